@@ -7,6 +7,7 @@ import (
 	"reflect"
 
 	"github.com/qor5/admin/presets"
+	"github.com/qor5/admin/presets/gorm2op"
 	"github.com/qor5/web"
 	"gorm.io/gorm"
 )
@@ -15,8 +16,12 @@ const (
 	Create = 1 << iota
 	Delete
 	Update
+	Restore
 )
 
+// HookOrder is the order of the presets hooks of the activity, see presets.ModelBuilder.Hook
+const HookOrder = 100
+
 type contextKey int
 
 const (
@@ -139,6 +144,9 @@ func (ab *ActivityBuilder) RegisterModels(models ...interface{}) *ActivityBuilde
 }
 
 // Model register a model and return model builder
+//
+// The changes of the presets.ModelBuilder are recorded by its AfterSave, AfterDelete and AfterRestore hooks,
+// so only EditingBuilder.Save and Delete record them, calling Editing().Saver or Deleter directly doesn't.
 func (ab *ActivityBuilder) RegisterModel(m interface{}) (mb *ModelBuilder) {
 	if m, exist := ab.GetModelBuilder(m); exist {
 		return m
@@ -167,48 +175,36 @@ func (ab *ActivityBuilder) RegisterModel(m interface{}) (mb *ModelBuilder) {
 	if presetModel, ok := m.(*presets.ModelBuilder); ok {
 		mb.presetModel = presetModel
 
-		var (
-			editing    = presetModel.Editing()
-			oldSaver   = editing.Saver
-			oldDeleter = editing.Deleter
-		)
-
-		editing.SaveFunc(func(obj interface{}, id string, ctx *web.EventContext) (err error) {
-			if mb.skip&Update != 0 && mb.skip&Create != 0 {
-				return oldSaver(obj, id, ctx)
-			}
-
-			old, ok := findOld(obj, ab.getDBFromContext(ctx.R.Context()))
-			if err = oldSaver(obj, id, ctx); err != nil {
-				return err
-			}
-
-			if (!ok || id == "") && mb.skip&Create == 0 {
-				return mb.AddRecords(ActivityCreate, ctx.R.Context(), obj)
+		// the changes are recorded after the hooks of the other modules with the default order
+		presetModel.Hook(presets.AfterSave, HookOrder, func(old interface{}, obj interface{}, ctx *web.EventContext) (err error) {
+			if old == nil {
+				if mb.skip&Create == 0 {
+					return mb.AddRecords(ActivityCreate, ctx.R.Context(), obj)
+				}
+				return
 			}
 
-			if ok && id != "" && mb.skip&Update == 0 {
+			if mb.skip&Update == 0 {
 				return mb.AddEditRecordWithOld(ab.getCreatorFromContext(ctx.R.Context()), old, obj, ab.getDBFromContext(ctx.R.Context()))
 			}
 
 			return
 		})
 
-		editing.DeleteFunc(func(obj interface{}, id string, ctx *web.EventContext) (err error) {
-			if mb.skip&Delete != 0 {
-				return oldDeleter(obj, id, ctx)
+		presetModel.Hook(presets.AfterDelete, HookOrder, func(old interface{}, obj interface{}, ctx *web.EventContext) (err error) {
+			if mb.skip&Delete != 0 || old == nil {
+				return
 			}
 
-			old, ok := findOldWithSlug(obj, id, ab.getDBFromContext(ctx.R.Context()))
-			if err = oldDeleter(obj, id, ctx); err != nil {
-				return err
-			}
+			return mb.AddRecords(ActivityDelete, ctx.R.Context(), old)
+		})
 
-			if ok {
-				return mb.AddRecords(ActivityDelete, ctx.R.Context(), old)
+		presetModel.Hook(presets.AfterRestore, HookOrder, func(old interface{}, obj interface{}, ctx *web.EventContext) (err error) {
+			if mb.skip&Restore != 0 {
+				return
 			}
 
-			return
+			return mb.AddRecords(ActivityRestore, ctx.R.Context(), obj)
 		})
 	}
 
@@ -340,8 +336,11 @@ func (ab *ActivityBuilder) AddEditRecordWithOldAndContext(ctx context.Context, o
 	return fmt.Errorf("can't find model builder for %v", now)
 }
 
-// GetDB get db from context
+// GetDB get db from context, the transaction of the presets change goes first
 func (ab *ActivityBuilder) getDBFromContext(ctx context.Context) *gorm.DB {
+	if tx, ok := gorm2op.TxFromContext(ctx); ok {
+		return tx
+	}
 	if contextdb := ctx.Value(ab.dbContextKey); contextdb != nil {
 		return contextdb.(*gorm.DB)
 	}
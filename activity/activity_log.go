@@ -5,10 +5,11 @@ import (
 )
 
 const (
-	ActivityView   = "View"
-	ActivityEdit   = "Edit"
-	ActivityCreate = "Create"
-	ActivityDelete = "Delete"
+	ActivityView    = "View"
+	ActivityEdit    = "Edit"
+	ActivityCreate  = "Create"
+	ActivityDelete  = "Delete"
+	ActivityRestore = "Restore"
 )
 
 type CreatorInterface interface {
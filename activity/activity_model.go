@@ -104,6 +104,18 @@ func (mb *ModelBuilder) SkipDelete() *ModelBuilder {
 	return mb
 }
 
+// SkipRestore skip the restore action for preset.ModelBuilder
+func (mb *ModelBuilder) SkipRestore() *ModelBuilder {
+	if mb.presetModel == nil {
+		return mb
+	}
+
+	if mb.skip&Restore == 0 {
+		mb.skip |= Restore
+	}
+	return mb
+}
+
 
 // EnableActivityInfoTab enable activity info tab on the given model's editing page
 func (mb *ModelBuilder) EnableActivityInfoTab() *ModelBuilder {
@@ -231,6 +243,13 @@ func (mb *ModelBuilder) AddRecords(action string, ctx context.Context, vs ...int
 				return err
 			}
 		}
+	case ActivityRestore:
+		for _, v := range vs {
+			err := mb.AddRestoreRecord(creator, v, db)
+			if err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -263,6 +282,11 @@ func (mb *ModelBuilder) AddDeleteRecord(creator interface{}, v interface{}, db *
 	return mb.save(creator, ActivityDelete, v, db, "")
 }
 
+// AddRestoreRecord add restore record
+func (mb *ModelBuilder) AddRestoreRecord(creator interface{}, v interface{}, db *gorm.DB) error {
+	return mb.save(creator, ActivityRestore, v, db, "")
+}
+
 // AddSaverRecord will save a create log or a edit log
 func (mb *ModelBuilder) AddSaveRecord(creator interface{}, now interface{}, db *gorm.DB) error {
 	old, ok := findOld(now, db)
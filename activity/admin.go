@@ -140,6 +140,10 @@ func (ab *ActivityBuilder) configureAdmin(b *presets.Builder) {
 				Label: msgr.ActionDelete,
 				Query: url.Values{"action": []string{ActivityDelete}},
 			},
+			{
+				Label: msgr.ActionRestore,
+				Query: url.Values{"action": []string{ActivityRestore}},
+			},
 		}
 	})
 
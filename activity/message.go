@@ -1,12 +1,13 @@
 package activity
 
 type Messages struct {
-	Activities   string
-	ActionAll    string
-	ActionView   string
-	ActionEdit   string
-	ActionCreate string
-	ActionDelete string
+	Activities    string
+	ActionAll     string
+	ActionView    string
+	ActionEdit    string
+	ActionCreate  string
+	ActionDelete  string
+	ActionRestore string
 
 	ModelUserID    string
 	ModelCreatedAt string
@@ -34,12 +35,13 @@ type Messages struct {
 }
 
 var Messages_en_US = &Messages{
-	Activities:   "Activities",
-	ActionAll:    "All",
-	ActionView:   "View",
-	ActionEdit:   "Edit",
-	ActionCreate: "Create",
-	ActionDelete: "Delete",
+	Activities:    "Activities",
+	ActionAll:     "All",
+	ActionView:    "View",
+	ActionEdit:    "Edit",
+	ActionCreate:  "Create",
+	ActionDelete:  "Delete",
+	ActionRestore: "Restore",
 
 	ModelUserID:    "Creator ID",
 	ModelCreatedAt: "Date Time",
@@ -67,12 +69,13 @@ var Messages_en_US = &Messages{
 }
 
 var Messages_zh_CN = &Messages{
-	Activities:   "活动",
-	ActionAll:    "全部",
-	ActionView:   "查看",
-	ActionEdit:   "编辑",
-	ActionCreate: "创建",
-	ActionDelete: "删除",
+	Activities:    "活动",
+	ActionAll:     "全部",
+	ActionView:    "查看",
+	ActionEdit:    "编辑",
+	ActionCreate:  "创建",
+	ActionDelete:  "删除",
+	ActionRestore: "恢复",
 
 	ModelUserID:    "操作者ID",
 	ModelCreatedAt: "日期时间",
@@ -36,6 +36,7 @@ import (
 	"github.com/qor5/admin/role"
 	"github.com/qor5/admin/slug"
 	"github.com/qor5/admin/utils"
+	"github.com/qor5/admin/webhook"
 	"github.com/qor5/admin/worker"
 	v "github.com/qor5/ui/vuetify"
 	vx "github.com/qor5/ui/vuetifyx"
@@ -227,7 +228,12 @@ func NewConfig() Config {
 
 	mListing := m.Listing("ID", "Title", "TitleWithSlug", "HeroImage", "Body").
 		SearchColumns("title", "body").
+		FullTextSearch(true).
 		PerPage(10)
+	mListing.Trash()
+	mListing.GlobalSearch().TitleFunc(func(obj interface{}, ctx *web.EventContext) string {
+		return obj.(*models.Post).Title
+	})
 
 	mListing.FilterDataFunc(func(ctx *web.EventContext) vx.FilterData {
 		u := getCurrentUser(ctx.R)
@@ -401,7 +407,14 @@ func NewConfig() Config {
 
 	pageBuilder := example.ConfigPageBuilder(db, "/page_builder", ``, b.I18n())
 	pm := pageBuilder.Configure(b, db, l10nBuilder, ab, publisher, seoBuilder)
+	pageBuilder.EditLease(gorm2op.EditLeaseStore(db, func(ctx *web.EventContext) (id string, name string) {
+		if u := getCurrentUser(ctx.R); u != nil {
+			return fmt.Sprint(u.ID), u.Name
+		}
+		return
+	}), presets.DefaultEditLeaseTTL)
 	pmListing := pm.Listing()
+	pmListing.ScheduleCalendar()
 	pmListing.FilterDataFunc(func(ctx *web.EventContext) vx.FilterData {
 		u := getCurrentUser(ctx.R)
 
@@ -509,6 +522,7 @@ func NewConfig() Config {
 
 	ab.RegisterModel(m).EnableActivityInfoTab()
 	ab.RegisterModels(l)
+	webhook.New(db).Worker(w).Activity(ab).RegisterModels(m, product, category).Configure(b)
 	mm := b.Model(&models.MicrositeModel{})
 	mm.Listing("ID", "Name", "PrePath", "Status").
 		SearchColumns("ID::text", "Name").
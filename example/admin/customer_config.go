@@ -55,4 +55,10 @@ func configNestedFieldDemo(b *presets.Builder, db *gorm.DB) {
 		}
 		return gorm2op.DataOperator(db.Session(&gorm.Session{FullSaveAssociations: true})).Save(obj, id, ctx)
 	})
+
+	gorm2op.DataOperator(db).Duplicate(cust, "Addresses", "MembershipCard").
+		CopyFunc(func(from interface{}, to interface{}, ctx *web.EventContext) (err error) {
+			to.(*models.Customer).Name += " (copy)"
+			return
+		})
 }
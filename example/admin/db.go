@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/qor5/admin/example/models"
+	"github.com/qor5/admin/presets/gorm2op"
 	"github.com/qor5/admin/role"
 	"github.com/qor5/x/perm"
 	"gorm.io/driver/postgres"
@@ -41,5 +42,8 @@ func ConnectDB() *gorm.DB {
 	); err != nil {
 		panic(err)
 	}
+	if err = gorm2op.PostgresFullTextIndex(db, &models.Post{}, "simple", "title", "body"); err != nil {
+		panic(err)
+	}
 	return db
 }
@@ -1,10 +1,12 @@
 package admin
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/qor5/admin/example/models"
 	"github.com/qor5/admin/presets"
+	"github.com/qor5/admin/presets/gorm2op"
 	"github.com/qor5/ui/vuetify"
 	"github.com/qor5/ui/vuetifyx"
 	"github.com/qor5/web"
@@ -36,6 +38,15 @@ func configOrder(pb *presets.Builder, db *gorm.DB) {
 		StatusAttr,
 		SourceAttr,
 	)
+	lb.Aggregate(OrderCodeAttr, presets.AggregateCount).
+		Aggregate(CreatedDateAttr, presets.AggregateMin).
+		Aggregate(CreatedDateAttr, presets.AggregateMax)
+
+	kanban := lb.Kanban(StatusAttr)
+	for _, status := range models.OrderStatuses {
+		kanban.Column(string(status), string(status))
+	}
+	lb.Calendar(CreatedDateAttr, "created_at")
 
 	lb.Field(CreatedDateAttr).ComponentFunc(func(obj interface{}, field *presets.FieldContext, ctx *web.EventContext) h.HTMLComponent {
 		return h.Td(h.Text(field.Value(obj).(time.Time).Local().Format("2006-01-02 15:04:05")))
@@ -81,12 +92,15 @@ func configOrder(pb *presets.Builder, db *gorm.DB) {
 		}
 	})
 
-	lb.Action("Export").ButtonCompFunc(func(ctx *web.EventContext) h.HTMLComponent {
-		return vuetify.VBtn("Export").
-			Color("primary").
-			Depressed(true).
-			Class("ml-2").
-			Href(exportOrdersURL)
+	lb.SavedViews(gorm2op.ListingViewStore(db, func(ctx *web.EventContext) string {
+		if u := getCurrentUser(ctx.R); u != nil {
+			return fmt.Sprint(u.ID)
+		}
+		return ""
+	}))
+
+	lb.Export().FieldFormatFunc(CreatedDateAttr, func(obj interface{}, field *presets.FieldContext, ctx *web.EventContext) string {
+		return obj.(*models.Order).CreatedAt.Local().Format("2006-01-02 15:04:05")
 	})
 
 	lb.BulkAction("Change status").ComponentFunc(func(selectedIds []string, ctx *web.EventContext) h.HTMLComponent {
@@ -24,6 +24,12 @@ func configProduct(b *presets.Builder, db *gorm.DB, wb *worker.Builder) *presets
 	eb := p.Editing("StatusBar", "ScheduleBar", "Code", "Name", "Price", "Image")
 	listing := p.Listing("Code", "Name", "Price", "Image").SearchColumns("Code", "Name").SelectableColumns(true)
 	listing.ActionsAsMenu(true)
+	listing.Field("Price").Editable()
+	listing.Filters("Code", "Name", "Price", "CreatedAt")
+	listing.BulkEdit("Price")
+	listing.GlobalSearch().TitleFunc(func(obj interface{}, ctx *web.EventContext) string {
+		return obj.(*models.Product).Name
+	})
 
 	noParametersJob := wb.ActionJob(
 		"No parameters",
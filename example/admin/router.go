@@ -15,8 +15,6 @@ var favicon []byte
 
 const (
 	logoutURL = "/auth/logout"
-
-	exportOrdersURL = "/export-orders"
 )
 
 func Router() http.Handler {
@@ -50,8 +48,6 @@ func Router() http.Handler {
 		return
 	})
 
-	mux.Handle(exportOrdersURL, exportOrders(db))
-
 	// example of sitemap and robot
 	sitemap.SiteMap("product").RegisterRawString("https://dev.qor5.com/admin", "/product").MountTo(mux)
 	robot := sitemap.Robots()
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"net/url"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/qor5/admin/activity"
 	"github.com/qor5/admin/l10n"
 	"github.com/qor5/admin/presets"
+	"github.com/qor5/admin/presets/gorm2op"
 	"github.com/qor5/admin/utils"
 	v "github.com/qor5/ui/vuetify"
 	vx "github.com/qor5/ui/vuetifyx"
@@ -21,6 +23,10 @@ import (
 )
 
 const WrapHandlerKey = "l10nWrapHandlerKey"
+
+// HookOrder is the order of the presets hooks of l10n, after the ones of publish that restore the other versions
+// of the record, and before the ones of activity that record the restored record.
+const HookOrder = 50
 const MenuTopItemFunc = "l10nMenuTopItemFunc"
 
 func Configure(b *presets.Builder, db *gorm.DB, lb *l10n.Builder, ab *activity.ActivityBuilder, models ...*presets.ModelBuilder) {
@@ -62,11 +68,12 @@ func Configure(b *presets.Builder, db *gorm.DB, lb *l10n.Builder, ab *activity.A
 			}
 		})
 
-		deleter := m.Editing().Deleter
-		m.Editing().DeleteFunc(func(obj interface{}, id string, ctx *web.EventContext) (err error) {
-			if err = deleter(obj, id, ctx); err != nil {
+		// the locale code of the deleted record is changed, so that the record can be localized to the locale again
+		m.Hook(presets.AfterDelete, 0, func(old interface{}, _ interface{}, ctx *web.EventContext) (err error) {
+			if old == nil {
 				return
 			}
+			obj, id := old, old.(presets.SlugEncoder).PrimarySlug()
 			locale := obj.(presets.SlugDecoder).PrimaryColumnValuesBySlug(id)["locale_code"]
 			locale = fmt.Sprintf("%s(del:%d)", locale, time.Now().UnixMilli())
 
@@ -75,9 +82,38 @@ func Configure(b *presets.Builder, db *gorm.DB, lb *l10n.Builder, ab *activity.A
 				withoutKeys = append(withoutKeys, "version")
 			}
 
-			if err = utils.PrimarySluggerWhere(db.Unscoped(), obj, id, withoutKeys...).Update("locale_code", locale).Error; err != nil {
+			if err = utils.PrimarySluggerWhere(dbFromContext(db, ctx).Unscoped(), obj, id, withoutKeys...).Update("locale_code", locale).Error; err != nil {
+				return
+			}
+			return
+		})
+
+		// the locale code of the restored record is changed back, unless the record is localized to the locale again
+		mb := m
+		m.Hook(presets.AfterRestore, HookOrder, func(old interface{}, obj interface{}, ctx *web.EventContext) (err error) {
+			deleted := obj.(l10n.L10nInterface).GetLocale()
+			i := strings.Index(deleted, "(del:")
+			if i < 0 {
+				return
+			}
+			locale := deleted[:i]
+			id := obj.(presets.SlugEncoder).PrimarySlug()
+			tx := dbFromContext(db, ctx)
+
+			var count int64
+			if err = utils.PrimarySluggerWhere(tx, mb.NewModel(), id, "version", "locale_code").
+				Where("locale_code = ?", locale).
+				Count(&count).Error; err != nil {
+				return
+			}
+			if count > 0 {
+				return fmt.Errorf("the record can't be restored, it's localized to %s again", locale)
+			}
+
+			if err = utils.PrimarySluggerWhere(tx.Unscoped(), mb.NewModel(), id, "version").Update("locale_code", locale).Error; err != nil {
 				return
 			}
+			obj.(l10n.L10nInterface).SetLocale(locale)
 			return
 		})
 
@@ -120,6 +156,14 @@ func Configure(b *presets.Builder, db *gorm.DB, lb *l10n.Builder, ab *activity.A
 		RegisterForModule(language.Japanese, I18nLocalizeKey, Messages_ja_JP)
 }
 
+// dbFromContext returns the transaction of the presets change, so that the hooks don't wait for the locks of it.
+func dbFromContext(db *gorm.DB, ctx *web.EventContext) *gorm.DB {
+	if tx, ok := gorm2op.TxFromContext(ctx.R.Context()); ok {
+		return tx
+	}
+	return db
+}
+
 func localeListFunc(db *gorm.DB, lb *l10n.Builder) func(obj interface{}, field *presets.FieldContext, ctx *web.EventContext) h.HTMLComponent {
 	return func(obj interface{}, field *presets.FieldContext, ctx *web.EventContext) h.HTMLComponent {
 		id, err := reflectutils.Get(obj, "ID")
@@ -197,7 +197,7 @@ func doLocalizeTo(db *gorm.DB, mb *presets.ModelBuilder, lb *l10n.Builder, ab *a
 			newContext = context.WithValue(newContext, FromLocale, fromLocale)
 			ctx.R = ctx.R.WithContext(newContext)
 
-			if err = me.Saver(toObj, toParamID, ctx); err != nil {
+			if err = me.Save(toObj, toParamID, ctx); err != nil {
 				return
 			}
 			toObjs = append(toObjs, toObj)
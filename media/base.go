@@ -20,6 +20,7 @@ import (
 	"github.com/gosimple/slug"
 	"github.com/iancoleman/strcase"
 	"github.com/jinzhu/inflection"
+	"github.com/qor5/admin/presets"
 	"gorm.io/gorm"
 	"gorm.io/gorm/schema"
 )
@@ -194,14 +195,15 @@ func getFuncMap(db *gorm.DB, field *schema.Field, filename string) template.Func
 	}
 }
 
-// GetURL get default URL for a model based on its options
+// GetURL get default URL for a model based on its options,
+// the URL is put into the directory of the tenant of the context of db, see presets.TenantPath
 func (b Base) GetURL(option *Option, db *gorm.DB, field *schema.Field, templater URLTemplater) string {
 	if path := templater.GetURLTemplate(option); path != "" {
 		tmpl := template.New("").Funcs(getFuncMap(db, field, b.GetFileName()))
 		if tmpl, err := tmpl.Parse(path); err == nil {
 			var result = bytes.NewBufferString("")
 			if err := tmpl.Execute(result, db.Statement.Dest); err == nil {
-				return result.String()
+				return presets.TenantPath(presets.TenantFromContext(db.Statement.Context), result.String())
 			}
 		}
 	}
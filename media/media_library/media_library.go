@@ -18,6 +18,8 @@ var QorPreviewMaxSize = 200
 
 type MediaLibrary struct {
 	gorm.Model
+	// TenantID is the tenant of the file if the admin has tenants, see presets.Builder.TenantResolver
+	TenantID     string `gorm:"index"`
 	SelectedType string
 	File         MediaLibraryStorage `sql:"size:4294967295;" mediaLibrary:"url:/system/{{class}}/{{primary_key}}/{{column}}.{{extension}}"`
 }
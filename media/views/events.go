@@ -1,6 +1,8 @@
 package views
 
 import (
+	"github.com/qor5/admin/media/media_library"
+	"github.com/qor5/admin/presets"
 	"github.com/qor5/web"
 	"gorm.io/gorm"
 )
@@ -20,15 +22,33 @@ const (
 )
 
 func registerEventFuncs(hub web.EventFuncHub, db *gorm.DB) {
-	hub.RegisterEventFunc(openFileChooserEvent, fileChooser(db))
+	hub.RegisterEventFunc(openFileChooserEvent, withTenant(db, fileChooser))
 	hub.RegisterEventFunc(deleteFileEvent, deleteFileField())
-	hub.RegisterEventFunc(cropImageEvent, cropImage(db))
-	hub.RegisterEventFunc(loadImageCropperEvent, loadImageCropper(db))
-	hub.RegisterEventFunc(imageSearchEvent, searchFile(db))
-	hub.RegisterEventFunc(imageJumpPageEvent, jumpPage(db))
-	hub.RegisterEventFunc(uploadFileEvent, uploadFile(db))
-	hub.RegisterEventFunc(chooseFileEvent, chooseFile(db))
-	hub.RegisterEventFunc(updateDescriptionEvent, updateDescription(db))
-	hub.RegisterEventFunc(deleteConfirmationEvent, deleteConfirmation(db))
-	hub.RegisterEventFunc(doDeleteEvent, doDelete(db))
+	hub.RegisterEventFunc(cropImageEvent, withTenant(db, cropImage))
+	hub.RegisterEventFunc(loadImageCropperEvent, withTenant(db, loadImageCropper))
+	hub.RegisterEventFunc(imageSearchEvent, withTenant(db, searchFile))
+	hub.RegisterEventFunc(imageJumpPageEvent, withTenant(db, jumpPage))
+	hub.RegisterEventFunc(uploadFileEvent, withTenant(db, uploadFile))
+	hub.RegisterEventFunc(chooseFileEvent, withTenant(db, chooseFile))
+	hub.RegisterEventFunc(updateDescriptionEvent, withTenant(db, updateDescription))
+	hub.RegisterEventFunc(deleteConfirmationEvent, withTenant(db, deleteConfirmation))
+	hub.RegisterEventFunc(doDeleteEvent, withTenant(db, doDelete))
+}
+
+// withTenant builds the event func with the db of the tenant of every request, see tenantDB.
+func withTenant(db *gorm.DB, f func(db *gorm.DB) web.EventFunc) web.EventFunc {
+	return func(ctx *web.EventContext) (r web.EventResponse, err error) {
+		return f(tenantDB(db, ctx))(ctx)
+	}
+}
+
+// tenantDB scopes the media library to the tenant of the request, see presets.Builder.TenantResolver,
+// and the files uploaded with the db are stored in the directory of the tenant, see media.Base.GetURL.
+func tenantDB(db *gorm.DB, ctx *web.EventContext) *gorm.DB {
+	db = db.WithContext(ctx.R.Context())
+	if tenant := presets.TenantFromContext(ctx.R.Context()); tenant != "" {
+		// the new session keeps the condition from the conditions of the following queries
+		db = db.Where(&media_library.MediaLibrary{TenantID: tenant}).Session(&gorm.Session{})
+	}
+	return db
 }
@@ -390,7 +390,7 @@ func uploadFile(db *gorm.DB) web.EventFunc {
 		var uf uploadFiles
 		ctx.MustUnmarshalForm(&uf)
 		for _, fh := range uf.NewFiles {
-			m := media_library.MediaLibrary{}
+			m := media_library.MediaLibrary{TenantID: presets.TenantFromContext(ctx.R.Context())}
 
 			if media.IsImageFormat(fh.Filename) {
 				m.SelectedType = media_library.ALLOW_TYPE_IMAGE
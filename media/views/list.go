@@ -26,7 +26,7 @@ func configList(b *presets.Builder, db *gorm.DB) {
 					Type("hidden").
 					Value(keyword).
 					Attr(web.VFieldName(searchKeywordName(mediaLibraryListField))...),
-				fileChooserDialogContent(db, mediaLibraryListField, ctx, &media_library.MediaBoxConfig{}),
+				fileChooserDialogContent(tenantDB(db, ctx), mediaLibraryListField, ctx, &media_library.MediaBoxConfig{}),
 			).Name(dialogContentPortalName(mediaLibraryListField)),
 		)
 		return
@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/qor5/admin/activity"
 	"github.com/qor5/admin/l10n"
@@ -80,6 +81,8 @@ type Builder struct {
 	publishBtnColor   string
 	duplicateBtnColor string
 	templateEnabled   bool
+	leaseStore        presets.EditLeaseStore
+	leaseTTL          time.Duration
 }
 
 const (
@@ -218,6 +221,9 @@ func (b *Builder) Configure(pb *presets.Builder, db *gorm.DB, l10nB *l10n.Builde
 	}
 
 	b.mb = pm
+	if b.leaseStore != nil {
+		pm.Editing().EditLease(b.leaseStore, b.leaseTTL)
+	}
 	lb := pm.Listing("ID", "Online", "Title", "Path")
 	lb.Field("Path").ComponentFunc(func(obj interface{}, field *presets.FieldContext, ctx *web.EventContext) h.HTMLComponent {
 		page := obj.(*Page)
@@ -1215,7 +1221,7 @@ func schedulePublish(db *gorm.DB, mb *presets.ModelBuilder) web.EventFunc {
 			mb.Editing().UpdateOverlayContent(ctx, &r, obj, "", err)
 			return
 		}
-		err = mb.Editing().Saver(obj, paramID, ctx)
+		err = mb.Editing().Save(obj, paramID, ctx)
 		if err != nil {
 			mb.Editing().UpdateOverlayContent(ctx, &r, obj, "", err)
 			return
@@ -1358,7 +1364,7 @@ func updateSEO(db *gorm.DB, mb *presets.ModelBuilder) web.EventFunc {
 			mb.Editing().UpdateOverlayContent(ctx, &r, obj, "", err)
 			return
 		}
-		err = mb.Editing().Saver(obj, paramID, ctx)
+		err = mb.Editing().Save(obj, paramID, ctx)
 		if err != nil {
 			mb.Editing().UpdateOverlayContent(ctx, &r, obj, "", err)
 			return
@@ -1422,7 +1428,7 @@ func renameVersion(mb *presets.ModelBuilder) web.EventFunc {
 			return
 		}
 
-		if err = mb.Editing().Saver(obj, paramID, ctx); err != nil {
+		if err = mb.Editing().Save(obj, paramID, ctx); err != nil {
 			return
 		}
 		qs := ctx.Queries()
@@ -1821,6 +1827,14 @@ func (b *ContainerBuilder) Model(m interface{}) *ContainerBuilder {
 
 	b.modelType = val.Elem().Type()
 
+	if b.builder.leaseStore != nil {
+		b.mb.Editing().EditLease(b.builder.leaseStore, b.builder.leaseTTL)
+	}
+	b.mb.Hook(presets.BeforeSave, 0, func(old interface{}, obj interface{}, ctx *web.EventContext) (err error) {
+		v, _ := reflectutils.Get(obj, "ID")
+		id, _ := v.(uint)
+		return b.builder.checkContainerModelPageEditLease(ctx, b.name, id)
+	})
 	b.configureRelatedOnlinePagesTab()
 	return b
 }
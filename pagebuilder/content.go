@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 
+	"github.com/qor5/admin/presets"
 	"github.com/qor5/admin/publish"
 	. "github.com/qor5/ui/vuetify"
 	"github.com/qor5/web"
@@ -39,7 +40,8 @@ func (b *Builder) PageContent(ctx *web.EventContext) (r web.PageResponse, err er
 			// deviceQueries.Add("locale", locale)
 		}
 	}
-	body, p, err = b.renderPageOrTemplate(ctx, isTpl, id, version, locale, true)
+	var lease *presets.EditLease
+	body, p, lease, err = b.renderPageOrTemplate(ctx, isTpl, id, version, locale, true)
 	if err != nil {
 		return
 	}
@@ -53,13 +55,16 @@ func (b *Builder) PageContent(ctx *web.EventContext) (r web.PageResponse, err er
 		activeDevice = 2
 	}
 
-	containerList, err = b.renderContainersList(ctx, p.ID, p.GetVersion(), p.GetLocale(), p.GetStatus() != publish.StatusDraft)
+	containerList, err = b.renderContainersList(ctx, p.ID, p.GetVersion(), p.GetLocale(), p.GetStatus() != publish.StatusDraft || (lease != nil && !lease.Owned))
 	if err != nil {
 		return
 	}
 	// msgr := i18n.MustGetModuleMessages(ctx.R, I18nPageBuilderKey, Messages_en_US).(*Messages)
 	r.Body = h.Components(
-		VContainer(web.Portal(body).Name(editorPreviewContentPortal)).
+		VContainer(
+			b.pageEditLeaseBanner(ctx, p, lease),
+			web.Portal(body).Name(editorPreviewContentPortal),
+		).
 			Class("mt-6").
 			Fluid(true),
 		VNavigationDrawer(
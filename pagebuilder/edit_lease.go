@@ -0,0 +1,80 @@
+package pagebuilder
+
+import (
+	"time"
+
+	"github.com/qor5/admin/presets"
+	"github.com/qor5/web"
+	h "github.com/theplant/htmlgo"
+)
+
+// EditLease makes the editors of the draft pages and the drawers of the containers acquire the edit leases,
+// see presets.EditingBuilder.EditLease. The containers of the page that is being edited by someone else
+// are read-only, and their changes like moving and deleting are rejected.
+func (b *Builder) EditLease(store presets.EditLeaseStore, ttl time.Duration) (r *Builder) {
+	b.leaseStore = store
+	b.leaseTTL = ttl
+	if b.mb != nil {
+		b.mb.Editing().EditLease(store, ttl)
+	}
+	for _, cb := range b.containerBuilders {
+		if cb.mb != nil {
+			cb.mb.Editing().EditLease(store, ttl)
+		}
+	}
+	return b
+}
+
+func (b *Builder) acquirePageEditLease(ctx *web.EventContext, p *Page) (lease *presets.EditLease, err error) {
+	if b.mb == nil || b.leaseStore == nil {
+		return
+	}
+	return b.mb.Editing().AcquireEditLease(p.PrimarySlug(), false, ctx)
+}
+
+func (b *Builder) pageEditLeaseBanner(ctx *web.EventContext, p *Page, lease *presets.EditLease) h.HTMLComponent {
+	if b.mb == nil || lease == nil {
+		return nil
+	}
+	return b.mb.Editing().EditLeaseBanner(p.PrimarySlug(), lease, ctx)
+}
+
+func (b *Builder) checkPageEditLease(ctx *web.EventContext, pageID uint, pageVersion, locale string) (err error) {
+	if b.mb == nil || b.leaseStore == nil || pageVersion == templateVersion {
+		return
+	}
+	p := &Page{}
+	p.ID = pageID
+	p.Version.Version = pageVersion
+	p.LocaleCode = locale
+	return b.mb.Editing().CheckEditLease(p.PrimarySlug(), ctx)
+}
+
+func (b *Builder) checkContainerPageEditLease(ctx *web.EventContext, containerID, locale string) (err error) {
+	if b.mb == nil || b.leaseStore == nil {
+		return
+	}
+	var c Container
+	if err = b.db.First(&c, "id = ? AND locale_code = ?", containerID, locale).Error; err != nil {
+		return
+	}
+	return b.checkPageEditLease(ctx, c.PageID, c.PageVersion, c.LocaleCode)
+}
+
+// checkContainerModelPageEditLease checks the leases of the pages of the container, the drawer of the container
+// holds the lease of the container model, not the one of the page.
+func (b *Builder) checkContainerModelPageEditLease(ctx *web.EventContext, containerName string, modelID uint) (err error) {
+	if b.mb == nil || b.leaseStore == nil || modelID == 0 {
+		return
+	}
+	var cs []*Container
+	if err = b.db.Where("model_name = ? AND model_id = ?", containerName, modelID).Find(&cs).Error; err != nil {
+		return
+	}
+	for _, c := range cs {
+		if err = b.checkPageEditLease(ctx, c.PageID, c.PageVersion, c.LocaleCode); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -59,7 +59,7 @@ func (b *Builder) Preview(ctx *web.EventContext) (r web.PageResponse, err error)
 	locale := ctx.R.FormValue("locale")
 
 	var p *Page
-	r.Body, p, err = b.renderPageOrTemplate(ctx, isTpl, id, version, locale, false)
+	r.Body, p, _, err = b.renderPageOrTemplate(ctx, isTpl, id, version, locale, false)
 	if err != nil {
 		return
 	}
@@ -98,14 +98,15 @@ func (b *Builder) Editor(ctx *web.EventContext) (r web.PageResponse, err error)
 		}
 	}
 
-	body, p, err = b.renderPageOrTemplate(ctx, isTpl, id, version, locale, true)
+	var lease *presets.EditLease
+	body, p, lease, err = b.renderPageOrTemplate(ctx, isTpl, id, version, locale, true)
 	if err != nil {
 		return
 	}
 	r.PageTitle = fmt.Sprintf("Editor for %s: %s", id, p.Title)
 	device, _ = b.getDevice(ctx)
 
-	containerList, err = b.renderContainersList(ctx, p.ID, p.GetVersion(), p.GetLocale(), p.GetStatus() != publish.StatusDraft)
+	containerList, err = b.renderContainersList(ctx, p.ID, p.GetVersion(), p.GetLocale(), p.GetStatus() != publish.StatusDraft || (lease != nil && !lease.Owned))
 	if err != nil {
 		return
 	}
@@ -139,7 +140,10 @@ func (b *Builder) Editor(ctx *web.EventContext) (r web.PageResponse, err error)
 			App(true),
 
 		VMain(
-			VContainer(web.Portal(body).Name(editorPreviewContentPortal)).
+			VContainer(
+				b.pageEditLeaseBanner(ctx, p, lease),
+				web.Portal(body).Name(editorPreviewContentPortal),
+			).
 				Class("mt-6").
 				Fluid(true),
 			VNavigationDrawer(containerList).
@@ -176,7 +180,7 @@ func (b *Builder) getDevice(ctx *web.EventContext) (device string, style string)
 
 const ContainerToPageLayoutKey = "ContainerToPageLayout"
 
-func (b *Builder) renderPageOrTemplate(ctx *web.EventContext, isTpl bool, pageOrTemplateID string, version, locale string, isEditor bool) (r h.HTMLComponent, p *Page, err error) {
+func (b *Builder) renderPageOrTemplate(ctx *web.EventContext, isTpl bool, pageOrTemplateID string, version, locale string, isEditor bool) (r h.HTMLComponent, p *Page, lease *presets.EditLease, err error) {
 	if isTpl {
 		tpl := &Template{}
 		err = b.db.First(tpl, "id = ? and locale_code = ?", pageOrTemplateID, locale).Error
@@ -196,6 +200,12 @@ func (b *Builder) renderPageOrTemplate(ctx *web.EventContext, isTpl bool, pageOr
 	if p.GetStatus() != publish.StatusDraft && isEditor {
 		isReadonly = true
 	}
+	if !isReadonly && isEditor && !isTpl {
+		if lease, err = b.acquirePageEditLease(ctx, p); err != nil {
+			return
+		}
+		isReadonly = lease != nil && !lease.Owned
+	}
 
 	var comps []h.HTMLComponent
 	comps, err = b.renderContainers(ctx, p, isEditor, isReadonly)
@@ -528,6 +538,9 @@ func (b *Builder) AddContainer(ctx *web.EventContext) (r web.EventResponse, err
 	containerName := ctx.R.FormValue(paramContainerName)
 	sharedContainer := ctx.R.FormValue(paramSharedContainer)
 	modelID := ctx.QueryAsInt(paramModelID)
+	if err = b.checkPageEditLease(ctx, uint(pageID), pageVersion, locale); err != nil {
+		return
+	}
 	var newModelID uint
 	if sharedContainer == "true" {
 		err = b.AddSharedContainerToPage(pageID, pageVersion, locale, containerName, uint(modelID))
@@ -553,6 +566,11 @@ func (b *Builder) MoveContainer(ctx *web.EventContext) (r web.EventResponse, err
 	if err != nil {
 		return
 	}
+	if len(result) > 0 {
+		if err = b.checkContainerPageEditLease(ctx, result[0].ContainerID, result[0].Locale); err != nil {
+			return
+		}
+	}
 	err = b.db.Transaction(func(tx *gorm.DB) (inerr error) {
 		for i, r := range result {
 			if inerr = tx.Model(&Container{}).Where("id = ? AND locale_code = ?", r.ContainerID, r.Locale).Update("display_order", i+1).Error; inerr != nil {
@@ -573,6 +591,10 @@ func (b *Builder) ToggleContainerVisibility(ctx *web.EventContext) (r web.EventR
 	containerID := cs["id"]
 	locale := cs["locale_code"]
 
+	if err = b.checkContainerPageEditLease(ctx, containerID, locale); err != nil {
+		return
+	}
+
 	err = b.db.Exec("UPDATE page_builder_containers SET hidden = NOT(coalesce(hidden,FALSE)) WHERE id = ? AND locale_code = ?", containerID, locale).Error
 
 	r.PushState = web.Location(url.Values{})
@@ -623,6 +645,10 @@ func (b *Builder) DeleteContainer(ctx *web.EventContext) (r web.EventResponse, e
 	containerID := cs["id"]
 	locale := cs["locale_code"]
 
+	if err = b.checkContainerPageEditLease(ctx, containerID, locale); err != nil {
+		return
+	}
+
 	err = b.db.Delete(&Container{}, "id = ? AND locale_code = ?", containerID, locale).Error
 	if err != nil {
 		return
@@ -861,6 +887,10 @@ func (b *Builder) MarkAsSharedContainer(ctx *web.EventContext) (r web.EventRespo
 	containerID := cs["id"]
 	locale := cs["locale_code"]
 
+	if err = b.checkContainerPageEditLease(ctx, containerID, locale); err != nil {
+		return
+	}
+
 	err = b.db.Model(&Container{}).Where("id = ? AND locale_code = ?", containerID, locale).Update("shared", true).Error
 	if err != nil {
 		return
@@ -881,6 +911,9 @@ func (b *Builder) RenameContainer(ctx *web.EventContext) (r web.EventResponse, e
 	if err != nil {
 		return
 	}
+	if err = b.checkPageEditLease(ctx, c.PageID, c.PageVersion, c.LocaleCode); err != nil {
+		return
+	}
 	if c.Shared {
 		err = b.db.Model(&Container{}).Where("model_name = ? AND model_id = ? AND locale_code = ?", c.ModelName, c.ModelID, locale).Update("display_name", name).Error
 		if err != nil {
@@ -17,6 +17,32 @@ const (
 	ReloadList           = "presets_ReloadList"
 	OpenListingDialog    = "presets_OpenListingDialog"
 	UpdateListingDialog  = "presets_UpdateListingDialog"
+	OpenImportDialog     = "presets_OpenImportDialog"
+	ImportDryRun         = "presets_ImportDryRun"
+	DoImport             = "presets_DoImport"
+
+	OpenSaveListingViewDialog = "presets_OpenSaveListingViewDialog"
+	SaveListingView           = "presets_SaveListingView"
+	DeleteListingView         = "presets_DeleteListingView"
+
+	Restore                       = "presets_Restore"
+	DeletePermanentlyConfirmation = "presets_DeletePermanentlyConfirmation"
+	DoDeletePermanently           = "presets_DoDeletePermanently"
+
+	UpdateListingCell = "presets_UpdateListingCell"
+
+	GlobalSearch = "presets_GlobalSearch"
+
+	RenewEditLease    = "presets_RenewEditLease"
+	TakeOverEditLease = "presets_TakeOverEditLease"
+	ReleaseEditLease  = "presets_ReleaseEditLease"
+
+	MoveKanbanCard = "presets_MoveKanbanCard"
+
+	LoadTreeChildren = "presets_LoadTreeChildren"
+	MoveTreeNode     = "presets_MoveTreeNode"
+
+	WizardStep = "presets_WizardStep"
 
 	// list editor
 	AddRowEvent    = "listEditor_addRowEvent"
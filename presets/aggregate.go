@@ -0,0 +1,108 @@
+package presets
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/qor5/web"
+	h "github.com/theplant/htmlgo"
+)
+
+type AggregateKind string
+
+const (
+	AggregateSum   AggregateKind = "sum"
+	AggregateAvg   AggregateKind = "avg"
+	AggregateMin   AggregateKind = "min"
+	AggregateMax   AggregateKind = "max"
+	AggregateCount AggregateKind = "count"
+)
+
+type Aggregate struct {
+	FieldName string
+	Kind      AggregateKind
+}
+
+// Aggregate adds the aggregate of the field to the footer of the listing, which is computed over all the records
+// matching the keyword and the filters, not only the current page. A field can have several aggregates.
+// By default the DataOperator is used if it implements Aggregator, but not with the SearchFunc,
+// whose conditions the DataOperator doesn't know, then the AggregateFunc is required.
+func (b *ListingBuilder) Aggregate(field string, kind AggregateKind) (r *ListingBuilder) {
+	b.aggregates = append(b.aggregates, &Aggregate{FieldName: field, Kind: kind})
+	return b
+}
+
+func (b *ListingBuilder) AggregateFunc(v AggregateFunc) (r *ListingBuilder) {
+	b.Aggregator = v
+	return b
+}
+
+func (b *ListingBuilder) aggregator() AggregateFunc {
+	if b.Aggregator != nil {
+		return b.Aggregator
+	}
+	if a, ok := b.mb.p.dataOperator.(Aggregator); ok && !b.customSearcher {
+		return a.Aggregate
+	}
+	return nil
+}
+
+// aggregatesRow renders the aggregates under the columns of their fields,
+// the first and the last cells are left for the checkboxes and the row menus.
+func (b *ListingBuilder) aggregatesRow(searchParams *SearchParams, columns []string, selectable bool, hasRowMenu bool, ctx *web.EventContext) h.HTMLComponent {
+	aggregator := b.aggregator()
+	if aggregator == nil {
+		panic("a DataOperator implementing presets.Aggregator or Listing().AggregateFunc(...) required, the AggregateFunc is required with the SearchFunc")
+	}
+	values, err := aggregator(b.mb.NewModelSlice(), searchParams, b.aggregates, ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	msgr := MustGetMessages(ctx.R)
+	labels := map[AggregateKind]string{
+		AggregateSum:   msgr.AggregateSum,
+		AggregateAvg:   msgr.AggregateAvg,
+		AggregateMin:   msgr.AggregateMin,
+		AggregateMax:   msgr.AggregateMax,
+		AggregateCount: msgr.AggregateCount,
+	}
+
+	var tds []h.HTMLComponent
+	if selectable {
+		tds = append(tds, h.Td())
+	}
+	for _, col := range columns {
+		var items []h.HTMLComponent
+		for i, a := range b.aggregates {
+			if a.FieldName != col || i >= len(values) {
+				continue
+			}
+			items = append(items, h.Div(
+				h.Span(labels[a.Kind]).Class("grey--text mr-1"),
+				h.Text(formatAggregateValue(values[i])),
+			).Class("text-no-wrap"))
+		}
+		tds = append(tds, h.Td(items...).Class("font-weight-medium"))
+	}
+	if hasRowMenu {
+		tds = append(tds, h.Td())
+	}
+	return h.Tr(tds...).Class("grey lighten-5")
+}
+
+func formatAggregateValue(v interface{}) string {
+	switch vv := v.(type) {
+	case nil:
+		return "-"
+	case float64:
+		return strconv.FormatFloat(math.Round(vv*100)/100, 'f', -1, 64)
+	case time.Time:
+		return vv.Local().Format("2006-01-02 15:04:05")
+	case []byte:
+		return string(vv)
+	}
+	return fmt.Sprint(v)
+}
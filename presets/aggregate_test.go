@@ -0,0 +1,76 @@
+package presets
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qor5/web"
+)
+
+type aggregateItem struct {
+	ID    uint
+	Name  string
+	Total float64
+}
+
+type aggregateOperator struct {
+	restAPIItemsOperator
+	params     *SearchParams
+	aggregates []*Aggregate
+}
+
+func (op *aggregateOperator) Aggregate(obj interface{}, params *SearchParams, aggregates []*Aggregate, ctx *web.EventContext) (r []interface{}, err error) {
+	op.params = params
+	op.aggregates = aggregates
+	return []interface{}{1234.5678, int64(3), nil}, nil
+}
+
+func TestListingAggregates(t *testing.T) {
+	op := &aggregateOperator{}
+	b := New().DataOperator(op)
+	lb := b.Model(&aggregateItem{}).Listing("ID", "Name", "Total").
+		SearchColumns("name").
+		Aggregate("Total", AggregateSum).
+		Aggregate("ID", AggregateCount).
+		Aggregate("Name", AggregateMin)
+	if lb.aggregator() == nil {
+		t.Fatalf("the aggregate func is not set from the data operator")
+	}
+
+	ctx := &web.EventContext{R: httptest.NewRequest("GET", "/aggregate-items?keyword=shoes&page=3", nil), W: httptest.NewRecorder()}
+	sp := lb.newSearchParams(ctx, 10)
+	row := lb.aggregatesRow(sp, []string{"ID", "Name", "Total"}, true, false, ctx)
+	if op.params == nil || op.params.Keyword != "shoes" || len(op.aggregates) != 3 {
+		t.Fatalf("params = %+v, aggregates = %d", op.params, len(op.aggregates))
+	}
+
+	content, _ := row.MarshalHTML(context.TODO())
+	for _, s := range []string{"Sum", "1234.57", "Count", "3", "Min", "-"} {
+		if !strings.Contains(string(content), s) {
+			t.Errorf("%s is not in the footer %s", s, content)
+		}
+	}
+	if c := strings.Count(string(content), "<td"); c != 4 {
+		t.Errorf("the footer has %d cells, want 4", c)
+	}
+}
+
+func TestListingAggregatesWithSearchFunc(t *testing.T) {
+	b := New().DataOperator(&aggregateOperator{})
+	lb := b.Model(&aggregateItem{}).Listing("ID", "Name", "Total").
+		Aggregate("Total", AggregateSum).
+		SearchFunc(func(model interface{}, params *SearchParams, ctx *web.EventContext) (r interface{}, totalCount int, err error) {
+			return
+		})
+	if lb.aggregator() != nil {
+		t.Fatalf("the data operator aggregates the records of the search func")
+	}
+	lb.AggregateFunc(func(obj interface{}, params *SearchParams, aggregates []*Aggregate, ctx *web.EventContext) (r []interface{}, err error) {
+		return
+	})
+	if lb.aggregator() == nil {
+		t.Fatalf("the aggregate func is not used")
+	}
+}
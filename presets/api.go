@@ -35,6 +35,31 @@ type DataOperator interface {
 	Delete(obj interface{}, id string, ctx *web.EventContext) (err error)
 }
 
+// TransactionFunc runs fc in a transaction, the data operations called with the ctx passed to fc join the transaction.
+type TransactionFunc func(ctx *web.EventContext, fc func(ctx *web.EventContext) error) (err error)
+
+// Transactor is implemented by the DataOperator that supports transactions
+type Transactor interface {
+	Transaction(ctx *web.EventContext, fc func(ctx *web.EventContext) error) (err error)
+}
+
+// Trasher is implemented by the DataOperator that supports soft deletes, see ListingBuilder.Trash
+type Trasher interface {
+	// SearchTrash searches the soft-deleted records
+	SearchTrash(obj interface{}, params *SearchParams, ctx *web.EventContext) (r interface{}, totalCount int, err error)
+	// FetchTrash fetches the soft-deleted record, or returns ErrRecordNotFound if it's not deleted
+	FetchTrash(obj interface{}, id string, ctx *web.EventContext) (r interface{}, err error)
+	Restore(obj interface{}, id string, ctx *web.EventContext) (err error)
+	DeletePermanently(obj interface{}, id string, ctx *web.EventContext) (err error)
+}
+
+// Aggregator is implemented by the DataOperator that computes the aggregates of the fields, see ListingBuilder.Aggregate
+type Aggregator interface {
+	// Aggregate computes the aggregates over all the records matching the keyword and the conditions of params,
+	// the paging and the order are ignored. The values are returned in the order of aggregates.
+	Aggregate(obj interface{}, params *SearchParams, aggregates []*Aggregate, ctx *web.EventContext) (r []interface{}, err error)
+}
+
 type SetterFunc func(obj interface{}, ctx *web.EventContext)
 type FieldSetterFunc func(obj interface{}, field *FieldContext, ctx *web.EventContext) (err error)
 type ValidateFunc func(obj interface{}, ctx *web.EventContext) (err web.ValidationErrors)
@@ -43,6 +68,8 @@ type SearchFunc func(model interface{}, params *SearchParams, ctx *web.EventCont
 type FetchFunc func(obj interface{}, id string, ctx *web.EventContext) (r interface{}, err error)
 type SaveFunc func(obj interface{}, id string, ctx *web.EventContext) (err error)
 type DeleteFunc func(obj interface{}, id string, ctx *web.EventContext) (err error)
+type RestoreFunc func(obj interface{}, id string, ctx *web.EventContext) (err error)
+type AggregateFunc func(obj interface{}, params *SearchParams, aggregates []*Aggregate, ctx *web.EventContext) (r []interface{}, err error)
 
 type SQLCondition struct {
 	Query string
@@ -57,6 +84,31 @@ type SearchParams struct {
 	Page           int64
 	OrderBy        string
 	PageURL        *url.URL
+	// Keyset is set in keyset pagination mode, then Page should be ignored, see ListingBuilder.KeysetPagination
+	Keyset *KeysetParams
+	// FullText searches the Keyword by the full-text search of the database instead of matching every column,
+	// see ListingBuilder.FullTextSearch
+	FullText bool
+	// OrderByRelevance orders the records by the relevance of the full-text search first, then by OrderBy
+	OrderByRelevance bool
+}
+
+// KeysetParams pages the records by the values of the order by columns instead of OFFSET.
+// Use Condition and OrderBySQL to build the query, the total count can be skipped unless WithCount is true.
+type KeysetParams struct {
+	// the last one is always the primary field, so that the order is unique
+	OrderBys []*KeysetOrderBy
+	// the values of the OrderBys of the record that the page starts after, empty for the first page
+	After []interface{}
+	// search the records before After for the previous page, the records are returned in the reversed order
+	Backward  bool
+	WithCount bool
+}
+
+type KeysetOrderBy struct {
+	FieldName string
+	DBColumn  string
+	Desc      bool
 }
 
 type SlugDecoder interface {
@@ -0,0 +1,204 @@
+package presets
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	. "github.com/qor5/ui/vuetify"
+	"github.com/qor5/web"
+	"github.com/qor5/x/i18n"
+	"github.com/qor5/x/perm"
+	h "github.com/theplant/htmlgo"
+)
+
+const bulkEditActionName = "BulkEdit"
+
+// BulkEdit adds the bulk action that sets the fields of the selected records, the user picks the fields
+// and enters the values with the components of the editing fields. Every record is fetched, unmarshalled,
+// validated and saved by the EditingBuilder one by one, so the save hooks (like activity) apply
+// to each record. The fields are the editing fields without the nested ones if vs is empty.
+func (b *ListingBuilder) BulkEdit(vs ...string) (r *BulkActionBuilder) {
+	r = b.BulkAction(bulkEditActionName)
+	b.bulkEditFields = vs
+	r.ComponentFunc(b.bulkEditComponent).
+		UpdateFunc(b.bulkEditUpdate)
+	return
+}
+
+// bulkActionLabel returns label, or the message of the bulk edit without the label.
+func bulkActionLabel(ba *BulkActionBuilder, label string, ctx *web.EventContext) string {
+	if ba.name == bulkEditActionName && ba.NameLabel.label == "" {
+		return MustGetMessages(ctx.R).BulkEdit
+	}
+	return label
+}
+
+func (b *ListingBuilder) bulkEditFieldNames() (r []string) {
+	if len(b.bulkEditFields) > 0 {
+		return b.bulkEditFields
+	}
+	for _, f := range b.mb.editing.fields {
+		if f.nestedFieldsBuilder != nil {
+			continue
+		}
+		r = append(r, f.name)
+	}
+	return
+}
+
+func (b *ListingBuilder) bulkEditComponent(selectedIds []string, ctx *web.EventContext) h.HTMLComponent {
+	msgr := MustGetMessages(ctx.R)
+	info := b.mb.Info()
+	names := b.bulkEditFieldNames()
+	fb := b.mb.editing.FieldsBuilder.Only(stringsToInterfaces(names)...)
+
+	vErr, _ := ctx.Flash.(*web.ValidationErrors)
+	if vErr == nil {
+		vErr = &web.ValidationErrors{}
+	}
+
+	obj := b.mb.NewModel()
+	var items []map[string]string
+	var comps []h.HTMLComponent
+	for _, name := range names {
+		if !info.FieldAllowed(PermUpdate, nil, name, ctx.R) {
+			continue
+		}
+		items = append(items, map[string]string{
+			"text":  i18n.PT(ctx.R, ModelsI18nModuleKey, b.mb.label, fb.getLabel(fb.GetField(name).NameLabel)),
+			"value": name,
+		})
+		comps = append(comps, h.Div(
+			fb.fieldToComponentWithFormValueKey(info, obj, "", ctx, name, nil, true, vErr),
+		).Attr("v-show", fmt.Sprintf("locals.fields.includes(%s)", h.JSONString(name))))
+	}
+
+	picked := ctx.R.Form[ParamBulkEditFields]
+	if picked == nil {
+		picked = []string{}
+	}
+	return web.Scope(
+		VSelect().
+			Items(items).
+			ItemText("text").
+			ItemValue("value").
+			Label(msgr.BulkEditFields).
+			Multiple(true).
+			Chips(true).
+			Value(picked).
+			FieldName(ParamBulkEditFields).
+			Attr("v-model", "locals.fields"),
+		h.Components(comps...),
+	).Init(h.JSONString(map[string]interface{}{"fields": picked})).
+		VSlot("{ locals }")
+}
+
+// bulkEditUpdate saves the selected records one by one, and lists the updated and failed ids
+// in the error of the dialog if any of them failed.
+func (b *ListingBuilder) bulkEditUpdate(selectedIds []string, ctx *web.EventContext) (err error) {
+	msgr := MustGetMessages(ctx.R)
+	picked := ctx.R.Form[ParamBulkEditFields]
+	if len(picked) == 0 {
+		vErr := &web.ValidationErrors{}
+		vErr.GlobalError(msgr.BulkEditFieldsRequired)
+		ctx.Flash = vErr
+		return vErr
+	}
+
+	allowed := map[string]bool{}
+	for _, name := range b.bulkEditFieldNames() {
+		allowed[name] = true
+	}
+	for _, name := range picked {
+		if !allowed[name] {
+			return fmt.Errorf("field %s can not be bulk edited", name)
+		}
+	}
+
+	var updated, failed []string
+	for _, id := range selectedIds {
+		if err1 := b.bulkEditRecord(id, picked, ctx); err1 != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s)", id, err1.Error()))
+			continue
+		}
+		updated = append(updated, id)
+	}
+	if len(failed) == 0 {
+		return
+	}
+
+	vErr := &web.ValidationErrors{}
+	vErr.GlobalError(msgr.BulkEditResult(bulkIdsText(updated), bulkIdsText(failed)))
+	ctx.Flash = vErr
+	return vErr
+}
+
+func (b *ListingBuilder) bulkEditRecord(id string, picked []string, ctx *web.EventContext) (err error) {
+	eb := b.mb.editing
+	recordCtx := &web.EventContext{
+		R:        bulkEditRecordRequest(ctx.R, id),
+		W:        ctx.W,
+		Injector: ctx.Injector,
+	}
+
+	obj, err := eb.Fetcher(b.mb.NewModel(), id, recordCtx)
+	if err != nil {
+		return
+	}
+	if b.mb.Info().Verifier().Do(PermUpdate).ObjectOn(obj).WithReq(ctx.R).IsAllowed() != nil {
+		return perm.PermissionDenied
+	}
+
+	fb := eb.FieldsBuilder.Only(stringsToInterfaces(picked)...)
+	vErr := fb.Unmarshal(obj, b.mb.Info(), false, recordCtx)
+	if !vErr.HaveErrors() {
+		vErr = eb.validate(obj, recordCtx)
+	}
+	if vErr.HaveErrors() {
+		return errors.New(b.bulkEditErrorText(&vErr))
+	}
+	return eb.Save(obj, id, recordCtx)
+}
+
+// bulkEditErrorText joins the global errors and the errors of the editing fields.
+func (b *ListingBuilder) bulkEditErrorText(vErr *web.ValidationErrors) string {
+	msgs := vErr.GetGlobalErrors()
+	for _, f := range b.mb.editing.fields {
+		msgs = append(msgs, vErr.GetFieldErrors(f.name)...)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// bulkEditRecordRequest returns a request that posts the form of the bulk edit like the editing form of the record.
+func bulkEditRecordRequest(r *http.Request, id string) *http.Request {
+	rr := r.Clone(r.Context())
+	rr.Form = url.Values{}
+	for k, v := range r.Form {
+		rr.Form[k] = v
+	}
+	rr.Form.Set(ParamID, id)
+	if r.MultipartForm != nil {
+		mf := *r.MultipartForm
+		mf.Value = rr.Form
+		rr.MultipartForm = &mf
+	}
+	return rr
+}
+
+// bulkIdsText joins the ids, only the first 10 of them are shown.
+func bulkIdsText(ids []string) string {
+	if len(ids) <= 10 {
+		return strings.Join(ids, ", ")
+	}
+	return fmt.Sprintf("%s...(+%d)", strings.Join(ids[:10], ", "), len(ids)-10)
+}
+
+func stringsToInterfaces(vs []string) (r []interface{}) {
+	for _, v := range vs {
+		r = append(r, v)
+	}
+	return
+}
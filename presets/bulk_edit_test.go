@@ -0,0 +1,58 @@
+package presets
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qor5/web"
+)
+
+func TestBulkEdit(t *testing.T) {
+	op := &restAPIItemsOperator{items: map[string]*restAPIItem{
+		"1": {ID: "1", Name: "A", Price: 1, Note: "a"},
+		"2": {ID: "2", Price: 2, Note: "b"},
+	}}
+	b := New().DataOperator(op)
+	mb := b.Model(&restAPIItem{})
+	mb.Editing("Name", "Price", "Note").Field("Name").Validate("required")
+	mb.Listing().BulkEdit("Price", "Note")
+
+	update := func(fields map[string][]string) (ctx *web.EventContext, err error) {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		for k, vs := range fields {
+			for _, v := range vs {
+				mw.WriteField(k, v)
+			}
+		}
+		mw.Close()
+		req := httptest.NewRequest("POST", "/rest-api-items?__execute_event__=presets_DoBulkAction", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		req.ParseMultipartForm(1 << 20)
+		ctx = &web.EventContext{R: req, W: httptest.NewRecorder()}
+		return ctx, mb.listing.bulkEditUpdate([]string{"1", "2"}, ctx)
+	}
+
+	if _, err := update(map[string][]string{"Price": {"5"}}); err == nil {
+		t.Errorf("no fields are picked")
+	}
+	if _, err := update(map[string][]string{ParamBulkEditFields: {"Name"}, "Name": {"B"}}); err == nil {
+		t.Errorf("Name is not bulk editable")
+	}
+
+	ctx, err := update(map[string][]string{ParamBulkEditFields: {"Price"}, "Price": {"5"}, "Note": {"x"}})
+	if err == nil {
+		t.Fatal("item 2 without name should fail")
+	}
+	if msg := ctx.Flash.(*web.ValidationErrors).GetGlobalError(); msg != "Updated: 1. Failed: 2 (Name is required)" {
+		t.Errorf("message = %s", msg)
+	}
+	if item := op.items["1"]; item.Price != 5 || item.Note != "a" {
+		t.Errorf("item 1 = %+v", item)
+	}
+	if item := op.items["2"]; item.Price != 2 {
+		t.Errorf("item 2 = %+v", item)
+	}
+}
@@ -0,0 +1,229 @@
+package presets
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/qor5/ui/vuetify"
+	vx "github.com/qor5/ui/vuetifyx"
+	"github.com/qor5/web"
+	"github.com/sunfmin/reflectutils"
+	h "github.com/theplant/htmlgo"
+)
+
+const (
+	CalendarViewMonth = "month"
+	CalendarViewWeek  = "week"
+)
+
+const calendarDateLayout = "2006-01-02"
+
+// CalendarBuilder shows the records of the listing on the month or the week grid by the date field,
+// the records with the end field are shown on all the days from the start to the end.
+type CalendarBuilder struct {
+	lb          *ListingBuilder
+	startField  string
+	startColumn string
+	endField    string
+	endColumn   string
+	titleFunc   func(obj interface{}, ctx *web.EventContext) string
+	defaultView string
+}
+
+// Calendar adds the calendar layout to the listing, field is a time.Time or *time.Time field,
+// and dbColumn is its column to search the records in the range of the calendar.
+func (b *ListingBuilder) Calendar(field string, dbColumn string) (r *CalendarBuilder) {
+	b.calendar = &CalendarBuilder{
+		lb:          b,
+		startField:  field,
+		startColumn: dbColumn,
+		defaultView: CalendarViewMonth,
+	}
+	return b.calendar
+}
+
+// ScheduleCalendar adds the calendar layout by the ScheduledStartAt and the ScheduledEndAt of publish.Schedule.
+func (b *ListingBuilder) ScheduleCalendar() (r *CalendarBuilder) {
+	return b.Calendar("ScheduledStartAt", "scheduled_start_at").
+		EndField("ScheduledEndAt", "scheduled_end_at")
+}
+
+// EndField sets the end of the records, the records without the end are shown on the day of the start.
+func (b *CalendarBuilder) EndField(field string, dbColumn string) (r *CalendarBuilder) {
+	b.endField = field
+	b.endColumn = dbColumn
+	return b
+}
+
+// TitleFunc sets the title of the record, by default it is the PageTitle() of the record or its id.
+func (b *CalendarBuilder) TitleFunc(v func(obj interface{}, ctx *web.EventContext) string) (r *CalendarBuilder) {
+	b.titleFunc = v
+	return b
+}
+
+// DefaultView sets the view when the url query has none, CalendarViewMonth or CalendarViewWeek.
+func (b *CalendarBuilder) DefaultView(v string) (r *CalendarBuilder) {
+	b.defaultView = v
+	return b
+}
+
+// calendarRange returns the days shown in the view of the date, the weeks start on Sunday.
+func calendarRange(view string, date time.Time) (start time.Time, end time.Time) {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	if view == CalendarViewWeek {
+		start = date.AddDate(0, 0, -int(date.Weekday()))
+		return start, start.AddDate(0, 0, 7)
+	}
+	first := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+	start = first.AddDate(0, 0, -int(first.Weekday()))
+	end = first.AddDate(0, 1, 0)
+	if wd := end.Weekday(); wd != time.Sunday {
+		end = end.AddDate(0, 0, 7-int(wd))
+	}
+	return
+}
+
+func (b *CalendarBuilder) viewAndDate(ctx *web.EventContext) (view string, date time.Time) {
+	qs := ctx.R.URL.Query()
+	view = qs.Get(ParamCalendarView)
+	if view != CalendarViewMonth && view != CalendarViewWeek {
+		view = b.defaultView
+	}
+	date, err := time.ParseInLocation(calendarDateLayout, qs.Get(ParamCalendarDate), time.Local)
+	if err != nil {
+		date = time.Now()
+	}
+	return
+}
+
+// rangeCondition searches the records that overlap the range.
+func (b *CalendarBuilder) rangeCondition(start time.Time, end time.Time) *SQLCondition {
+	if b.endColumn == "" {
+		return &SQLCondition{
+			Query: fmt.Sprintf("%s >= ? AND %s < ?", b.startColumn, b.startColumn),
+			Args:  []interface{}{start, end},
+		}
+	}
+	return &SQLCondition{
+		Query: fmt.Sprintf("%s < ? AND (%s >= ? OR (%s IS NULL AND %s >= ?))", b.startColumn, b.endColumn, b.endColumn, b.startColumn),
+		Args:  []interface{}{end, start, start},
+	}
+}
+
+// days returns the days of the record in the range.
+func (b *CalendarBuilder) days(obj interface{}, start time.Time, end time.Time) (r []time.Time) {
+	from, ok := calendarTime(reflectutils.MustGet(obj, b.startField))
+	if !ok {
+		return
+	}
+	to := from
+	if b.endField != "" {
+		if v, ok := calendarTime(reflectutils.MustGet(obj, b.endField)); ok && v.After(from) {
+			to = v
+		}
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	if from.Before(start) {
+		from = start
+	}
+	for d := from; !d.After(to) && d.Before(end); d = d.AddDate(0, 0, 1) {
+		r = append(r, d)
+	}
+	return
+}
+
+func calendarTime(v interface{}) (t time.Time, ok bool) {
+	switch vv := v.(type) {
+	case time.Time:
+		t = vv
+	case *time.Time:
+		if vv == nil {
+			return
+		}
+		t = *vv
+	default:
+		return
+	}
+	if t.IsZero() {
+		return
+	}
+	return t.Local(), true
+}
+
+func (b *CalendarBuilder) component(ctx *web.EventContext) h.HTMLComponent {
+	msgr := MustGetMessages(ctx.R)
+	view, date := b.viewAndDate(ctx)
+	start, end := calendarRange(view, date)
+
+	events := make(map[string][]h.HTMLComponent)
+	for _, obj := range b.lb.layoutSearch(ctx, "", b.rangeCondition(start, end)) {
+		id := vx.ObjectID(obj)
+		title := getPageTitle(obj, id)
+		if b.titleFunc != nil {
+			title = b.titleFunc(obj, ctx)
+		}
+		for _, d := range b.days(obj, start, end) {
+			key := d.Format(calendarDateLayout)
+			events[key] = append(events[key], VChip(h.Text(title)).
+				XSmall(true).
+				Label(true).
+				Color("primary").
+				Outlined(true).
+				Class("d-flex mb-1 text-truncate").
+				Attr("@click", b.lb.recordOnclick(id)))
+		}
+	}
+
+	goTo := func(d time.Time, v string) string {
+		return web.Plaid().
+			PushState(true).
+			MergeQuery(true).
+			Query(ParamCalendarDate, d.Format(calendarDateLayout)).
+			Query(ParamCalendarView, v).
+			Go()
+	}
+	// moved from the first of the month, so that the 31st doesn't overflow the shorter months
+	firstDay := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+	prev, next := firstDay.AddDate(0, -1, 0), firstDay.AddDate(0, 1, 0)
+	title := msgr.CalendarMonthTitle(date)
+	if view == CalendarViewWeek {
+		prev, next = date.AddDate(0, 0, -7), date.AddDate(0, 0, 7)
+		title = fmt.Sprintf("%s - %s", start.Format("2006-01-02"), end.AddDate(0, 0, -1).Format("2006-01-02"))
+	}
+
+	var cells []h.HTMLComponent
+	for i := 0; i < 7; i++ {
+		cells = append(cells, h.Div(h.Text(msgr.CalendarWeekday(time.Weekday(i)))).
+			Class("text-caption grey--text text-center py-1"))
+	}
+	today := time.Now().Format(calendarDateLayout)
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		key := d.Format(calendarDateLayout)
+		dayClass := "text-caption"
+		if view == CalendarViewMonth && d.Month() != date.Month() {
+			dayClass += " grey--text"
+		}
+		if key == today {
+			dayClass += " primary--text font-weight-bold"
+		}
+		cells = append(cells, h.Div(
+			h.Div(h.Text(fmt.Sprint(d.Day()))).Class(dayClass),
+			h.Components(events[key]...),
+		).Class("pa-1").Style("min-height: 110px; min-width: 0; border-top: 1px solid #e0e0e0; border-left: 1px solid #e0e0e0;"))
+	}
+
+	return h.Div(
+		VToolbar(
+			VBtn("").Icon(true).Children(VIcon("chevron_left")).Attr("@click", goTo(prev, view)),
+			VBtn("").Icon(true).Children(VIcon("chevron_right")).Attr("@click", goTo(next, view)),
+			VBtn(msgr.CalendarToday).Depressed(true).Small(true).Class("ml-2").Attr("@click", goTo(time.Now(), view)),
+			VToolbarTitle(title).Class("ml-4"),
+			VSpacer(),
+			VBtnToggle(
+				VBtn(msgr.CalendarMonth).Small(true).Attr("value", CalendarViewMonth).Attr("@click", goTo(date, CalendarViewMonth)),
+				VBtn(msgr.CalendarWeek).Small(true).Attr("value", CalendarViewWeek).Attr("@click", goTo(date, CalendarViewWeek)),
+			).Value(view).Dense(true).Mandatory(true),
+		).Flat(true).Dense(true),
+		h.Div(cells...).Style("display: grid; grid-template-columns: repeat(7, minmax(0, 1fr)); border-right: 1px solid #e0e0e0; border-bottom: 1px solid #e0e0e0;"),
+	).Class("pa-2")
+}
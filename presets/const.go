@@ -8,6 +8,11 @@ const (
 	PermUpdate = "presets:update"
 	PermDelete = "presets:delete"
 
+	PermShareListingView  = "presets:share_listing_view"
+	PermRestore           = "presets:restore"
+	PermDeletePermanently = "presets:delete_permanently"
+	PermReleaseEditLease  = "presets:release_edit_lease"
+
 	PermActions         = "actions"
 	PermDoListingAction = "do_listing_action"
 	PermBulkActions     = "bulk_actions"
@@ -30,6 +35,28 @@ const (
 	ParamInDialog                 = "presets_in_dialog"
 	ParamListingQueries           = "presets_listing_queries"
 	ParamAfterDeleteEvent         = "presets_after_delete_event"
+	ParamExportFormat             = "export_format"
+	ParamImportFile               = "import_file"
+	ParamImportMapping            = "import_mapping"
+	ParamKeysetAfter              = "after"
+	ParamKeysetBefore             = "before"
+	ParamListingViewID            = "listing_view_id"
+	ParamListingViewName          = "listing_view_name"
+	ParamListingViewShared        = "listing_view_shared"
+	ParamVersion                  = "presets_version"
+	ParamEditLeaseOwned           = "presets_edit_lease_owned"
+	ParamTrash                    = "presets_trash"
+	ParamListingCellField         = "presets_listing_cell_field"
+	ParamGlobalSearchKeyword      = "presets_global_search_keyword"
+	ParamBulkEditFields           = "presets_bulk_edit_fields"
+	ParamListingLayout            = "presets_listing_layout"
+	ParamKanbanValue              = "presets_kanban_value"
+	ParamCalendarDate             = "presets_calendar_date"
+	ParamCalendarView             = "presets_calendar_view"
+	ParamTreeParentID             = "presets_tree_parent_id"
+	ParamTreeIndex                = "presets_tree_index"
+	ParamWizardStep               = "presets_wizard_step"
+	ParamWizardTarget             = "presets_wizard_target"
 
 	// list editor
 	ParamAddRowFormKey      = "listEditor_AddRowFormKey"
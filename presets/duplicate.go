@@ -0,0 +1,174 @@
+package presets
+
+import (
+	"reflect"
+
+	"github.com/qor5/admin/presets/actions"
+	. "github.com/qor5/ui/vuetify"
+	vx "github.com/qor5/ui/vuetifyx"
+	"github.com/qor5/web"
+	h "github.com/theplant/htmlgo"
+)
+
+// DuplicateFunc sets the fields of the copy to, which is deep copied from the record from.
+type DuplicateFunc func(from interface{}, to interface{}, ctx *web.EventContext) (err error)
+
+// DuplicateBuilder duplicates the record by the "Duplicate" item of the row menu, the copy is saved by the
+// EditingBuilder.Save, and then opened in the editing drawer. It requires the PermCreate permission.
+type DuplicateBuilder struct {
+	mb       *ModelBuilder
+	Fetcher  FetchFunc
+	Resetter DuplicateFunc
+	Copier   DuplicateFunc
+}
+
+// Duplicate adds the "Duplicate" item to the row menu of the listing. The record is fetched by the Fetcher
+// of the editing and deep copied, then the Resetter resets the primary key and the CreatedAt, UpdatedAt and
+// DeletedAt fields of the copy, and the Copier customizes the copy, like appending " (copy)" to the name or
+// clearing the unique slug. See gorm2op.DataOperatorBuilder.Duplicate to copy the has-many children.
+func (mb *ModelBuilder) Duplicate() (r *DuplicateBuilder) {
+	if mb.duplicate != nil {
+		return mb.duplicate
+	}
+
+	mb.duplicate = &DuplicateBuilder{mb: mb}
+	mb.duplicate.ResetFunc(mb.duplicate.defaultReset)
+
+	ib := mb.listing.RowMenu().RowMenuItem("Duplicate").
+		PermAction(PermCreate).
+		OnClick(mb.duplicate.duplicate)
+	ib.ComponentFunc(func(obj interface{}, id string, ctx *web.EventContext) h.HTMLComponent {
+		if mb.Info().Verifier().Do(PermCreate).ObjectOn(obj).WithReq(ctx.R).IsAllowed() != nil {
+			return nil
+		}
+		return VListItem(
+			VListItemIcon(VIcon("content_copy")),
+			VListItemTitle(h.Text(MustGetMessages(ctx.R).Duplicate)),
+		).Attr("@click", web.Plaid().
+			EventFunc(ib.eventID).
+			Query(ParamID, id).
+			Go())
+	})
+	return mb.duplicate
+}
+
+// FetchFunc sets the func that fetches the record to duplicate, its default value is the Fetcher of the editing.
+func (b *DuplicateBuilder) FetchFunc(v FetchFunc) (r *DuplicateBuilder) {
+	b.Fetcher = v
+	return b
+}
+
+func (b *DuplicateBuilder) ResetFunc(v DuplicateFunc) (r *DuplicateBuilder) {
+	b.Resetter = v
+	return b
+}
+
+func (b *DuplicateBuilder) CopyFunc(v DuplicateFunc) (r *DuplicateBuilder) {
+	b.Copier = v
+	return b
+}
+
+func (b *DuplicateBuilder) defaultReset(from interface{}, to interface{}, ctx *web.EventContext) (err error) {
+	v := reflect.Indirect(reflect.ValueOf(to))
+	for _, name := range []string{b.mb.primaryField, "CreatedAt", "UpdatedAt", "DeletedAt"} {
+		if f := v.FieldByName(name); f.IsValid() && f.CanSet() {
+			f.Set(reflect.Zero(f.Type()))
+		}
+	}
+	return
+}
+
+func (b *DuplicateBuilder) duplicate(ctx *web.EventContext, id string) (r web.EventResponse, err error) {
+	msgr := MustGetMessages(ctx.R)
+	eb := b.mb.editing
+	fetcher := b.Fetcher
+	if fetcher == nil {
+		fetcher = eb.Fetcher
+	}
+
+	from, err := fetcher(b.mb.NewModel(), id, ctx)
+	if err != nil {
+		return
+	}
+	to := DeepCopy(from)
+	if err = b.Resetter(from, to, ctx); err != nil {
+		return
+	}
+	if b.Copier != nil {
+		if err = b.Copier(from, to, ctx); err != nil {
+			return
+		}
+	}
+
+	if vErr := eb.validate(to, ctx); vErr.HaveErrors() {
+		msg := vErr.GetGlobalError()
+		if msg == "" {
+			msg = vErr.Error()
+		}
+		ShowMessage(&r, msg, "warning")
+		return
+	}
+	if err = eb.Save(to, "", ctx); err != nil {
+		ShowMessage(&r, err.Error(), "warning")
+		return
+	}
+
+	ShowMessage(&r, msgr.SuccessfullyDuplicated, "")
+	web.AppendVarsScripts(&r, web.Plaid().
+		EventFunc(actions.Edit).
+		Query(ParamID, vx.ObjectID(to)).
+		Go())
+	return
+}
+
+// DeepCopy returns the copy of v, the pointers, slices, maps and structs are copied recursively,
+// and the unexported fields of the structs are copied shallowly.
+func DeepCopy(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return deepCopy(reflect.ValueOf(v)).Interface()
+}
+
+func deepCopy(v reflect.Value) reflect.Value {
+	r := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			p := reflect.New(v.Type().Elem())
+			p.Elem().Set(deepCopy(v.Elem()))
+			r.Set(p)
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			r.Set(deepCopy(v.Elem()))
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			for i := 0; i < v.Len(); i++ {
+				s.Index(i).Set(deepCopy(v.Index(i)))
+			}
+			r.Set(s)
+		}
+	case reflect.Map:
+		if !v.IsNil() {
+			m := reflect.MakeMapWithSize(v.Type(), v.Len())
+			iter := v.MapRange()
+			for iter.Next() {
+				m.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+			}
+			r.Set(m)
+		}
+	case reflect.Struct:
+		r.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if f := r.Field(i); f.CanSet() {
+				f.Set(deepCopy(v.Field(i)))
+			}
+		}
+	default:
+		r.Set(v)
+	}
+	return r
+}
@@ -0,0 +1,52 @@
+package presets
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qor5/web"
+)
+
+func TestDuplicate(t *testing.T) {
+	op := &restAPIItemsOperator{items: map[string]*restAPIItem{
+		"1": {ID: "1", Name: "A", Price: 1},
+	}}
+	b := New().DataOperator(op)
+	mb := b.Model(&restAPIItem{})
+	d := mb.Duplicate().CopyFunc(func(from interface{}, to interface{}, ctx *web.EventContext) (err error) {
+		to.(*restAPIItem).Name += " (copy)"
+		return
+	})
+
+	ctx := &web.EventContext{R: httptest.NewRequest("POST", "/rest-api-items", nil), W: httptest.NewRecorder()}
+	r, err := d.duplicate(ctx, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	item, ok := op.items["3"]
+	if !ok || item.Name != "A (copy)" || item.Price != 1 {
+		t.Fatalf("copy = %+v", item)
+	}
+	if op.items["1"].Name != "A" {
+		t.Errorf("the record is changed: %+v", op.items["1"])
+	}
+	if !strings.Contains(r.VarsScript, `eventFunc("presets_Edit").query("id", "3")`) {
+		t.Errorf("the copy is not opened: %s", r.VarsScript)
+	}
+}
+
+func TestDeepCopy(t *testing.T) {
+	type child struct{ Tags []string }
+	type parent struct {
+		Children []*child
+		Meta     map[string]*child
+	}
+	p := &parent{Children: []*child{{Tags: []string{"a"}}}, Meta: map[string]*child{"k": {Tags: []string{"b"}}}}
+	c := DeepCopy(p).(*parent)
+	c.Children[0].Tags[0] = "x"
+	c.Meta["k"].Tags[0] = "y"
+	if p.Children[0].Tags[0] != "a" || p.Meta["k"].Tags[0] != "b" {
+		t.Errorf("the original is changed: %+v", p)
+	}
+}
@@ -0,0 +1,187 @@
+package presets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/qor5/admin/presets/actions"
+	. "github.com/qor5/ui/vuetify"
+	"github.com/qor5/web"
+	"github.com/qor5/x/i18n"
+	"github.com/sunfmin/reflectutils"
+	h "github.com/theplant/htmlgo"
+)
+
+// ErrEditConflict is returned by the Saver when the record has been changed since the editing form was loaded.
+var ErrEditConflict = errors.New("the record has been changed by someone else")
+
+// VersionCondition is put in the request context when saving a record with the optimistic locking,
+// the DataOperator should only update the record whose FieldName is still Value, otherwise return ErrEditConflict.
+type VersionCondition struct {
+	FieldName string
+	Value     interface{}
+}
+
+type versionConditionKey struct{}
+
+// VersionConditionFrom returns the VersionCondition of the saving, nil if the optimistic locking is not enabled.
+func VersionConditionFrom(ctx *web.EventContext) *VersionCondition {
+	if ctx == nil || ctx.R == nil {
+		return nil
+	}
+	vc, _ := ctx.R.Context().Value(versionConditionKey{}).(*VersionCondition)
+	return vc
+}
+
+// OptimisticLock rejects the update if the record has been changed since the editing form was loaded,
+// and shows the changed fields to the user. fieldName is the version field, like UpdatedAt which is
+// updated by the DataOperator, or an integer field which is increased on every update.
+func (b *EditingBuilder) OptimisticLock(fieldName string) (r *EditingBuilder) {
+	if reflectutils.GetType(b.mb.model, fieldName) == nil {
+		panic(fmt.Sprintf("no version field %s in %T", fieldName, b.mb.model))
+	}
+	b.versionField = fieldName
+	return b
+}
+
+// editConflictError keeps the current record to compare with the values of the user.
+type editConflictError struct {
+	current interface{}
+}
+
+func (e *editConflictError) Error() string {
+	return ErrEditConflict.Error()
+}
+
+func (e *editConflictError) Unwrap() error {
+	return ErrEditConflict
+}
+
+// versionToken returns the version of the record as the hidden field value of the editing form.
+func versionToken(v interface{}) string {
+	switch vt := v.(type) {
+	case time.Time:
+		return vt.UTC().Format(time.RFC3339Nano)
+	case *time.Time:
+		if vt == nil {
+			return ""
+		}
+		return vt.UTC().Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
+
+func (b *EditingBuilder) versionHiddenField(obj interface{}) h.HTMLComponent {
+	if b.versionField == "" {
+		return nil
+	}
+	v, err := reflectutils.Get(obj, b.versionField)
+	if err != nil {
+		return h.Div(h.Text(err.Error())).Class("error--text")
+	}
+	return h.Input("").Type("hidden").Value(versionToken(v)).Attr(web.VFieldName(ParamVersion)...)
+}
+
+// lockVersion checks the version of the form is still the version of obj, which is fetched before unmarshalling the form,
+// then increases the integer version and returns the context with the VersionCondition for the Saver.
+func (b *EditingBuilder) lockVersion(obj interface{}, id string, ctx *web.EventContext) (saveCtx *web.EventContext, err error) {
+	stored, err := reflectutils.Get(obj, b.versionField)
+	if err != nil {
+		return
+	}
+	if versionToken(stored) != ctx.R.FormValue(ParamVersion) {
+		return nil, b.editConflict(obj, id, ctx)
+	}
+
+	fv := reflect.Indirect(reflect.ValueOf(obj)).FieldByName(b.versionField)
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fv.SetInt(fv.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		fv.SetUint(fv.Uint() + 1)
+	}
+
+	c := *ctx
+	c.R = ctx.R.WithContext(context.WithValue(ctx.R.Context(), versionConditionKey{}, &VersionCondition{
+		FieldName: b.versionField,
+		Value:     stored,
+	}))
+	return &c, nil
+}
+
+// editConflict fetches the current record, and sets its version to obj,
+// so that the user can overwrite it by submitting the form again.
+func (b *EditingBuilder) editConflict(obj interface{}, id string, ctx *web.EventContext) error {
+	current, err := b.Fetcher(b.mb.NewModel(), id, ctx)
+	if err != nil {
+		return err
+	}
+	v, err := reflectutils.Get(current, b.versionField)
+	if err != nil {
+		return err
+	}
+	if err = reflectutils.Set(obj, b.versionField, v); err != nil {
+		return err
+	}
+	return &editConflictError{current: current}
+}
+
+// conflictDialog compares the values of the user with the current values field by field.
+func (b *EditingBuilder) conflictDialog(obj interface{}, conflict *editConflictError, ctx *web.EventContext) h.HTMLComponent {
+	msgr := MustGetMessages(ctx.R)
+
+	var trs []h.HTMLComponent
+	for _, f := range b.fields {
+		if !b.mb.Info().FieldAllowed(PermGet, conflict.current, f.name, ctx.R) {
+			continue
+		}
+		mine, err1 := reflectutils.Get(obj, f.name)
+		current, err2 := reflectutils.Get(conflict.current, f.name)
+		if err1 != nil || err2 != nil || reflect.DeepEqual(mine, current) {
+			continue
+		}
+		trs = append(trs, h.Tr(
+			h.Td(h.Text(i18n.PT(ctx.R, ModelsI18nModuleKey, b.mb.label, b.mb.getLabel(f.NameLabel)))),
+			h.Td(h.Text(fmt.Sprint(mine))),
+			h.Td(h.Text(fmt.Sprint(current))),
+		))
+	}
+
+	discard := web.Plaid().Reload()
+	if !b.mb.singleton {
+		discard = web.Plaid().
+			EventFunc(actions.Edit).
+			Query(ParamID, ctx.R.FormValue(ParamID)).
+			Query(ParamOverlay, ctx.R.FormValue(ParamOverlay))
+	}
+
+	return VDialog(
+		VCard(
+			VCardTitle(h.Text(msgr.EditConflictTitle)),
+			VCardText(
+				h.Div(h.Text(msgr.EditConflictNotice)).Class("mb-2"),
+				VSimpleTable(
+					h.Thead(h.Tr(h.Th(msgr.EditConflictField), h.Th(msgr.EditConflictMine), h.Th(msgr.EditConflictCurrent))),
+					h.Tbody(trs...),
+				).Dense(true),
+			),
+			VCardActions(
+				VSpacer(),
+				VBtn(msgr.EditConflictDiscard).
+					Depressed(true).
+					Class("ml-2").
+					Attr("@click", "locals.conflictDialog = false;"+discard.Go()),
+				VBtn(msgr.EditConflictKeep).
+					Color("primary").
+					Depressed(true).
+					Class("ml-2").
+					Attr("@click", "locals.conflictDialog = false"),
+			),
+		),
+	).Width("600px").
+		Attr("v-model", "locals.conflictDialog").
+		Attr(web.InitContextLocals, `{conflictDialog: true}`)
+}
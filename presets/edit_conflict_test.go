@@ -0,0 +1,77 @@
+package presets
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qor5/web"
+)
+
+type editConflictItem struct {
+	ID      uint
+	Name    string
+	Version int
+}
+
+func TestOptimisticLock(t *testing.T) {
+	stored := editConflictItem{ID: 1, Name: "A", Version: 2}
+	var saved *editConflictItem
+	var condition *VersionCondition
+
+	b := New().DataOperator(&restAPIItemsOperator{})
+	eb := b.Model(&editConflictItem{}).Editing("Name").
+		OptimisticLock("Version").
+		FetchFunc(func(obj interface{}, id string, ctx *web.EventContext) (r interface{}, err error) {
+			v := stored
+			return &v, nil
+		}).
+		SaveFunc(func(obj interface{}, id string, ctx *web.EventContext) (err error) {
+			saved = obj.(*editConflictItem)
+			condition = VersionConditionFrom(ctx)
+			return
+		})
+
+	update := func(version string) error {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		mw.WriteField(ParamID, "1")
+		mw.WriteField(ParamVersion, version)
+		mw.WriteField("Name", "B")
+		mw.Close()
+		r := httptest.NewRequest("POST", "/edit-conflict-items?__execute_event__=presets_Update", &body)
+		r.Header.Set("Content-Type", mw.FormDataContentType())
+		ctx := &web.EventContext{R: r, W: httptest.NewRecorder()}
+		return eb.doUpdate(ctx, &web.EventResponse{}, false)
+	}
+
+	err := update("1")
+	var conflict *editConflictError
+	if !errors.As(err, &conflict) || saved != nil {
+		t.Fatalf("err = %v, saved = %v", err, saved)
+	}
+	if conflict.current.(*editConflictItem).Name != "A" {
+		t.Errorf("current = %+v", conflict.current)
+	}
+
+	if err = update("2"); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Name != "B" || saved.Version != 3 {
+		t.Errorf("saved = %+v", saved)
+	}
+	if condition == nil || condition.FieldName != "Version" || condition.Value != 2 {
+		t.Errorf("condition = %+v", condition)
+	}
+}
+
+func TestOptimisticLockWithoutVersionField(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("the missing version field is not checked")
+		}
+	}()
+	New().Model(&editConflictItem{}).Editing("Name").OptimisticLock("Revision")
+}
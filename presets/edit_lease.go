@@ -0,0 +1,241 @@
+package presets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/qor5/admin/presets/actions"
+	. "github.com/qor5/ui/vuetify"
+	"github.com/qor5/web"
+	"github.com/qor5/x/perm"
+	h "github.com/theplant/htmlgo"
+)
+
+// ErrEditLeaseHeld is returned when the record is updated by the user who doesn't hold its edit lease.
+var ErrEditLeaseHeld = errors.New("the record is being edited by someone else")
+
+const DefaultEditLeaseTTL = time.Minute
+
+// EditLease is the lease of a record that is held by the user who opens it in the editing form.
+type EditLease struct {
+	HolderID   string
+	HolderName string
+	ExpiresAt  time.Time
+	// held by the current user
+	Owned bool
+}
+
+// EditLeaseStore keeps the edit leases of the records, see gorm2op.EditLeaseStore.
+// model is the uri name of the model, and id is the id of the record.
+type EditLeaseStore interface {
+	// GetLease returns the unexpired lease of the record, nil if nobody holds it.
+	GetLease(model string, id string, ctx *web.EventContext) (*EditLease, error)
+	// AcquireLease acquires or renews the lease of the record for the current user until ttl later,
+	// the unexpired lease of the other user is returned without Owned unless takeOver.
+	AcquireLease(model string, id string, ttl time.Duration, takeOver bool, ctx *web.EventContext) (*EditLease, error)
+	// ReleaseLease releases the lease of the current user, or the lease of anyone if force.
+	ReleaseLease(model string, id string, force bool, ctx *web.EventContext) error
+}
+
+// EditLease makes the editing form acquire the lease of the record, which is renewed by the heartbeat of the form
+// every third of ttl, and expires after ttl once the form is closed. The other users see the holder in the banner
+// of the read-only form, they can take the lease over, or release it with the PermReleaseEditLease permission.
+// The updates of the users who don't hold the lease are rejected with ErrEditLeaseHeld.
+func (b *EditingBuilder) EditLease(store EditLeaseStore, ttl time.Duration) (r *EditingBuilder) {
+	if b.leaseStore == nil && store != nil {
+		b.mb.RegisterEventFunc(actions.RenewEditLease, b.renewEditLease)
+		b.mb.RegisterEventFunc(actions.TakeOverEditLease, b.takeOverEditLease)
+		b.mb.RegisterEventFunc(actions.ReleaseEditLease, b.releaseEditLease)
+	}
+	if ttl <= 0 {
+		ttl = DefaultEditLeaseTTL
+	}
+	b.leaseStore = store
+	b.leaseTTL = ttl
+	return b
+}
+
+// AcquireEditLease acquires or renews the lease of the record for the current user,
+// the lease is nil if the edit leases are not enabled.
+func (b *EditingBuilder) AcquireEditLease(id string, takeOver bool, ctx *web.EventContext) (lease *EditLease, err error) {
+	if b.leaseStore == nil || id == "" {
+		return
+	}
+	return b.leaseStore.AcquireLease(b.mb.uriName, id, b.leaseTTL, takeOver, ctx)
+}
+
+// CheckEditLease returns ErrEditLeaseHeld if the record is being edited by someone else, for the changes that
+// are not submitted by the editing form, like the containers of the pages of the pagebuilder.
+func (b *EditingBuilder) CheckEditLease(id string, ctx *web.EventContext) (err error) {
+	lease, err := b.AcquireEditLease(id, false, ctx)
+	if err != nil {
+		return
+	}
+	return leaseHeldError(lease)
+}
+
+// checkEditLeaseHeld is CheckEditLease without acquiring the lease, Save checks it for all the changes,
+// like the listing cells, the bulk edit, the kanban cards, the tree nodes and the REST API.
+func (b *EditingBuilder) checkEditLeaseHeld(id string, ctx *web.EventContext) (err error) {
+	if b.leaseStore == nil || id == "" {
+		return
+	}
+	lease, err := b.leaseStore.GetLease(b.mb.uriName, id, ctx)
+	if err != nil {
+		return
+	}
+	return leaseHeldError(lease)
+}
+
+func leaseHeldError(lease *EditLease) error {
+	if lease != nil && !lease.Owned {
+		return fmt.Errorf("%w: %s", ErrEditLeaseHeld, lease.HolderName)
+	}
+	return nil
+}
+
+type editLeaseReadonlyKey struct{}
+
+// withEditLeaseReadonly disables all the fields of the form, see ModelInfo.FieldWritable.
+func withEditLeaseReadonly(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), editLeaseReadonlyKey{}, true))
+}
+
+func editLeaseReadonly(r *http.Request) bool {
+	v, _ := r.Context().Value(editLeaseReadonlyKey{}).(bool)
+	return v
+}
+
+// EditLeaseBanner shows the holder of the lease to the other users, and renews the lease of the holder
+// by the heartbeat, like for the pages that are not edited by the editing form.
+func (b *EditingBuilder) EditLeaseBanner(id string, lease *EditLease, ctx *web.EventContext) h.HTMLComponent {
+	if lease == nil {
+		return nil
+	}
+
+	owned := "0"
+	if lease.Owned {
+		owned = "1"
+	}
+	interval := fmt.Sprint((b.leaseTTL / 3).Milliseconds())
+	switch ctx.R.FormValue(ParamOverlay) {
+	case actions.Drawer:
+		interval = fmt.Sprintf("vars.presetsRightDrawer ? %s : 0", interval)
+	case actions.Dialog:
+		interval = fmt.Sprintf("vars.presetsDialog ? %s : 0", interval)
+	}
+
+	return web.Portal(b.editLeaseBannerContent(id, lease, ctx)).
+		Loader(web.Plaid().
+			URL(b.mb.Info().ListingHref()).
+			EventFunc(actions.RenewEditLease).
+			Query(ParamID, id).
+			Query(ParamOverlay, ctx.R.FormValue(ParamOverlay)).
+			Query(ParamEditLeaseOwned, owned)).
+		AutoReloadInterval(interval)
+}
+
+func (b *EditingBuilder) editLeaseBannerContent(id string, lease *EditLease, ctx *web.EventContext) h.HTMLComponent {
+	msgr := MustGetMessages(ctx.R)
+
+	if lease == nil {
+		return VAlert(
+			h.Div(
+				h.Text(msgr.EditLeaseExpired),
+				VSpacer(),
+				VBtn(msgr.EditLeaseReload).Small(true).Depressed(true).
+					Attr("@click", b.editLeaseReloadScript(id, ctx)),
+			).Class("d-flex align-center"),
+		).Type("info").Dense(true).Text(true)
+	}
+	if lease.Owned {
+		return h.Div()
+	}
+
+	var btns []h.HTMLComponent
+	if b.mb.Info().Verifier().Do(PermUpdate).WithReq(ctx.R).IsAllowed() == nil {
+		btns = append(btns, VBtn(msgr.EditLeaseTakeOver).Small(true).Depressed(true).Class("ml-2").
+			Attr("@click", web.Plaid().
+				URL(b.mb.Info().ListingHref()).
+				EventFunc(actions.TakeOverEditLease).
+				Query(ParamID, id).
+				Query(ParamOverlay, ctx.R.FormValue(ParamOverlay)).
+				Go()))
+	}
+	if b.mb.Info().Verifier().Do(PermReleaseEditLease).WithReq(ctx.R).IsAllowed() == nil {
+		btns = append(btns, VBtn(msgr.EditLeaseRelease).Small(true).Depressed(true).Class("ml-2").
+			Attr("@click", web.Plaid().
+				URL(b.mb.Info().ListingHref()).
+				EventFunc(actions.ReleaseEditLease).
+				Query(ParamID, id).
+				Go()))
+	}
+
+	return VAlert(
+		h.Div(
+			VIcon("lock").Small(true).Class("mr-2"),
+			h.Text(msgr.EditLeaseHeld(lease.HolderName)),
+			VSpacer(),
+			h.Components(btns...),
+		).Class("d-flex align-center"),
+	).Type("warning").Dense(true).Text(true)
+}
+
+// editLeaseReloadScript opens the editing form again, or reloads the page that is not an overlay.
+func (b *EditingBuilder) editLeaseReloadScript(id string, ctx *web.EventContext) string {
+	overlay := ctx.R.FormValue(ParamOverlay)
+	if overlay == "" {
+		return web.Plaid().Reload().Go()
+	}
+	return web.Plaid().
+		URL(b.mb.Info().ListingHref()).
+		EventFunc(actions.Edit).
+		Query(ParamID, id).
+		Query(ParamOverlay, overlay).
+		Go()
+}
+
+// renewEditLease is the heartbeat of the banner, it renews the lease of the holder,
+// and refreshes the holder for the other users.
+func (b *EditingBuilder) renewEditLease(ctx *web.EventContext) (r web.EventResponse, err error) {
+	id := ctx.R.FormValue(ParamID)
+	var lease *EditLease
+	if ctx.R.FormValue(ParamEditLeaseOwned) == "1" && b.mb.Info().Verifier().Do(PermUpdate).WithReq(ctx.R).IsAllowed() == nil {
+		lease, err = b.AcquireEditLease(id, false, ctx)
+	} else {
+		lease, err = b.leaseStore.GetLease(b.mb.uriName, id, ctx)
+	}
+	if err != nil {
+		return
+	}
+	r.Body = b.editLeaseBannerContent(id, lease, ctx)
+	return
+}
+
+func (b *EditingBuilder) takeOverEditLease(ctx *web.EventContext) (r web.EventResponse, err error) {
+	if b.mb.Info().Verifier().Do(PermUpdate).WithReq(ctx.R).IsAllowed() != nil {
+		ShowMessage(&r, perm.PermissionDenied.Error(), "warning")
+		return
+	}
+	id := ctx.R.FormValue(ParamID)
+	if _, err = b.AcquireEditLease(id, true, ctx); err != nil {
+		return
+	}
+	web.AppendVarsScripts(&r, b.editLeaseReloadScript(id, ctx))
+	return
+}
+
+func (b *EditingBuilder) releaseEditLease(ctx *web.EventContext) (r web.EventResponse, err error) {
+	if b.mb.Info().Verifier().Do(PermReleaseEditLease).WithReq(ctx.R).IsAllowed() != nil {
+		ShowMessage(&r, perm.PermissionDenied.Error(), "warning")
+		return
+	}
+	if err = b.leaseStore.ReleaseLease(b.mb.uriName, ctx.R.FormValue(ParamID), true, ctx); err != nil {
+		return
+	}
+	ShowMessage(&r, MustGetMessages(ctx.R).EditLeaseReleased, "")
+	return
+}
@@ -0,0 +1,130 @@
+package presets
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/qor5/web"
+	"github.com/qor5/x/perm"
+)
+
+// memoryEditLeaseStore keeps the leases of the user in the header X-User.
+type memoryEditLeaseStore struct {
+	leases map[string]*EditLease
+}
+
+func (s *memoryEditLeaseStore) GetLease(model string, id string, ctx *web.EventContext) (*EditLease, error) {
+	l, ok := s.leases[model+id]
+	if !ok || l.ExpiresAt.Before(time.Now()) {
+		return nil, nil
+	}
+	return &EditLease{HolderID: l.HolderID, HolderName: l.HolderName, ExpiresAt: l.ExpiresAt, Owned: l.HolderID == ctx.R.Header.Get("X-User")}, nil
+}
+
+func (s *memoryEditLeaseStore) AcquireLease(model string, id string, ttl time.Duration, takeOver bool, ctx *web.EventContext) (*EditLease, error) {
+	l, _ := s.GetLease(model, id, ctx)
+	if l == nil || l.Owned || takeOver {
+		user := ctx.R.Header.Get("X-User")
+		s.leases[model+id] = &EditLease{HolderID: user, HolderName: "User " + user, ExpiresAt: time.Now().Add(ttl)}
+	}
+	return s.GetLease(model, id, ctx)
+}
+
+func (s *memoryEditLeaseStore) ReleaseLease(model string, id string, force bool, ctx *web.EventContext) error {
+	if l, _ := s.GetLease(model, id, ctx); l != nil && (l.Owned || force) {
+		delete(s.leases, model+id)
+	}
+	return nil
+}
+
+func TestEditLease(t *testing.T) {
+	store := &memoryEditLeaseStore{leases: map[string]*EditLease{}}
+	var saved *editConflictItem
+
+	b := New().DataOperator(&restAPIItemsOperator{}).
+		Permission(perm.New().Policies(
+			perm.PolicyFor(perm.Anybody).WhoAre(perm.Allowed).ToDo(perm.Anything).On(perm.Anything),
+			perm.PolicyFor("viewer").WhoAre(perm.Denied).ToDo(PermUpdate).On("*:edit_conflict_items:*"),
+		).SubjectsFunc(func(r *http.Request) []string {
+			return []string{r.Header.Get("X-User")}
+		}))
+	eb := b.Model(&editConflictItem{}).Editing("Name").
+		EditLease(store, time.Minute).
+		FetchFunc(func(obj interface{}, id string, ctx *web.EventContext) (r interface{}, err error) {
+			return &editConflictItem{ID: 1, Name: "A"}, nil
+		}).
+		SaveFunc(func(obj interface{}, id string, ctx *web.EventContext) (err error) {
+			saved = obj.(*editConflictItem)
+			return
+		})
+
+	open := func(user string) *web.EventContext {
+		r := httptest.NewRequest("POST", "/edit-conflict-items?__execute_event__=presets_Edit&id=1", nil)
+		r.Header.Set("X-User", user)
+		ctx := &web.EventContext{R: r, W: httptest.NewRecorder()}
+		eb.editFormFor(nil, ctx)
+		return ctx
+	}
+	update := func(user string) error {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		mw.WriteField(ParamID, "1")
+		mw.WriteField("Name", "B")
+		mw.Close()
+		r := httptest.NewRequest("POST", "/edit-conflict-items?__execute_event__=presets_Update", &body)
+		r.Header.Set("Content-Type", mw.FormDataContentType())
+		r.Header.Set("X-User", user)
+		ctx := &web.EventContext{R: r, W: httptest.NewRecorder()}
+		return eb.doUpdate(ctx, &web.EventResponse{}, false)
+	}
+
+	// the users who can't update the record don't acquire its lease
+	if open("viewer"); len(store.leases) != 0 {
+		t.Errorf("the viewer acquires the lease")
+	}
+	if ctx := open("alice"); editLeaseReadonly(ctx.R) {
+		t.Errorf("the form of the holder is read-only")
+	}
+	if ctx := open("bob"); !editLeaseReadonly(ctx.R) || eb.mb.Info().FieldWritable(&editConflictItem{}, "Name", false, ctx.R) {
+		t.Errorf("the form of the other user is writable")
+	}
+
+	if err := update("bob"); !errors.Is(err, ErrEditLeaseHeld) || saved != nil {
+		t.Fatalf("err = %v, saved = %v", err, saved)
+	}
+	if err := update("alice"); err != nil || saved == nil || saved.Name != "B" {
+		t.Fatalf("err = %v, saved = %v", err, saved)
+	}
+
+	// the changes that are not submitted by the editing form are checked by Save
+	saved = nil
+	r := httptest.NewRequest("POST", "/edit-conflict-items/1", nil)
+	r.Header.Set("X-User", "bob")
+	if err := eb.Save(&editConflictItem{ID: 1, Name: "C"}, "1", &web.EventContext{R: r, W: httptest.NewRecorder()}); !errors.Is(err, ErrEditLeaseHeld) || saved != nil {
+		t.Errorf("the save of bob is not rejected: %v", err)
+	}
+
+	r = httptest.NewRequest("POST", "/edit-conflict-items?__execute_event__=presets_TakeOverEditLease&id=1", nil)
+	r.Header.Set("X-User", "bob")
+	if _, err := eb.takeOverEditLease(&web.EventContext{R: r, W: httptest.NewRecorder()}); err != nil {
+		t.Fatal(err)
+	}
+	saved = nil
+	if err := update("alice"); !errors.Is(err, ErrEditLeaseHeld) || saved != nil {
+		t.Errorf("the update of alice is not rejected after bob takes over: %v", err)
+	}
+
+	r = httptest.NewRequest("POST", "/edit-conflict-items?__execute_event__=presets_ReleaseEditLease&id=1", nil)
+	r.Header.Set("X-User", "admin")
+	if _, err := eb.releaseEditLease(&web.EventContext{R: r, W: httptest.NewRecorder()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := update("alice"); err != nil || saved == nil {
+		t.Errorf("the update of alice is rejected after the lease is released: %v", err)
+	}
+}
@@ -1,8 +1,10 @@
 package presets
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jinzhu/inflection"
@@ -27,6 +29,14 @@ type EditingBuilder struct {
 	sidePanel        ComponentFunc
 	actionsFunc      ObjectComponentFunc
 	editingTitleFunc EditingTitleComponentFunc
+	versionField     string
+	leaseStore       EditLeaseStore
+	leaseTTL         time.Duration
+	// see Wizard
+	wizard           bool
+	wizardValidators map[string]ValidateFunc
+	// see StructTagValidation
+	structTagValidation bool
 	FieldsBuilder
 }
 
@@ -57,12 +67,13 @@ func (b *EditingBuilder) Except(vs ...string) (r *EditingBuilder) {
 func (b *EditingBuilder) Creating(vs ...interface{}) (r *EditingBuilder) {
 	if b.mb.creating == nil {
 		b.mb.creating = &EditingBuilder{
-			mb:        b.mb,
-			Fetcher:   b.Fetcher,
-			Setter:    b.Setter,
-			Saver:     b.Saver,
-			Deleter:   b.Deleter,
-			Validator: b.Validator,
+			mb:                  b.mb,
+			Fetcher:             b.Fetcher,
+			Setter:              b.Setter,
+			Saver:               b.Saver,
+			Deleter:             b.Deleter,
+			Validator:           b.Validator,
+			structTagValidation: b.structTagValidation,
 		}
 	}
 	r = b.mb.creating
@@ -162,7 +173,7 @@ func (b *EditingBuilder) singletonPageFunc(ctx *web.EventContext) (r web.PageRes
 	r.PageTitle = title
 	obj, err := b.Fetcher(b.mb.NewModel(), "", ctx)
 	if err == ErrRecordNotFound {
-		if err = b.Saver(b.mb.NewModel(), "", ctx); err != nil {
+		if err = b.Save(b.mb.NewModel(), "", ctx); err != nil {
 			return
 		}
 		obj, err = b.Fetcher(b.mb.NewModel(), "", ctx)
@@ -184,6 +195,7 @@ func (b *EditingBuilder) editFormFor(obj interface{}, ctx *web.EventContext) h.H
 
 	var buttonLabel = msgr.Create
 	var disableUpdateBtn bool
+	var leaseBanner h.HTMLComponent
 	var title h.HTMLComponent
 	title = h.Text(msgr.CreatingObjectTitle(
 		i18n.T(ctx.R, ModelsI18nModuleKey, inflection.Singular(b.mb.label)),
@@ -197,6 +209,27 @@ func (b *EditingBuilder) editFormFor(obj interface{}, ctx *web.EventContext) h.H
 			}
 		}
 		disableUpdateBtn = b.mb.Info().Verifier().Do(PermUpdate).ObjectOn(obj).WithReq(ctx.R).IsAllowed() != nil
+		if b.leaseStore != nil {
+			// only the users who can update the record acquire its lease, the others see the holder
+			var lease *EditLease
+			var err error
+			if disableUpdateBtn {
+				lease, err = b.leaseStore.GetLease(b.mb.uriName, id, ctx)
+			} else {
+				lease, err = b.AcquireEditLease(id, false, ctx)
+			}
+			if err != nil {
+				disableUpdateBtn = true
+				ctx.R = withEditLeaseReadonly(ctx.R)
+				leaseBanner = VAlert(h.Text(err.Error())).Type("error").Dense(true).Text(true)
+			} else if lease != nil {
+				if !lease.Owned {
+					disableUpdateBtn = true
+					ctx.R = withEditLeaseReadonly(ctx.R)
+				}
+				leaseBanner = b.EditLeaseBanner(id, lease, ctx)
+			}
+		}
 		buttonLabel = msgr.Update
 		editingTitleText := msgr.EditingObjectTitle(
 			i18n.T(ctx.R, ModelsI18nModuleKey, inflection.Singular(b.mb.label)),
@@ -213,6 +246,7 @@ func (b *EditingBuilder) editFormFor(obj interface{}, ctx *web.EventContext) h.H
 	}
 
 	var notice h.HTMLComponent
+	var conflictDialog h.HTMLComponent
 	{
 		var text string
 		var color string
@@ -222,6 +256,11 @@ func (b *EditingBuilder) editFormFor(obj interface{}, ctx *web.EventContext) h.H
 				color = "success"
 			}
 		}
+		if conflict, ok := ctx.Flash.(*editConflictError); ok {
+			text = msgr.EditConflictNotice
+			color = "error"
+			conflictDialog = b.conflictDialog(obj, conflict, ctx)
+		}
 		vErr, ok := ctx.Flash.(*web.ValidationErrors)
 		if ok {
 			gErr := vErr.GetGlobalError()
@@ -268,12 +307,21 @@ func (b *EditingBuilder) editFormFor(obj interface{}, ctx *web.EventContext) h.H
 		VSpacer(),
 		updateBtn,
 	)
+	var fieldsContent h.HTMLComponent
+	if b.wizard && len(id) == 0 {
+		fieldsContent, actionButtons = b.wizardComponents(obj, updateBtn, ctx)
+	} else {
+		fieldsContent = b.ToComponent(b.mb.Info(), obj, ctx)
+	}
 
 	if b.actionsFunc != nil {
 		actionButtons = b.actionsFunc(obj, ctx)
 	}
 
 	var hiddenComps []h.HTMLComponent
+	if len(id) > 0 {
+		hiddenComps = append(hiddenComps, b.versionHiddenField(obj))
+	}
 	for _, hf := range b.hiddenFuncs {
 		hiddenComps = append(hiddenComps, hf(obj, ctx))
 	}
@@ -281,7 +329,7 @@ func (b *EditingBuilder) editFormFor(obj interface{}, ctx *web.EventContext) h.H
 	formContent := h.Components(
 		VCardText(
 			h.Components(hiddenComps...),
-			b.ToComponent(b.mb.Info(), obj, ctx),
+			fieldsContent,
 		),
 		VCardActions(actionButtons),
 	)
@@ -328,6 +376,7 @@ func (b *EditingBuilder) editFormFor(obj interface{}, ctx *web.EventContext) h.H
 
 	return web.Scope(
 		notice,
+		conflictDialog,
 		h.If(!b.mb.singleton,
 			VAppBar(
 				VToolbarTitle("").Class("pl-2").
@@ -340,6 +389,7 @@ func (b *EditingBuilder) editFormFor(obj interface{}, ctx *web.EventContext) h.H
 		),
 
 		VSheet(
+			leaseBanner,
 			VCard(asideContent).Flat(true),
 		).Class("pa-2"),
 	).VSlot("{ plaidForm }")
@@ -354,7 +404,7 @@ func (b *EditingBuilder) doDelete(ctx *web.EventContext) (r web.EventResponse, e
 	id := ctx.R.FormValue(ParamID)
 	var obj = b.mb.NewModel()
 	if len(id) > 0 {
-		err := b.Deleter(obj, id, ctx)
+		err := b.Delete(obj, id, ctx)
 		if err != nil {
 			ShowMessage(&r, err.Error(), "warning")
 			return
@@ -448,14 +498,37 @@ func (b *EditingBuilder) doUpdate(
 		}
 	}
 
-	if usingB.Validator != nil {
-		if vErr = usingB.Validator(obj, ctx); vErr.HaveErrors() {
+	if vErr = usingB.validate(obj, ctx); vErr.HaveErrors() {
+		usingB.UpdateOverlayContent(ctx, r, obj, "", &vErr)
+		return &vErr
+	}
+	if len(id) == 0 && usingB.wizard {
+		if vErr = usingB.validateWizardSteps(obj, ctx); vErr.HaveErrors() {
 			usingB.UpdateOverlayContent(ctx, r, obj, "", &vErr)
 			return &vErr
 		}
 	}
 
-	err1 := usingB.Saver(obj, id, ctx)
+	if len(id) > 0 {
+		if err1 := b.CheckEditLease(id, ctx); err1 != nil {
+			b.UpdateOverlayContent(ctx, r, obj, "", err1)
+			return err1
+		}
+	}
+
+	saveCtx := ctx
+	if len(id) > 0 && usingB.versionField != "" {
+		var err1 error
+		if saveCtx, err1 = usingB.lockVersion(obj, id, ctx); err1 != nil {
+			usingB.UpdateOverlayContent(ctx, r, obj, "", err1)
+			return err1
+		}
+	}
+
+	err1 := usingB.Save(obj, id, saveCtx)
+	if errors.Is(err1, ErrEditConflict) && usingB.versionField != "" {
+		err1 = usingB.editConflict(obj, id, ctx)
+	}
 	if err1 != nil {
 		usingB.UpdateOverlayContent(ctx, r, obj, "", err1)
 		return err1
@@ -533,7 +606,8 @@ func (b *EditingBuilder) UpdateOverlayContent(
 	ctx.Flash = err
 
 	if err != nil {
-		if _, ok := err.(*web.ValidationErrors); !ok {
+		_, isConflict := err.(*editConflictError)
+		if _, ok := err.(*web.ValidationErrors); !ok && !isConflict {
 			vErr := &web.ValidationErrors{}
 			vErr.GlobalError(err.Error())
 			ctx.Flash = vErr
@@ -4,4 +4,5 @@ import "errors"
 
 var (
 	ErrRecordNotFound = errors.New("record not found")
+	ErrNoTenant       = errors.New("no tenant")
 )
@@ -0,0 +1,307 @@
+package presets
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	. "github.com/qor5/ui/vuetify"
+	"github.com/qor5/web"
+	"github.com/qor5/x/i18n"
+	h "github.com/theplant/htmlgo"
+	"go.uber.org/zap"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"
+	ExportFormatXLSX ExportFormat = "xlsx"
+)
+
+const defaultExportBatchSize = 500
+
+type ExportFieldFormatFunc func(obj interface{}, field *FieldContext, ctx *web.EventContext) string
+
+type ExportFileNameFunc func(format ExportFormat, ctx *web.EventContext) string
+
+// ExportBuilder streams the current listing, with the same keyword, filters,
+// orderings and selected columns as the data table, to a CSV or XLSX file.
+type ExportBuilder struct {
+	lb           *ListingBuilder
+	formats      []ExportFormat
+	fields       []string
+	formatFuncs  map[string]ExportFieldFormatFunc
+	batchSize    int64
+	fileNameFunc ExportFileNameFunc
+}
+
+// Export enables exporting on the listing, the returned builder can be used to configure it.
+func (b *ListingBuilder) Export() (r *ExportBuilder) {
+	if b.exporter == nil {
+		b.exporter = &ExportBuilder{
+			lb:          b,
+			formats:     []ExportFormat{ExportFormatCSV, ExportFormatXLSX},
+			formatFuncs: make(map[string]ExportFieldFormatFunc),
+			batchSize:   defaultExportBatchSize,
+		}
+	}
+	return b.exporter
+}
+
+func (b *ExportBuilder) Formats(vs ...ExportFormat) (r *ExportBuilder) {
+	b.formats = vs
+	return b
+}
+
+// Only sets the fields to export, by default the displayed columns of the listing are exported.
+func (b *ExportBuilder) Only(vs ...string) (r *ExportBuilder) {
+	b.fields = vs
+	return b
+}
+
+// FieldFormatFunc sets the text of a field in the exported file,
+// by default the text content of the listing cell is used.
+func (b *ExportBuilder) FieldFormatFunc(name string, v ExportFieldFormatFunc) (r *ExportBuilder) {
+	b.formatFuncs[name] = v
+	return b
+}
+
+// BatchSize sets how many records are searched per query while exporting.
+func (b *ExportBuilder) BatchSize(v int64) (r *ExportBuilder) {
+	b.batchSize = v
+	return b
+}
+
+func (b *ExportBuilder) FileNameFunc(v ExportFileNameFunc) (r *ExportBuilder) {
+	b.fileNameFunc = v
+	return b
+}
+
+func (b *ExportBuilder) hasFormat(format ExportFormat) bool {
+	for _, f := range b.formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *ExportBuilder) fileName(format ExportFormat, ctx *web.EventContext) string {
+	if b.fileNameFunc != nil {
+		return b.fileNameFunc(format, ctx)
+	}
+	return fmt.Sprintf("%s-%s.%s", b.lb.mb.uriName, time.Now().Format("20060102150405"), format)
+}
+
+func (b *ExportBuilder) exportFields(ctx *web.EventContext) (r []*FieldBuilder) {
+	lb := b.lb
+	var fields []*FieldBuilder
+	switch {
+	case len(b.fields) > 0:
+		for _, name := range b.fields {
+			fields = append(fields, lb.Field(name))
+		}
+	case lb.selectableColumns:
+		_, _, fields = lb.displaySortedColumns(ctx.R.URL, ctx)
+	default:
+		fields = lb.fields
+	}
+
+	for _, f := range fields {
+		if !lb.mb.Info().FieldAllowed(PermList, nil, f.name, ctx.R) {
+			continue
+		}
+		r = append(r, lb.getFieldOrDefault(f.name))
+	}
+	return
+}
+
+var htmlTagReg = regexp.MustCompile(`<[^>]*>`)
+
+// componentText renders the component and returns its text content.
+func componentText(comp h.HTMLComponent, ctx *web.EventContext) (r string, err error) {
+	if comp == nil {
+		return
+	}
+	bs, err := comp.MarshalHTML(ctx.R.Context())
+	if err != nil {
+		return
+	}
+	r = html.UnescapeString(htmlTagReg.ReplaceAllString(string(bs), " "))
+	return strings.Join(strings.Fields(r), " "), nil
+}
+
+func (b *ExportBuilder) fieldText(obj interface{}, f *FieldBuilder, ctx *web.EventContext) (r string, err error) {
+	field := b.lb.mb.getComponentFuncField(f)
+	if ff, ok := b.formatFuncs[f.name]; ok {
+		return ff(obj, field, ctx), nil
+	}
+	return componentText(f.compFunc(obj, field, ctx), ctx)
+}
+
+type exportRecordWriter interface {
+	Write(record []string) error
+	Close() error
+}
+
+type csvRecordWriter struct {
+	*csv.Writer
+}
+
+func (w *csvRecordWriter) Close() error {
+	w.Writer.Flush()
+	return w.Writer.Error()
+}
+
+func newExportRecordWriter(format ExportFormat, w io.Writer) (r exportRecordWriter, err error) {
+	switch format {
+	case ExportFormatCSV:
+		return &csvRecordWriter{Writer: csv.NewWriter(w)}, nil
+	case ExportFormatXLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, fmt.Errorf("unsupported export format: %s", format)
+}
+
+func exportContentType(format ExportFormat) string {
+	if format == ExportFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// export pages through the Searcher with the listing's SearchParams,
+// so that big tables are not loaded into memory at once.
+func (b *ExportBuilder) export(format ExportFormat, w io.Writer, ctx *web.EventContext) (err error) {
+	lb := b.lb
+	if lb.Searcher == nil {
+		return fmt.Errorf("presets.New().DataOperator(...) required")
+	}
+
+	fields := b.exportFields(ctx)
+	rw, err := newExportRecordWriter(format, w)
+	if err != nil {
+		return
+	}
+
+	header := make([]string, 0, len(fields))
+	for _, f := range fields {
+		header = append(header, i18n.PT(ctx.R, ModelsI18nModuleKey, lb.mb.label, lb.mb.getLabel(f.NameLabel)))
+	}
+	if err = rw.Write(header); err != nil {
+		return
+	}
+
+	batchSize := b.batchSize
+	if batchSize <= 0 {
+		batchSize = defaultExportBatchSize
+	}
+	searchParams := lb.newSearchParams(ctx, batchSize)
+	if searchParams.Keyset != nil {
+		// export all the records from the first one, not the page of the cursor
+		searchParams.Keyset.After = nil
+		searchParams.Keyset.Backward = false
+		searchParams.OrderBy = searchParams.Keyset.OrderBySQL()
+	}
+	var exported int
+	for searchParams.Page = 1; ; searchParams.Page++ {
+		objs, totalCount, err1 := lb.Searcher(lb.mb.NewModelSlice(), searchParams, ctx)
+		if err1 != nil {
+			return err1
+		}
+
+		objsValue := reflect.ValueOf(objs)
+		for i := 0; i < objsValue.Len(); i++ {
+			obj := objsValue.Index(i).Interface()
+			record := make([]string, 0, len(fields))
+			for _, f := range fields {
+				text, err1 := b.fieldText(obj, f, ctx)
+				if err1 != nil {
+					return err1
+				}
+				record = append(record, text)
+			}
+			if err = rw.Write(record); err != nil {
+				return
+			}
+		}
+
+		exported += objsValue.Len()
+		if int64(objsValue.Len()) < batchSize {
+			break
+		}
+		if searchParams.Keyset != nil {
+			// the count is skipped in keyset pagination mode
+			searchParams.Keyset.After = searchParams.Keyset.Values(objsValue.Index(objsValue.Len() - 1).Interface())
+			continue
+		}
+		if exported >= totalCount {
+			break
+		}
+	}
+
+	return rw.Close()
+}
+
+func (b *ExportBuilder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := &web.EventContext{R: r, W: w}
+	if b.lb.mb.Info().Verifier().Do(PermList).WithReq(r).IsAllowed() != nil {
+		http.Error(w, "permission denied", http.StatusForbidden)
+		return
+	}
+
+	format := ExportFormat(r.URL.Query().Get(ParamExportFormat))
+	if format == "" {
+		format = b.formats[0]
+	}
+	if !b.hasFormat(format) {
+		http.Error(w, fmt.Sprintf("unsupported export format: %s", format), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", exportContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, b.fileName(format, ctx)))
+	if err := b.export(format, w, ctx); err != nil {
+		// the headers are most likely already sent, so just stop writing
+		b.lb.mb.p.logger.Error("export failed", zap.String("model", b.lb.mb.uriName), zap.Error(err))
+	}
+}
+
+func (b *ExportBuilder) button(ctx *web.EventContext) h.HTMLComponent {
+	msgr := MustGetMessages(ctx.R)
+	if b.lb.mb.Info().Verifier().Do(PermList).WithReq(ctx.R).IsAllowed() != nil {
+		return nil
+	}
+
+	// keep the current keyword, filters, orderings and selected columns
+	qs := ctx.R.URL.Query()
+	qs.Del("__execute_event__")
+	qs.Del(ParamSelectedIds)
+
+	var items []h.HTMLComponent
+	for _, format := range b.formats {
+		qs.Set(ParamExportFormat, string(format))
+		items = append(items, VListItem(
+			VListItemTitle(h.Text(strings.ToUpper(string(format)))),
+		).Href(fmt.Sprintf("%s?%s", b.lb.mb.Info().ExportHref(), qs.Encode())))
+	}
+
+	return VMenu(
+		web.Slot(
+			VBtn(msgr.Export).
+				Color(ColorPrimary).
+				Depressed(true).
+				Class("ml-2").
+				Attr("v-bind", "attrs").
+				Attr("v-on", "on"),
+		).Name("activator").Scope("{ on, attrs }"),
+		VList(items...).Dense(true),
+	).OffsetY(true)
+}
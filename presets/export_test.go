@@ -0,0 +1,108 @@
+package presets
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qor5/web"
+)
+
+type exportItem struct {
+	ID   uint
+	Name string
+}
+
+func TestExportCSV(t *testing.T) {
+	var items []*exportItem
+	for i := 1; i <= 5; i++ {
+		items = append(items, &exportItem{ID: uint(i), Name: strings.Repeat("a", i)})
+	}
+
+	var pages []int64
+	b := New()
+	lb := b.Model(&exportItem{}).Listing("ID", "Name")
+	lb.SearchFunc(func(model interface{}, params *SearchParams, ctx *web.EventContext) (r interface{}, totalCount int, err error) {
+		pages = append(pages, params.Page)
+		start := int((params.Page - 1) * params.PerPage)
+		end := start + int(params.PerPage)
+		if end > len(items) {
+			end = len(items)
+		}
+		return items[start:end], len(items), nil
+	})
+	lb.Export().
+		BatchSize(2).
+		FieldFormatFunc("Name", func(obj interface{}, field *FieldContext, ctx *web.EventContext) string {
+			return strings.ToUpper(obj.(*exportItem).Name)
+		})
+
+	r := httptest.NewRequest("GET", "/admin/export-items/export?export_format=csv", nil)
+	w := httptest.NewRecorder()
+	lb.Export().ServeHTTP(w, r)
+
+	excepted := "ID,Name\n1,A\n2,AA\n3,AAA\n4,AAAA\n5,AAAAA\n"
+	if w.Body.String() != excepted {
+		t.Errorf("export = %q, excepted %q", w.Body.String(), excepted)
+	}
+	if len(pages) != 3 {
+		t.Errorf("searched pages = %v, excepted 3 pages", pages)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	r = httptest.NewRequest("GET", "/admin/export-items/export?export_format=pdf", nil)
+	w = httptest.NewRecorder()
+	lb.Export().ServeHTTP(w, r)
+	if w.Code != 400 {
+		t.Errorf("unsupported format status = %d, excepted 400", w.Code)
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := newXLSXWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]string{"Name", "Note"})
+	w.Write([]string{"a&b", "<tag>"})
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sheet string
+	for _, f := range zr.File {
+		if f.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bs, _ := io.ReadAll(rc)
+		rc.Close()
+		sheet = string(bs)
+	}
+	if len(zr.File) != 5 {
+		t.Errorf("files = %d, excepted 5", len(zr.File))
+	}
+	for _, s := range []string{
+		`<t xml:space="preserve">Name</t>`,
+		`<t xml:space="preserve">a&amp;b</t>`,
+		`<t xml:space="preserve">&lt;tag&gt;</t>`,
+		`</sheetData></worksheet>`,
+	} {
+		if !strings.Contains(sheet, s) {
+			t.Errorf("sheet %q does not contain %q", sheet, s)
+		}
+	}
+}
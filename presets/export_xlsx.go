@@ -0,0 +1,87 @@
+package presets
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+)
+
+const (
+	xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+	xlsxRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+	xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`
+	xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+	xlsxSheetBegin = xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd   = `</sheetData></worksheet>`
+)
+
+// xlsxWriter writes records as inline string cells of a single sheet workbook,
+// the sheet is the last entry of the zip file, so rows can be streamed without buffering.
+type xlsxWriter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+}
+
+func newXLSXWriter(w io.Writer) (r *xlsxWriter, err error) {
+	zw := zip.NewWriter(w)
+	for _, f := range []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	} {
+		var fw io.Writer
+		if fw, err = zw.Create(f.name); err != nil {
+			return
+		}
+		if _, err = io.WriteString(fw, f.content); err != nil {
+			return
+		}
+	}
+
+	sw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return
+	}
+	r = &xlsxWriter{zw: zw, sheet: bufio.NewWriter(sw)}
+	_, err = r.sheet.WriteString(xlsxSheetBegin)
+	return
+}
+
+func (w *xlsxWriter) Write(record []string) (err error) {
+	w.sheet.WriteString("<row>")
+	for _, v := range record {
+		w.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		if err = xml.EscapeText(w.sheet, []byte(v)); err != nil {
+			return
+		}
+		w.sheet.WriteString("</t></is></c>")
+	}
+	_, err = w.sheet.WriteString("</row>")
+	return
+}
+
+func (w *xlsxWriter) Close() (err error) {
+	if _, err = w.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return
+	}
+	if err = w.sheet.Flush(); err != nil {
+		return
+	}
+	return w.zw.Close()
+}
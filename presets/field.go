@@ -63,11 +63,17 @@ func (fc *FieldContext) ContextValue(key interface{}) (r interface{}) {
 
 type FieldBuilder struct {
 	NameLabel
-	compFunc            FieldComponentFunc
-	setterFunc          FieldSetterFunc
+	compFunc   FieldComponentFunc
+	setterFunc FieldSetterFunc
+	// customSetter is false for the setters of the field defaults, see the decode of the REST API
+	customSetter        bool
 	context             context.Context
 	rt                  reflect.Type
 	nestedFieldsBuilder *FieldsBuilder
+	// editable is only for the listing fields, see Editable
+	editable bool
+	// validation is the rules of the editing fields, see Validate
+	validation string
 }
 
 func (b *FieldsBuilder) appendNewFieldWithName(name string) (r *FieldBuilder) {
@@ -111,6 +117,9 @@ func (b *FieldBuilder) Clone() (r *FieldBuilder) {
 	r.label = b.label
 	r.compFunc = b.compFunc
 	r.setterFunc = b.setterFunc
+	r.customSetter = b.customSetter
+	r.editable = b.editable
+	r.validation = b.validation
 	return r
 }
 
@@ -124,6 +133,13 @@ func (b *FieldBuilder) ComponentFunc(v FieldComponentFunc) (r *FieldBuilder) {
 
 func (b *FieldBuilder) SetterFunc(v FieldSetterFunc) (r *FieldBuilder) {
 	b.setterFunc = v
+	b.customSetter = true
+	return b
+}
+
+func (b *FieldBuilder) defaultSetterFunc(v FieldSetterFunc) (r *FieldBuilder) {
+	b.setterFunc = v
+	b.customSetter = false
 	return b
 }
 
@@ -260,10 +276,9 @@ func (b *FieldsBuilder) SetObjectFields(fromObj interface{}, toObj interface{},
 
 	for _, f := range b.fields {
 		info := parent.ModelInfo
-		if info != nil {
-			if info.Verifier().Do(PermCreate).ObjectOn(toObj).SnakeOn("f_"+f.name).WithReq(ctx.R).IsAllowed() != nil && info.Verifier().Do(PermUpdate).ObjectOn(toObj).SnakeOn("f_"+f.name).WithReq(ctx.R).IsAllowed() != nil {
-				continue
-			}
+		// the forbidden fields are ignored even if they are submitted
+		if info != nil && !info.FieldWritable(toObj, f.name, info.creatingRequest(ctx.R), ctx.R) {
+			continue
 		}
 
 		if f.nestedFieldsBuilder != nil {
@@ -497,7 +512,7 @@ func (b *FieldsBuilder) getFieldOrDefault(name string) (r *FieldBuilder) {
 
 		ft := b.defaults.fieldTypeByTypeOrCreate(fType)
 		r.ComponentFunc(ft.compFunc).
-			SetterFunc(ft.setterFunc)
+			defaultSetterFunc(ft.setterFunc)
 	}
 	return
 }
@@ -583,15 +598,15 @@ func (b *FieldsBuilder) String() (r string) {
 
 func (b *FieldsBuilder) ToComponent(info *ModelInfo, obj interface{}, ctx *web.EventContext) h.HTMLComponent {
 	modifiedIndexes := ContextModifiedIndexesBuilder(ctx)
-	return b.toComponentWithFormValueKey(info, obj, "", modifiedIndexes, ctx)
+	return h.Components(
+		modifiedIndexes.ToFormHidden(),
+		b.toComponentWithFormValueKey(info, obj, "", modifiedIndexes, ctx),
+	)
 }
 
 func (b *FieldsBuilder) toComponentWithFormValueKey(info *ModelInfo, obj interface{}, parentFormValueKey string, modifiedIndexes *ModifiedIndexesBuilder, ctx *web.EventContext) h.HTMLComponent {
 
 	var comps []h.HTMLComponent
-	if parentFormValueKey == "" {
-		comps = append(comps, modifiedIndexes.ToFormHidden())
-	}
 
 	vErr, _ := ctx.Flash.(*web.ValidationErrors)
 	if vErr == nil {
@@ -682,7 +697,7 @@ func (b *FieldsBuilder) fieldToComponentWithFormValueKey(info *ModelInfo, obj in
 	// if f.compFunc == nil {
 	// 	return nil
 	// }
-	if info != nil && info.Verifier().Do(PermGet).ObjectOn(obj).SnakeOn("f_"+f.name).WithReq(ctx.R).IsAllowed() != nil {
+	if info != nil && !info.FieldAllowed(PermGet, obj, f.name, ctx.R) {
 		return nil
 	}
 
@@ -698,18 +713,14 @@ func (b *FieldsBuilder) fieldToComponentWithFormValueKey(info *ModelInfo, obj in
 
 	disabled := false
 	if info != nil {
-		if edit {
-			disabled = info.Verifier().Do(PermUpdate).ObjectOn(obj).SnakeOn("f_"+f.name).WithReq(ctx.R).IsAllowed() != nil
-		} else {
-			disabled = info.Verifier().Do(PermCreate).ObjectOn(obj).SnakeOn("f_"+f.name).WithReq(ctx.R).IsAllowed() != nil
-		}
+		disabled = !info.FieldWritable(obj, f.name, !edit, ctx.R)
 	}
 	return f.compFunc(obj, &FieldContext{
 		ModelInfo:           info,
 		Name:                f.name,
 		FormKey:             contextKeyPath,
 		Label:               label,
-		Errors:              vErr.GetFieldErrors(f.name),
+		Errors:              vErr.GetFieldErrors(contextKeyPath),
 		NestedFieldsBuilder: f.nestedFieldsBuilder,
 		Context:             f.context,
 		Disabled:            disabled,
@@ -113,7 +113,7 @@ func (b *FieldDefaults) inspectFieldsAndCollectName(val interface{}, collectType
 		if !hasMatched(b.excludesPatterns, f.Name) && ft != nil {
 			r.Field(f.Name).
 				ComponentFunc(ft.compFunc).
-				SetterFunc(ft.setterFunc)
+				defaultSetterFunc(ft.setterFunc)
 		}
 
 		if collectType != nil && f.Type == collectType {
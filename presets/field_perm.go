@@ -0,0 +1,65 @@
+package presets
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+)
+
+// Every field of a model has its own permission resource, which is the resource of the model or the record
+// with the "f_" prefixed snake case field name, like ":presets:products:products:1:f_price:", so the policies like
+//
+//	perm.PolicyFor(perm.Anybody).WhoAre(perm.Denied).ToDo(presets.PermUpdate).On("*:products:*f_price:")
+//
+// make the price readonly. PermList hides the column of the listing and the export, PermGet hides the field
+// of the detailing and editing, PermCreate and PermUpdate disable it in the form and ignore it when it is submitted.
+
+// FieldAllowed checks the permission of the action on the field of the model, obj can be nil for the checks
+// that are not about a record, like the columns of the listing.
+func (b ModelInfo) FieldAllowed(action string, obj interface{}, field string, r *http.Request) bool {
+	v := b.Verifier().Do(action)
+	if obj != nil {
+		v = v.ObjectOn(obj)
+	}
+	return v.SnakeOn("f_"+field).WithReq(r).IsAllowed() == nil
+}
+
+// FieldWritable checks PermCreate on the field of the new record, and PermUpdate of the existing one,
+// the fields of the record that is being edited by someone else are not writable, see EditingBuilder.EditLease.
+func (b ModelInfo) FieldWritable(obj interface{}, field string, creating bool, r *http.Request) bool {
+	if creating {
+		return b.FieldAllowed(PermCreate, obj, field, r)
+	}
+	if editLeaseReadonly(r) {
+		return false
+	}
+	return b.FieldAllowed(PermUpdate, obj, field, r)
+}
+
+// creatingRequest tells whether the form of the request creates a new record, the singleton is always updated.
+func (b ModelInfo) creatingRequest(r *http.Request) bool {
+	return !b.mb.singleton && r.FormValue(ParamID) == ""
+}
+
+// readableJSON encodes obj to a JSON object without the fields that are not allowed for the action.
+func (b ModelInfo) readableJSON(action string, obj interface{}, r *http.Request) (v interface{}, err error) {
+	bs, err := json.Marshal(obj)
+	if err != nil {
+		return
+	}
+	var m map[string]json.RawMessage
+	if err = json.Unmarshal(bs, &m); err != nil {
+		return
+	}
+	for _, f := range reflect.VisibleFields(b.mb.modelType.Elem()) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		key := jsonFieldName(b.mb.modelType.Elem(), f.Name)
+		if _, ok := m[key]; !ok || b.FieldAllowed(action, obj, f.Name, r) {
+			continue
+		}
+		delete(m, key)
+	}
+	return m, nil
+}
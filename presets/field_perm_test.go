@@ -0,0 +1,78 @@
+package presets
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qor5/web"
+	"github.com/qor5/x/perm"
+)
+
+func TestFieldPermissions(t *testing.T) {
+	op := &restAPIItemsOperator{items: map[string]*restAPIItem{
+		"1": {ID: "1", Name: "A", Price: 1, Note: "secret"},
+	}}
+	b := New().URIPrefix("/admin").RESTAPIPrefix("/api").DataOperator(op).
+		Permission(perm.New().Policies(
+			perm.PolicyFor(perm.Anybody).WhoAre(perm.Allowed).ToDo(perm.Anything).On(perm.Anything),
+			perm.PolicyFor(perm.Anybody).WhoAre(perm.Denied).ToDo(PermUpdate).On("*:rest_api_items:*f_price:"),
+			perm.PolicyFor(perm.Anybody).WhoAre(perm.Denied).ToDo(PermGet, PermList).On("*:rest_api_items:*f_note:"),
+		))
+	mb := b.Model(&restAPIItem{})
+	mb.Editing("Name", "Price", "Note")
+
+	unmarshal := func(id string, obj *restAPIItem) {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		mw.WriteField(ParamID, id)
+		mw.WriteField("Name", "B")
+		mw.WriteField("Price", "100")
+		mw.Close()
+		req := httptest.NewRequest("POST", "/admin/rest-api-items", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		req.ParseMultipartForm(1 << 20)
+		if vErr := mb.editing.Unmarshal(obj, mb.Info(), true, &web.EventContext{R: req, W: httptest.NewRecorder()}); vErr.HaveErrors() {
+			t.Fatal(vErr.Error())
+		}
+	}
+
+	// the forbidden fields are ignored even if they are submitted
+	obj := &restAPIItem{ID: "1", Name: "A", Price: 1}
+	unmarshal("1", obj)
+	if obj.Name != "B" || obj.Price != 1 {
+		t.Errorf("updated obj = %+v", obj)
+	}
+	// the price can be set when creating
+	obj = &restAPIItem{}
+	unmarshal("", obj)
+	if obj.Name != "B" || obj.Price != 100 {
+		t.Errorf("created obj = %+v", obj)
+	}
+
+	for _, c := range []struct {
+		method   string
+		path     string
+		body     string
+		excepted string
+	}{
+		{"GET", "/admin/api/rest-api-items", "", `{"data":[{"ID":"1","name":"A","Price":1}],"total":1,"page":1,"per_page":50}`},
+		{"GET", "/admin/api/rest-api-items/1", "", `{"ID":"1","name":"A","Price":1}`},
+		{"PATCH", "/admin/api/rest-api-items/1", `{"name":"C","Price":10}`, `{"ID":"1","name":"C","Price":1}`},
+	} {
+		r := httptest.NewRequest(c.method, c.path, strings.NewReader(c.body))
+		w := httptest.NewRecorder()
+		b.ServeHTTP(w, r)
+		var got, excepted interface{}
+		json.Unmarshal(w.Body.Bytes(), &got)
+		json.Unmarshal([]byte(c.excepted), &excepted)
+		gotJSON, _ := json.Marshal(got)
+		exceptedJSON, _ := json.Marshal(excepted)
+		if string(gotJSON) != string(exceptedJSON) {
+			t.Errorf("%s %s: body = %s, excepted %s", c.method, c.path, gotJSON, exceptedJSON)
+		}
+	}
+}
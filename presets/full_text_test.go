@@ -0,0 +1,42 @@
+package presets
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qor5/web"
+)
+
+type fullTextItem struct {
+	ID    uint
+	Title string
+}
+
+func TestFullTextSearchParams(t *testing.T) {
+	b := New().DataOperator(&restAPIItemsOperator{})
+	lb := b.Model(&fullTextItem{}).Listing().
+		SearchColumns("title").
+		FullTextSearch(true).
+		OrderableFields([]*OrderableField{{FieldName: "Title", DBColumn: "title"}})
+
+	for _, c := range []struct {
+		url       string
+		relevance bool
+	}{
+		{"/full-text-items?keyword=go+fast", true},
+		{"/full-text-items", false},
+		{"/full-text-items?keyword=go&order_by=Title_ASC", false},
+	} {
+		ctx := &web.EventContext{R: httptest.NewRequest("GET", c.url, nil), W: httptest.NewRecorder()}
+		sp := lb.newSearchParams(ctx, 10)
+		if !sp.FullText || sp.OrderByRelevance != c.relevance {
+			t.Errorf("%s: full text = %v, relevance = %v", c.url, sp.FullText, sp.OrderByRelevance)
+		}
+	}
+
+	lb.KeysetPagination(true)
+	ctx := &web.EventContext{R: httptest.NewRequest("GET", "/full-text-items?keyword=go", nil), W: httptest.NewRecorder()}
+	if sp := lb.newSearchParams(ctx, 10); sp.OrderByRelevance {
+		t.Errorf("relevance in keyset pagination")
+	}
+}
@@ -0,0 +1,175 @@
+package presets
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/qor5/admin/presets/actions"
+	. "github.com/qor5/ui/vuetify"
+	vx "github.com/qor5/ui/vuetifyx"
+	"github.com/qor5/web"
+	"github.com/qor5/x/i18n"
+	h "github.com/theplant/htmlgo"
+)
+
+const globalSearchResultsPortalName = "presets_GlobalSearchResultsPortal"
+
+// GlobalSearchBuilder adds the model to the global search of the layout,
+// the records are searched by the search columns of the listing or the Searcher.
+type GlobalSearchBuilder struct {
+	lb        *ListingBuilder
+	Searcher  SearchFunc
+	titleFunc func(obj interface{}, ctx *web.EventContext) string
+	limit     int64
+}
+
+// GlobalSearch adds the model to the global search, the search columns of the listing
+// or SearchFunc(...) are required. Only the users with PermList on the model see its results.
+func (b *ListingBuilder) GlobalSearch() (r *GlobalSearchBuilder) {
+	if b.globalSearch != nil {
+		return b.globalSearch
+	}
+
+	b.globalSearch = &GlobalSearchBuilder{lb: b, limit: 5}
+	return b.globalSearch
+}
+
+// SearchFunc searches the records by the keyword of the params, instead of the search columns of the listing.
+func (b *GlobalSearchBuilder) SearchFunc(v SearchFunc) (r *GlobalSearchBuilder) {
+	b.Searcher = v
+	return b
+}
+
+// TitleFunc sets the title of the result, by default it is the PageTitle() of the record or its id.
+func (b *GlobalSearchBuilder) TitleFunc(v func(obj interface{}, ctx *web.EventContext) string) (r *GlobalSearchBuilder) {
+	b.titleFunc = v
+	return b
+}
+
+// Limit sets the max number of the results of the model, the default is 5.
+func (b *GlobalSearchBuilder) Limit(v int64) (r *GlobalSearchBuilder) {
+	b.limit = v
+	return b
+}
+
+func (b *Builder) hasGlobalSearch() bool {
+	for _, m := range b.models {
+		if m.listing.globalSearch != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *GlobalSearchBuilder) search(keyword string, ctx *web.EventContext) (r interface{}, err error) {
+	lb := b.lb
+	searcher := b.Searcher
+	if searcher == nil {
+		if len(lb.searchColumns) == 0 {
+			panic(fmt.Sprintf("%s: listing SearchColumns(...) or GlobalSearch().SearchFunc(...) required", lb.mb.uriName))
+		}
+		searcher = lb.Searcher
+	}
+
+	orderBy := lb.orderBy
+	if orderBy == "" {
+		orderBy = fmt.Sprintf("%s DESC", lb.mb.primaryField)
+	}
+	r, _, err = searcher(lb.mb.NewModelSlice(), &SearchParams{
+		KeywordColumns:   lb.searchColumns,
+		Keyword:          keyword,
+		PerPage:          b.limit,
+		Page:             1,
+		OrderBy:          orderBy,
+		SQLConditions:    lb.conditions,
+		FullText:         lb.fullTextSearch,
+		OrderByRelevance: lb.fullTextSearch,
+	}, ctx)
+	return
+}
+
+func (b *GlobalSearchBuilder) title(obj interface{}, ctx *web.EventContext) string {
+	if b.titleFunc != nil {
+		return b.titleFunc(obj, ctx)
+	}
+	return getPageTitle(obj, vx.ObjectID(obj))
+}
+
+// onclick opens the record like the listing does, the editing drawer is loaded from the listing of the model.
+func (b *GlobalSearchBuilder) onclick(id string) string {
+	return b.lb.recordOnclick(id)
+}
+
+func (b *Builder) globalSearchDialog(ctx *web.EventContext) h.HTMLComponent {
+	msgr := MustGetMessages(ctx.R)
+	return VDialog(
+		VCard(
+			VCardTitle(
+				VTextField().
+					PrependIcon("search").
+					Label(msgr.GlobalSearch).
+					Autofocus(true).
+					Clearable(true).
+					HideDetails(true).
+					Attr("@keyup.enter", web.Plaid().
+						EventFunc(actions.GlobalSearch).
+						Query(ParamGlobalSearchKeyword, web.Var("[$event.target.value]")).
+						Go()),
+			),
+			VCardText(
+				web.Portal().Name(globalSearchResultsPortalName),
+			),
+		),
+	).MaxWidth("600px").
+		Scrollable(true).
+		Attr("v-model", "vars.presetsGlobalSearch").
+		Attr(web.InitContextVars, `{presetsGlobalSearch: false}`)
+}
+
+// globalSearch searches the keyword in the models of the global search, the results are grouped by the models.
+func (b *Builder) globalSearch(ctx *web.EventContext) (r web.EventResponse, err error) {
+	msgr := MustGetMessages(ctx.R)
+	keyword := ctx.R.FormValue(ParamGlobalSearchKeyword)
+
+	var items h.HTMLComponents
+	if keyword != "" {
+		for _, m := range b.models {
+			gs := m.listing.globalSearch
+			if gs == nil || m.Info().Verifier().Do(PermList).WithReq(ctx.R).IsAllowed() != nil {
+				continue
+			}
+
+			objs, err1 := gs.search(keyword, ctx)
+			if err1 != nil {
+				ShowMessage(&r, err1.Error(), "warning")
+				continue
+			}
+			objsV := reflect.ValueOf(objs)
+			if objsV.Len() == 0 {
+				continue
+			}
+
+			items = append(items, VSubheader(h.Text(i18n.T(ctx.R, ModelsI18nModuleKey, m.label))))
+			for i := 0; i < objsV.Len(); i++ {
+				obj := objsV.Index(i).Interface()
+				id := vx.ObjectID(obj)
+				items = append(items, VListItem(
+					VListItemContent(
+						VListItemTitle(h.Text(gs.title(obj, ctx))),
+					),
+				).Link(true).
+					Attr("@click", gs.onclick(id)+"; vars.presetsGlobalSearch = false"))
+			}
+		}
+	}
+
+	var body h.HTMLComponent = VList(items...).Dense(true)
+	if keyword != "" && len(items) == 0 {
+		body = h.Div(h.Text(msgr.GlobalSearchNoResults)).Class("pt-4 text-center grey--text")
+	}
+	r.UpdatePortals = append(r.UpdatePortals, &web.PortalUpdate{
+		Name: globalSearchResultsPortalName,
+		Body: body,
+	})
+	return
+}
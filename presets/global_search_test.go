@@ -0,0 +1,72 @@
+package presets
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qor5/web"
+	"github.com/qor5/x/perm"
+)
+
+type globalSearchItem struct {
+	ID   uint
+	Name string
+}
+
+func (i *globalSearchItem) PageTitle() string {
+	return i.Name
+}
+
+type globalSearchSecret struct {
+	ID   uint
+	Name string
+}
+
+func TestGlobalSearch(t *testing.T) {
+	b := New().DataOperator(&restAPIItemsOperator{}).
+		Permission(perm.New().Policies(
+			perm.PolicyFor(perm.Anybody).WhoAre(perm.Allowed).ToDo(perm.Anything).On(perm.Anything),
+			perm.PolicyFor(perm.Anybody).WhoAre(perm.Denied).ToDo(PermList).On("*:global_search_secrets:*"),
+		))
+	search := func(obj interface{}, params *SearchParams, ctx *web.EventContext) (r interface{}, totalCount int, err error) {
+		var items []*globalSearchItem
+		for _, name := range []string{"apple", "banana", "apricot"} {
+			if strings.HasPrefix(name, params.Keyword) {
+				items = append(items, &globalSearchItem{ID: uint(len(items) + 1), Name: name})
+			}
+		}
+		return items, len(items), nil
+	}
+	b.Model(&globalSearchItem{}).Listing().GlobalSearch().SearchFunc(search)
+	b.Model(&globalSearchSecret{}).Listing().GlobalSearch().SearchFunc(search)
+	if !b.hasGlobalSearch() {
+		t.Fatalf("global search is not enabled")
+	}
+
+	globalSearch := func(keyword string) string {
+		ctx := &web.EventContext{
+			R: httptest.NewRequest("POST", "/?__execute_event__=presets_GlobalSearch&presets_global_search_keyword="+keyword, nil),
+			W: httptest.NewRecorder(),
+		}
+		r, err := b.globalSearch(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := r.UpdatePortals[0].Body.MarshalHTML(context.TODO())
+		return string(content)
+	}
+
+	content := globalSearch("ap")
+	if !strings.Contains(content, "apple") || !strings.Contains(content, "apricot") || strings.Contains(content, "banana") {
+		t.Errorf("content = %s", content)
+	}
+	if !strings.Contains(content, "GlobalSearchItems") || strings.Count(content, "apple") != 1 {
+		t.Errorf("results of the denied model are shown: %s", content)
+	}
+
+	if content = globalSearch("cherry"); !strings.Contains(content, Messages_en_US.GlobalSearchNoResults) {
+		t.Errorf("content = %s", content)
+	}
+}
@@ -0,0 +1,75 @@
+package gorm2op
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/qor5/admin/presets"
+	"github.com/qor5/web"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Aggregate computes the aggregates of the fields in one query with the same conditions as Search.
+// The sums and the averages are float64, the counts are int64, and the mins and the maxes are the values of the driver,
+// nil if no records match.
+func (op *DataOperatorBuilder) Aggregate(obj interface{}, params *presets.SearchParams, aggregates []*presets.Aggregate, ctx *web.EventContext) (r []interface{}, err error) {
+	if len(aggregates) == 0 {
+		return
+	}
+	db, _, err := op.tenantWhere(op.dbFrom(ctx), obj, ctx)
+	if err != nil {
+		return
+	}
+	wh, _, err := op.searchWhere(db, obj, params)
+	if err != nil {
+		return
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err = stmt.Parse(obj); err != nil {
+		return
+	}
+	var selects []string
+	var cols []interface{}
+	dests := make([]interface{}, len(aggregates))
+	for i, a := range aggregates {
+		field := stmt.Schema.LookUpField(a.FieldName)
+		if field == nil || field.DBName == "" {
+			return nil, fmt.Errorf("no field %s in %s", a.FieldName, stmt.Schema.Name)
+		}
+		switch a.Kind {
+		case presets.AggregateSum, presets.AggregateAvg:
+			dests[i] = &sql.NullFloat64{}
+		case presets.AggregateCount:
+			dests[i] = new(int64)
+		case presets.AggregateMin, presets.AggregateMax:
+			dests[i] = new(interface{})
+		default:
+			return nil, fmt.Errorf("unknown aggregate %s of %s", a.Kind, a.FieldName)
+		}
+		selects = append(selects, fmt.Sprintf("%s(?)", strings.ToUpper(string(a.Kind))))
+		cols = append(cols, clause.Column{Table: stmt.Schema.Table, Name: field.DBName})
+	}
+
+	if err = wh.Select(strings.Join(selects, ", "), cols...).Row().Scan(dests...); err != nil {
+		return
+	}
+
+	for _, d := range dests {
+		switch v := d.(type) {
+		case *sql.NullFloat64:
+			if v.Valid {
+				r = append(r, v.Float64)
+			} else {
+				r = append(r, nil)
+			}
+		case *int64:
+			r = append(r, *v)
+		case *interface{}:
+			r = append(r, *v)
+		}
+	}
+	return
+}
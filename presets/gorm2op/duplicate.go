@@ -0,0 +1,101 @@
+package gorm2op
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/qor5/admin/presets"
+	"github.com/qor5/web"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Duplicate enables the duplicate of the model, see presets.ModelBuilder.Duplicate, with the has-many and has-one
+// children that are preloaded and created with the copy. The primary keys and the auto create/update times
+// of the copy and its children are reset by the gorm schema, the other has-many and has-one associations are
+// not copied, and the many-to-many associations are copied if they are loaded.
+func (op *DataOperatorBuilder) Duplicate(mb *presets.ModelBuilder, children ...string) (r *presets.DuplicateBuilder) {
+	stmt := &gorm.Statement{DB: op.db}
+	if err := stmt.Parse(mb.NewModel()); err != nil {
+		panic(err)
+	}
+	copied := map[string]bool{}
+	for _, name := range children {
+		rel, ok := stmt.Schema.Relationships.Relations[name]
+		if !ok || (rel.Type != schema.HasMany && rel.Type != schema.HasOne) {
+			panic(fmt.Sprintf("%s has no has-many or has-one association %s", stmt.Schema.Name, name))
+		}
+		copied[name] = true
+	}
+
+	r = mb.Duplicate()
+	r.FetchFunc(func(obj interface{}, id string, ctx *web.EventContext) (r interface{}, err error) {
+		db, _, err := op.tenantWhere(op.dbFrom(ctx), obj, ctx)
+		if err != nil {
+			return
+		}
+		for _, name := range children {
+			db = db.Preload(name)
+		}
+		if err = op.primarySluggerWhere(db, obj, id).First(obj).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, presets.ErrRecordNotFound
+			}
+			return
+		}
+		return obj, nil
+	})
+	r.ResetFunc(func(from interface{}, to interface{}, ctx *web.EventContext) (err error) {
+		rctx := ctx.R.Context()
+		rv := reflect.ValueOf(to)
+		if err = resetDuplicate(rctx, stmt.Schema, rv); err != nil {
+			return
+		}
+		for name, rel := range stmt.Schema.Relationships.Relations {
+			if rel.Type != schema.HasMany && rel.Type != schema.HasOne {
+				continue
+			}
+			if !copied[name] {
+				if err = rel.Field.Set(rctx, rv, reflect.Zero(rel.Field.FieldType).Interface()); err != nil {
+					return
+				}
+				continue
+			}
+			// the foreign keys are set to the copy by gorm when it is created
+			child := reflect.Indirect(rel.Field.ReflectValueOf(rctx, rv))
+			if child.Kind() != reflect.Slice {
+				if child.IsValid() {
+					if err = resetDuplicate(rctx, rel.FieldSchema, child.Addr()); err != nil {
+						return
+					}
+				}
+				continue
+			}
+			for i := 0; i < child.Len(); i++ {
+				elem := child.Index(i)
+				if elem.Kind() != reflect.Ptr {
+					elem = elem.Addr()
+				}
+				if err = resetDuplicate(rctx, rel.FieldSchema, elem); err != nil {
+					return
+				}
+			}
+		}
+		return
+	})
+	return
+}
+
+// resetDuplicate resets the primary keys, the auto create/update times and the soft delete time of the record.
+func resetDuplicate(ctx context.Context, s *schema.Schema, rv reflect.Value) (err error) {
+	for _, f := range s.Fields {
+		if !f.PrimaryKey && f.AutoCreateTime == 0 && f.AutoUpdateTime == 0 && f.FieldType != reflect.TypeOf(gorm.DeletedAt{}) {
+			continue
+		}
+		if err = f.Set(ctx, rv, reflect.Zero(f.FieldType).Interface()); err != nil {
+			return
+		}
+	}
+	return
+}
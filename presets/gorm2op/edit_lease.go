@@ -0,0 +1,123 @@
+package gorm2op
+
+import (
+	"errors"
+	"time"
+
+	"github.com/qor5/admin/presets"
+	"github.com/qor5/web"
+	"gorm.io/gorm"
+)
+
+type EditLease struct {
+	ID        uint   `gorm:"primarykey"`
+	ModelName string `gorm:"uniqueIndex:uidx_edit_leases_record"`
+	RecordID  string `gorm:"uniqueIndex:uidx_edit_leases_record"`
+	HolderID  string
+	// the name shown to the other users
+	HolderName string
+	ExpiresAt  time.Time `gorm:"index"`
+}
+
+// EditLeaseStoreBuilder keeps the edit leases in the database, see presets.EditingBuilder.EditLease.
+// The leases are acquired by the conditional updates and the unique index of the record,
+// so that only one user gets the lease when several users open the record at the same time.
+type EditLeaseStoreBuilder struct {
+	db       *gorm.DB
+	userFunc func(ctx *web.EventContext) (id string, name string)
+}
+
+// EditLeaseStore keeps the leases of the users, userFunc returns the id and the name of the current user,
+// the name is shown to the other users. It is required since the users of the same id own the same leases.
+func EditLeaseStore(db *gorm.DB, userFunc func(ctx *web.EventContext) (id string, name string)) (r *EditLeaseStoreBuilder) {
+	if userFunc == nil {
+		panic("gorm2op: the user func of the edit lease store is required")
+	}
+	if err := db.AutoMigrate(&EditLease{}); err != nil {
+		panic(err)
+	}
+	r = &EditLeaseStoreBuilder{db: db, userFunc: userFunc}
+	return
+}
+
+func (b *EditLeaseStoreBuilder) GetLease(model string, id string, ctx *web.EventContext) (r *presets.EditLease, err error) {
+	l := &EditLease{}
+	err = b.db.Where("model_name = ? AND record_id = ? AND expires_at > ?", model, id, time.Now()).First(l).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return
+	}
+	userID, _ := b.userFunc(ctx)
+	return toPresetsEditLease(l, userID), nil
+}
+
+func (b *EditLeaseStoreBuilder) AcquireLease(model string, id string, ttl time.Duration, takeOver bool, ctx *web.EventContext) (r *presets.EditLease, err error) {
+	userID, userName := b.userFunc(ctx)
+	if userID == "" {
+		return nil, errNoUser
+	}
+	now := time.Now()
+	l := &EditLease{
+		ModelName:  model,
+		RecordID:   id,
+		HolderID:   userID,
+		HolderName: userName,
+		ExpiresAt:  now.Add(ttl),
+	}
+
+	// renew the lease of the current user, or take the expired one
+	db := b.db.Model(&EditLease{}).Where("model_name = ? AND record_id = ?", model, id)
+	if !takeOver {
+		db = db.Where("holder_id = ? OR expires_at <= ?", userID, now)
+	}
+	result := db.Updates(map[string]interface{}{
+		"holder_id":   userID,
+		"holder_name": userName,
+		"expires_at":  l.ExpiresAt,
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return toPresetsEditLease(l, userID), nil
+	}
+
+	// the record is being edited by someone else
+	current := &EditLease{}
+	err = b.db.Where("model_name = ? AND record_id = ?", model, id).First(current).Error
+	if err == nil {
+		return toPresetsEditLease(current, userID), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return
+	}
+
+	if err = b.db.Create(l).Error; err == nil {
+		return toPresetsEditLease(l, userID), nil
+	}
+	// the lease is created by someone else at the same time
+	if fErr := b.db.Where("model_name = ? AND record_id = ?", model, id).First(current).Error; fErr != nil {
+		return nil, err
+	}
+	return toPresetsEditLease(current, userID), nil
+}
+
+func (b *EditLeaseStoreBuilder) ReleaseLease(model string, id string, force bool, ctx *web.EventContext) (err error) {
+	db := b.db.Where("model_name = ? AND record_id = ?", model, id)
+	if !force {
+		userID, _ := b.userFunc(ctx)
+		db = db.Where("holder_id = ?", userID)
+	}
+	return db.Delete(&EditLease{}).Error
+}
+
+func toPresetsEditLease(l *EditLease, userID string) *presets.EditLease {
+	return &presets.EditLease{
+		HolderID:   l.HolderID,
+		HolderName: l.HolderName,
+		ExpiresAt:  l.ExpiresAt,
+		Owned:      userID != "" && l.HolderID == userID && l.ExpiresAt.After(time.Now()),
+	}
+}
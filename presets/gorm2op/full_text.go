@@ -0,0 +1,151 @@
+package gorm2op
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FullTextStrategy searches the keyword by the full-text search of the database,
+// it is used when presets.SearchParams.FullText is true, see DataOperatorBuilder.FullText.
+type FullTextStrategy interface {
+	// Match filters the records of the table that match the keyword in the columns
+	Match(db *gorm.DB, table string, columns []string, keyword string) *gorm.DB
+	// Relevance returns the order by expression with the direction, that orders the records
+	// of the table by how they match the keyword, the most relevant first
+	Relevance(table string, columns []string, keyword string) clause.Expr
+}
+
+// FullText sets the full-text strategy of the operator, by default it is
+// PostgresFullText("simple") for Postgres and SQLiteFullText() for SQLite.
+func (op *DataOperatorBuilder) FullText(v FullTextStrategy) (r *DataOperatorBuilder) {
+	op.fullText = v
+	return op
+}
+
+func (op *DataOperatorBuilder) fullTextStrategy(db *gorm.DB) (r FullTextStrategy, err error) {
+	if op.fullText != nil {
+		return op.fullText, nil
+	}
+	switch db.Dialector.Name() {
+	case "postgres":
+		return PostgresFullText("simple"), nil
+	case "sqlite":
+		return SQLiteFullText(), nil
+	}
+	return nil, fmt.Errorf("no full-text strategy for %s, use DataOperator(...).FullText(...)", db.Dialector.Name())
+}
+
+type postgresFullText struct {
+	config string
+}
+
+// PostgresFullText matches the columns by tsvector with websearch_to_tsquery, and ranks them by ts_rank.
+// config is the text search configuration, like "simple" or "english".
+// Create the index with PostgresFullTextIndex of the same config and columns.
+func PostgresFullText(config string) FullTextStrategy {
+	return &postgresFullText{config: config}
+}
+
+func (s *postgresFullText) vector(columns []string) string {
+	var segs []string
+	for _, c := range columns {
+		segs = append(segs, fmt.Sprintf("coalesce(%s, '')", c))
+	}
+	return fmt.Sprintf("to_tsvector('%s', %s)", s.config, strings.Join(segs, " || ' ' || "))
+}
+
+func (s *postgresFullText) Match(db *gorm.DB, table string, columns []string, keyword string) *gorm.DB {
+	return db.Where(fmt.Sprintf("%s @@ websearch_to_tsquery('%s', ?)", s.vector(columns), s.config), keyword)
+}
+
+func (s *postgresFullText) Relevance(table string, columns []string, keyword string) clause.Expr {
+	return clause.Expr{
+		SQL:  fmt.Sprintf("ts_rank(%s, websearch_to_tsquery('%s', ?)) DESC", s.vector(columns), s.config),
+		Vars: []interface{}{keyword},
+	}
+}
+
+// PostgresFullTextIndex creates the GIN index of the tsvector that PostgresFullText matches,
+// the columns must be the same as the SearchColumns of the listing.
+func PostgresFullTextIndex(db *gorm.DB, obj interface{}, config string, columns ...string) (err error) {
+	table, err := tableName(db, obj)
+	if err != nil {
+		return
+	}
+	s := &postgresFullText{config: config}
+	return db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_full_text_idx ON %s USING GIN (%s)",
+		table, table, s.vector(columns))).Error
+}
+
+type sqliteFullText struct{}
+
+// SQLiteFullText matches the records by the FTS5 table "<table>_fts" created by SQLiteFullTextIndex,
+// and ranks them by bm25. The columns of the FTS5 table are matched, not the SearchColumns of the listing.
+// It is meant for the local development, the SQLite driver must be built with FTS5 (the sqlite_fts5 tag).
+func SQLiteFullText() FullTextStrategy {
+	return &sqliteFullText{}
+}
+
+// match quotes every word of the keyword, so that the FTS5 query syntax is not interpreted.
+func (s *sqliteFullText) match(keyword string) string {
+	var words []string
+	for _, w := range strings.Fields(keyword) {
+		words = append(words, fmt.Sprintf(`"%s"`, strings.ReplaceAll(w, `"`, `""`)))
+	}
+	return strings.Join(words, " ")
+}
+
+func (s *sqliteFullText) Match(db *gorm.DB, table string, columns []string, keyword string) *gorm.DB {
+	return db.Where(fmt.Sprintf("%s.rowid IN (SELECT rowid FROM %s_fts WHERE %s_fts MATCH ?)", table, table, table), s.match(keyword))
+}
+
+func (s *sqliteFullText) Relevance(table string, columns []string, keyword string) clause.Expr {
+	return clause.Expr{
+		SQL:  fmt.Sprintf("(SELECT rank FROM %s_fts WHERE %s_fts MATCH ? AND rowid = %s.rowid)", table, table, table),
+		Vars: []interface{}{s.match(keyword)},
+	}
+}
+
+// SQLiteFullTextIndex creates the FTS5 table "<table>_fts" of the columns for SQLiteFullText,
+// with the triggers that keep it in sync with the table, and indexes the existing records.
+func SQLiteFullTextIndex(db *gorm.DB, obj interface{}, columns ...string) (err error) {
+	table, err := tableName(db, obj)
+	if err != nil {
+		return
+	}
+
+	var news, olds []string
+	for _, c := range columns {
+		news = append(news, "new."+c)
+		olds = append(olds, "old."+c)
+	}
+	cols := strings.Join(columns, ", ")
+	deleteOld := fmt.Sprintf("INSERT INTO %s_fts(%s_fts, rowid, %s) VALUES('delete', old.rowid, %s);",
+		table, table, cols, strings.Join(olds, ", "))
+	insertNew := fmt.Sprintf("INSERT INTO %s_fts(rowid, %s) VALUES (new.rowid, %s);",
+		table, cols, strings.Join(news, ", "))
+
+	for _, sql := range []string{
+		fmt.Sprintf("CREATE VIRTUAL TABLE IF NOT EXISTS %s_fts USING fts5(%s, content='%s')", table, cols, table),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s_fts_insert AFTER INSERT ON %s BEGIN %s END", table, table, insertNew),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s_fts_delete AFTER DELETE ON %s BEGIN %s END", table, table, deleteOld),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s_fts_update AFTER UPDATE ON %s BEGIN %s %s END", table, table, deleteOld, insertNew),
+		fmt.Sprintf("INSERT INTO %s_fts(%s_fts) VALUES('rebuild')", table, table),
+	} {
+		if err = db.Exec(sql).Error; err != nil {
+			return
+		}
+	}
+	return
+}
+
+func tableName(db *gorm.DB, obj interface{}) (table string, err error) {
+	stmt := &gorm.Statement{DB: db}
+	if err = stmt.Parse(obj); err != nil {
+		return
+	}
+	return stmt.Schema.Table, nil
+}
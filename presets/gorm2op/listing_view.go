@@ -0,0 +1,106 @@
+package gorm2op
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qor5/admin/presets"
+	"github.com/qor5/web"
+	"gorm.io/gorm"
+)
+
+var errNoUser = errors.New("no current user")
+
+type ListingView struct {
+	gorm.Model
+
+	UserID    string `gorm:"index"`
+	ModelName string `gorm:"index"`
+	Name      string
+	Query     string `gorm:"type:text"`
+	Shared    bool
+}
+
+// ListingViewStoreBuilder saves the listing views in the database, see presets.ListingBuilder.SavedViews.
+type ListingViewStoreBuilder struct {
+	db         *gorm.DB
+	userIDFunc func(ctx *web.EventContext) string
+}
+
+// ListingViewStore saves the views per user, userIDFunc returns the id of the current user,
+// which is required since the views of the same user id are shared by the users.
+func ListingViewStore(db *gorm.DB, userIDFunc func(ctx *web.EventContext) string) (r *ListingViewStoreBuilder) {
+	if userIDFunc == nil {
+		panic("gorm2op: the user id func of the listing view store is required")
+	}
+	if err := db.AutoMigrate(&ListingView{}); err != nil {
+		panic(err)
+	}
+	r = &ListingViewStoreBuilder{db: db, userIDFunc: userIDFunc}
+	return
+}
+
+func (b *ListingViewStoreBuilder) ListViews(model string, ctx *web.EventContext) (r []*presets.ListingView, err error) {
+	userID := b.userIDFunc(ctx)
+	var views []*ListingView
+	err = b.db.Where("model_name = ? AND (user_id = ? OR shared = ?)", model, userID, true).
+		Order("id").
+		Find(&views).Error
+	if err != nil {
+		return
+	}
+
+	for _, v := range views {
+		r = append(r, &presets.ListingView{
+			ID:     fmt.Sprint(v.ID),
+			Name:   v.Name,
+			Query:  v.Query,
+			Shared: v.Shared,
+			Owned:  v.UserID == userID,
+		})
+	}
+	return
+}
+
+func (b *ListingViewStoreBuilder) SaveView(model string, view *presets.ListingView, ctx *web.EventContext) (err error) {
+	userID := b.userIDFunc(ctx)
+	if userID == "" {
+		return errNoUser
+	}
+	if view.ID == "" {
+		v := &ListingView{
+			UserID:    userID,
+			ModelName: model,
+			Name:      view.Name,
+			Query:     view.Query,
+			Shared:    view.Shared,
+		}
+		if err = b.db.Create(v).Error; err != nil {
+			return
+		}
+		view.ID = fmt.Sprint(v.ID)
+		view.Owned = true
+		return
+	}
+
+	result := b.db.Model(&ListingView{}).
+		Where("id = ? AND model_name = ? AND user_id = ?", view.ID, model, userID).
+		Updates(map[string]interface{}{"name": view.Name, "query": view.Query, "shared": view.Shared})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return presets.ErrRecordNotFound
+	}
+	view.Owned = true
+	return
+}
+
+func (b *ListingViewStoreBuilder) DeleteView(model string, id string, ctx *web.EventContext) (err error) {
+	userID := b.userIDFunc(ctx)
+	if userID == "" {
+		return errNoUser
+	}
+	return b.db.Where("id = ? AND model_name = ? AND user_id = ?", id, model, userID).
+		Delete(&ListingView{}).Error
+}
@@ -1,6 +1,7 @@
 package gorm2op
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -9,6 +10,7 @@ import (
 	"github.com/qor5/admin/presets"
 	"github.com/qor5/web"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
@@ -21,29 +23,79 @@ func DataOperator(db *gorm.DB) (r *DataOperatorBuilder) {
 }
 
 type DataOperatorBuilder struct {
-	db *gorm.DB
+	db       *gorm.DB
+	fullText FullTextStrategy
 }
 
-func (op *DataOperatorBuilder) Search(obj interface{}, params *presets.SearchParams, ctx *web.EventContext) (r interface{}, totalCount int, err error) {
-	ilike := "ILIKE"
-	if op.db.Dialector.Name() == "sqlite" {
-		ilike = "LIKE"
+type (
+	txKey          struct{}
+	afterCommitKey struct{}
+)
+
+// Transaction runs fc in a database transaction, the operator uses the transaction
+// for all the operations called with the ctx passed to fc.
+func (op *DataOperatorBuilder) Transaction(ctx *web.EventContext, fc func(ctx *web.EventContext) error) (err error) {
+	// the nested transactions leave the funcs of AfterCommit to the outermost one
+	funcs, nested := ctx.R.Context().Value(afterCommitKey{}).(*[]func())
+	if !nested {
+		funcs = &[]func(){}
 	}
+	err = op.dbFrom(ctx).Transaction(func(tx *gorm.DB) error {
+		txCtx := *ctx
+		c := context.WithValue(ctx.R.Context(), txKey{}, tx)
+		txCtx.R = ctx.R.WithContext(context.WithValue(c, afterCommitKey{}, funcs))
+		return fc(&txCtx)
+	})
+	if err == nil && !nested {
+		for _, f := range *funcs {
+			f()
+		}
+	}
+	return
+}
 
-	wh := op.db.Model(obj)
-	if len(params.KeywordColumns) > 0 && len(params.Keyword) > 0 {
-		var segs []string
-		var args []interface{}
-		for _, c := range params.KeywordColumns {
-			segs = append(segs, fmt.Sprintf("%s %s ?", c, ilike))
-			kw := wildcardReg.ReplaceAllString(params.Keyword, `\$0`)
-			args = append(args, fmt.Sprintf("%%%s%%", kw))
+// AfterCommit runs f after the transaction of Transaction in c is committed, and f is dropped if it is rolled back,
+// like for sending the notifications of the changes. f runs immediately without the transaction.
+func AfterCommit(c context.Context, f func()) {
+	if funcs, ok := c.Value(afterCommitKey{}).(*[]func()); ok {
+		*funcs = append(*funcs, f)
+		return
+	}
+	f()
+}
+
+// TxFromContext returns the transaction of Transaction in ctx, so that the modules like activity
+// write their records in the same transaction with the change.
+func TxFromContext(ctx context.Context) (tx *gorm.DB, ok bool) {
+	tx, ok = ctx.Value(txKey{}).(*gorm.DB)
+	return
+}
+
+func (op *DataOperatorBuilder) dbFrom(ctx *web.EventContext) *gorm.DB {
+	if ctx != nil && ctx.R != nil {
+		if tx, ok := TxFromContext(ctx.R.Context()); ok {
+			return tx
 		}
-		wh = wh.Where(strings.Join(segs, " OR "), args...)
 	}
+	return op.db
+}
 
-	for _, cond := range params.SQLConditions {
-		wh = wh.Where(strings.Replace(cond.Query, " ILIKE ", " "+ilike+" ", -1), cond.Args...)
+func (op *DataOperatorBuilder) Search(obj interface{}, params *presets.SearchParams, ctx *web.EventContext) (r interface{}, totalCount int, err error) {
+	db, _, err := op.tenantWhere(op.dbFrom(ctx), obj, ctx)
+	if err != nil {
+		return
+	}
+	return op.search(db, obj, params)
+}
+
+func (op *DataOperatorBuilder) search(db *gorm.DB, obj interface{}, params *presets.SearchParams) (r interface{}, totalCount int, err error) {
+	wh, relevance, err := op.searchWhere(db, obj, params)
+	if err != nil {
+		return
+	}
+
+	if params.Keyset != nil {
+		return op.keysetSearch(wh, obj, params)
 	}
 
 	var c int64
@@ -64,7 +116,13 @@ func (op *DataOperatorBuilder) Search(obj interface{}, params *presets.SearchPar
 	}
 
 	orderBy := params.OrderBy
-	if len(orderBy) > 0 {
+	if relevance != nil {
+		// OrderBy breaks the ties of the relevance
+		if len(orderBy) > 0 {
+			relevance.SQL += ", " + orderBy
+		}
+		wh = wh.Clauses(clause.OrderBy{Expression: *relevance})
+	} else if len(orderBy) > 0 {
 		wh = wh.Order(orderBy)
 	}
 
@@ -76,8 +134,76 @@ func (op *DataOperatorBuilder) Search(obj interface{}, params *presets.SearchPar
 	return
 }
 
-func (op *DataOperatorBuilder) primarySluggerWhere(obj interface{}, id string) *gorm.DB {
-	wh := op.db.Model(obj)
+// searchWhere applies the keyword and the conditions of params, and returns the relevance of the full-text search to order by.
+func (op *DataOperatorBuilder) searchWhere(db *gorm.DB, obj interface{}, params *presets.SearchParams) (wh *gorm.DB, relevance *clause.Expr, err error) {
+	ilike := "ILIKE"
+	if db.Dialector.Name() == "sqlite" {
+		ilike = "LIKE"
+	}
+
+	wh = db.Model(obj)
+	if params.FullText && len(params.KeywordColumns) > 0 && len(params.Keyword) > 0 {
+		var fts FullTextStrategy
+		var table string
+		if fts, err = op.fullTextStrategy(db); err != nil {
+			return
+		}
+		if table, err = tableName(db, obj); err != nil {
+			return
+		}
+		wh = fts.Match(wh, table, params.KeywordColumns, params.Keyword)
+		if params.OrderByRelevance && params.Keyset == nil {
+			rel := fts.Relevance(table, params.KeywordColumns, params.Keyword)
+			relevance = &rel
+		}
+	} else if len(params.KeywordColumns) > 0 && len(params.Keyword) > 0 {
+		var segs []string
+		var args []interface{}
+		for _, c := range params.KeywordColumns {
+			segs = append(segs, fmt.Sprintf("%s %s ?", c, ilike))
+			kw := wildcardReg.ReplaceAllString(params.Keyword, `\$0`)
+			args = append(args, fmt.Sprintf("%%%s%%", kw))
+		}
+		wh = wh.Where(strings.Join(segs, " OR "), args...)
+	}
+
+	for _, cond := range params.SQLConditions {
+		wh = wh.Where(strings.Replace(cond.Query, " ILIKE ", " "+ilike+" ", -1), cond.Args...)
+	}
+	return
+}
+
+// keysetSearch pages by the keyset condition instead of OFFSET,
+// and skips the count unless params.Keyset.WithCount is true.
+func (op *DataOperatorBuilder) keysetSearch(wh *gorm.DB, obj interface{}, params *presets.SearchParams) (r interface{}, totalCount int, err error) {
+	ks := params.Keyset
+	if ks.WithCount {
+		var c int64
+		err = wh.Count(&c).Error
+		if err != nil {
+			return
+		}
+		totalCount = int(c)
+	}
+
+	if cond, args := ks.Condition(); cond != "" {
+		wh = wh.Where(cond, args...)
+	}
+	if params.PerPage > 0 {
+		wh = wh.Limit(int(params.PerPage))
+	}
+	wh = wh.Order(ks.OrderBySQL())
+
+	err = wh.Find(obj).Error
+	if err != nil {
+		return
+	}
+	r = reflect.ValueOf(obj).Elem().Interface()
+	return
+}
+
+func (op *DataOperatorBuilder) primarySluggerWhere(db *gorm.DB, obj interface{}, id string) *gorm.DB {
+	wh := db.Model(obj)
 
 	if id == "" {
 		return wh
@@ -96,7 +222,11 @@ func (op *DataOperatorBuilder) primarySluggerWhere(obj interface{}, id string) *
 }
 
 func (op *DataOperatorBuilder) Fetch(obj interface{}, id string, ctx *web.EventContext) (r interface{}, err error) {
-	err = op.primarySluggerWhere(obj, id).First(obj).Error
+	db, _, err := op.tenantWhere(op.dbFrom(ctx), obj, ctx)
+	if err != nil {
+		return
+	}
+	err = op.primarySluggerWhere(db, obj, id).First(obj).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, presets.ErrRecordNotFound
@@ -108,15 +238,144 @@ func (op *DataOperatorBuilder) Fetch(obj interface{}, id string, ctx *web.EventC
 }
 
 func (op *DataOperatorBuilder) Save(obj interface{}, id string, ctx *web.EventContext) (err error) {
+	db := op.dbFrom(ctx)
+	if err = op.setTenant(db, obj, ctx); err != nil {
+		return
+	}
 	if id == "" {
-		err = op.db.Create(obj).Error
+		err = db.Create(obj).Error
+		return
+	}
+	db, scoped, err := op.tenantWhere(db, obj, ctx)
+	if err != nil {
 		return
 	}
-	err = op.primarySluggerWhere(obj, id).Save(obj).Error
+	wh := op.primarySluggerWhere(db, obj, id)
+	if vc := presets.VersionConditionFrom(ctx); vc != nil {
+		return op.saveVersion(wh, obj, vc)
+	}
+	if !scoped {
+		err = wh.Save(obj).Error
+		return
+	}
+	// Select("*") keeps Save from creating the record of the other tenant
+	result := wh.Select("*").Save(obj)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return presets.ErrRecordNotFound
+	}
+	return
+}
+
+// saveVersion updates the record only if its version is not changed, Select("*") keeps Save from creating the record when no rows are updated.
+func (op *DataOperatorBuilder) saveVersion(wh *gorm.DB, obj interface{}, vc *presets.VersionCondition) (err error) {
+	stmt := &gorm.Statement{DB: wh}
+	if err = stmt.Parse(obj); err != nil {
+		return
+	}
+	field := stmt.Schema.LookUpField(vc.FieldName)
+	if field == nil {
+		return fmt.Errorf("no field %s in %s", vc.FieldName, stmt.Schema.Name)
+	}
+
+	result := wh.Where(fmt.Sprintf("%s = ?", field.DBName), vc.Value).Select("*").Save(obj)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return presets.ErrEditConflict
+	}
 	return
 }
 
 func (op *DataOperatorBuilder) Delete(obj interface{}, id string, ctx *web.EventContext) (err error) {
-	err = op.primarySluggerWhere(obj, id).Delete(obj).Error
+	return op.delete(op.dbFrom(ctx), obj, id, ctx)
+}
+
+// delete refuses to delete the record of the other tenant with presets.ErrRecordNotFound.
+func (op *DataOperatorBuilder) delete(db *gorm.DB, obj interface{}, id string, ctx *web.EventContext) (err error) {
+	db, scoped, err := op.tenantWhere(db, obj, ctx)
+	if err != nil {
+		return
+	}
+	result := op.primarySluggerWhere(db, obj, id).Delete(obj)
+	if result.Error != nil {
+		return result.Error
+	}
+	if scoped && result.RowsAffected == 0 {
+		return presets.ErrRecordNotFound
+	}
+	return
+}
+
+// SearchTrash searches the soft-deleted records of the model with a gorm.DeletedAt field.
+func (op *DataOperatorBuilder) SearchTrash(obj interface{}, params *presets.SearchParams, ctx *web.EventContext) (r interface{}, totalCount int, err error) {
+	db, _, err := op.tenantWhere(op.dbFrom(ctx), obj, ctx)
+	if err != nil {
+		return
+	}
+	col, err := deletedAtColumn(db, obj)
+	if err != nil {
+		return
+	}
+	return op.search(db.Unscoped().Where(fmt.Sprintf("%s IS NOT NULL", col)), obj, params)
+}
+
+func (op *DataOperatorBuilder) FetchTrash(obj interface{}, id string, ctx *web.EventContext) (r interface{}, err error) {
+	db, _, err := op.tenantWhere(op.dbFrom(ctx), obj, ctx)
+	if err != nil {
+		return
+	}
+	col, err := deletedAtColumn(db, obj)
+	if err != nil {
+		return
+	}
+	err = op.primarySluggerWhere(db.Unscoped().Where(fmt.Sprintf("%s IS NOT NULL", col)), obj, id).First(obj).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, presets.ErrRecordNotFound
+		}
+		return
+	}
+	r = obj
 	return
 }
+
+func (op *DataOperatorBuilder) Restore(obj interface{}, id string, ctx *web.EventContext) (err error) {
+	db, _, err := op.tenantWhere(op.dbFrom(ctx), obj, ctx)
+	if err != nil {
+		return
+	}
+	col, err := deletedAtColumn(db, obj)
+	if err != nil {
+		return
+	}
+	result := op.primarySluggerWhere(db.Unscoped(), obj, id).Update(col, nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return presets.ErrRecordNotFound
+	}
+	return
+}
+
+func (op *DataOperatorBuilder) DeletePermanently(obj interface{}, id string, ctx *web.EventContext) (err error) {
+	return op.delete(op.dbFrom(ctx).Unscoped(), obj, id, ctx)
+}
+
+// deletedAtColumn returns the column of the gorm.DeletedAt field of obj, which can be a slice of the model.
+func deletedAtColumn(db *gorm.DB, obj interface{}) (col string, err error) {
+	stmt := &gorm.Statement{DB: db}
+	if err = stmt.Parse(obj); err != nil {
+		return
+	}
+	for _, f := range stmt.Schema.Fields {
+		if f.FieldType == reflect.TypeOf(gorm.DeletedAt{}) {
+			return f.DBName, nil
+		}
+	}
+	return "", fmt.Errorf("%s is not soft-deleted, no gorm.DeletedAt field", stmt.Schema.Name)
+}
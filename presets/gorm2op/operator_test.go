@@ -0,0 +1,82 @@
+package gorm2op
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qor5/web"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestDB(t *testing.T, models ...interface{}) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the memory database is kept by one connection
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err = db.AutoMigrate(models...); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func newTestContext() *web.EventContext {
+	return &web.EventContext{R: httptest.NewRequest("POST", "/", nil), W: httptest.NewRecorder()}
+}
+
+type testNote struct {
+	ID   uint
+	Body string
+}
+
+func TestAfterCommit(t *testing.T) {
+	op := DataOperator(newTestDB(t, &testNote{}))
+
+	var committed []string
+	err := op.Transaction(newTestContext(), func(ctx *web.EventContext) error {
+		AfterCommit(ctx.R.Context(), func() { committed = append(committed, "outer") })
+		return op.Transaction(ctx, func(ctx *web.EventContext) error {
+			AfterCommit(ctx.R.Context(), func() { committed = append(committed, "nested") })
+			if len(committed) != 0 {
+				t.Errorf("the funcs run before the commit")
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(committed) != 2 || committed[0] != "outer" || committed[1] != "nested" {
+		t.Errorf("committed = %v", committed)
+	}
+
+	committed = nil
+	err = op.Transaction(newTestContext(), func(ctx *web.EventContext) error {
+		if err := op.Save(&testNote{Body: "x"}, "", ctx); err != nil {
+			return err
+		}
+		AfterCommit(ctx.R.Context(), func() { committed = append(committed, "rolled back") })
+		return errors.New("rollback")
+	})
+	if err == nil || len(committed) != 0 {
+		t.Errorf("the funcs of the rolled back transaction run: %v", committed)
+	}
+	var count int64
+	op.db.Model(&testNote{}).Count(&count)
+	if count != 0 {
+		t.Errorf("the note is not rolled back")
+	}
+
+	AfterCommit(newTestContext().R.Context(), func() { committed = append(committed, "now") })
+	if len(committed) != 1 {
+		t.Errorf("the func without the transaction doesn't run immediately")
+	}
+}
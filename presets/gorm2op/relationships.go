@@ -0,0 +1,342 @@
+package gorm2op
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"github.com/iancoleman/strcase"
+	"github.com/qor5/admin/presets"
+	. "github.com/qor5/ui/vuetify"
+	vx "github.com/qor5/ui/vuetifyx"
+	"github.com/qor5/web"
+	"github.com/qor5/x/perm"
+	h "github.com/theplant/htmlgo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// hasManyLimit is the max number of the related records shown on the detailing page.
+const hasManyLimit = 10
+
+type replaceKey struct {
+	field string
+}
+
+// Relationships configures the fields of the associations of the model by the gorm schema,
+// the associated models must be registered in pb.
+//
+//   - belongs-to fields are edited by the autocomplete of the associated model
+//   - many-to-many fields are edited by chips, and saved by replacing the association after the Saver,
+//     so call it after the SaveFunc of the editing. The ids are loaded by the Searcher of the associated model,
+//     and only the join table is written
+//   - has-many fields show the related records on the detailing page, and link to the listing of the
+//     child model, which is filtered by the foreign key if it has the filter, see presets.ListingBuilder.Filters
+func (op *DataOperatorBuilder) Relationships(pb *presets.Builder, mb *presets.ModelBuilder, fields ...string) {
+	stmt := &gorm.Statement{DB: op.db}
+	if err := stmt.Parse(mb.NewModel()); err != nil {
+		panic(err)
+	}
+
+	for _, name := range fields {
+		rel, ok := stmt.Schema.Relationships.Relations[name]
+		if !ok {
+			panic(fmt.Sprintf("%s has no association %s", stmt.Schema.Name, name))
+		}
+		rmb := pb.GetModelBuilder(reflect.New(rel.FieldSchema.ModelType).Interface())
+		if rmb == nil {
+			panic(fmt.Sprintf("the model %s of the association %s is not registered", rel.FieldSchema.Name, name))
+		}
+
+		switch rel.Type {
+		case schema.BelongsTo:
+			op.belongsTo(mb, rmb, rel)
+		case schema.Many2Many:
+			op.many2Many(mb, rmb, rel)
+		case schema.HasMany:
+			op.hasMany(mb, rmb, rel)
+		default:
+			panic(fmt.Sprintf("the %s association %s is not supported", rel.Type, name))
+		}
+	}
+}
+
+func recordTitle(obj interface{}) string {
+	if pt, ok := obj.(interface{ PageTitle() string }); ok {
+		return pt.PageTitle()
+	}
+	return vx.ObjectID(obj)
+}
+
+func autocompleteDataSource(rmb *presets.ModelBuilder, rel *schema.Relationship) *vx.AutocompleteDataSource {
+	return rmb.Listing().ConfigureAutocompleteDataSource(&presets.AutocompleteDataSourceConfig{
+		OptionValue: rel.FieldSchema.PrioritizedPrimaryField.Name,
+		OptionText: func(obj interface{}) string {
+			return recordTitle(obj)
+		},
+	}, "relationship")
+}
+
+func optionItem(obj interface{}, pk *schema.Field) presets.OptionItem {
+	v, _ := pk.ValueOf(context.TODO(), reflect.ValueOf(obj))
+	return presets.OptionItem{Text: recordTitle(obj), Value: fmt.Sprint(v)}
+}
+
+// parseValue parses the form value s to the type of the field, which can be a pointer.
+func parseValue(f *schema.Field, s string) (r interface{}, err error) {
+	v := reflect.New(f.IndirectFieldType).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var i uint64
+		if i, err = strconv.ParseUint(s, 10, 64); err != nil {
+			return
+		}
+		v.SetUint(i)
+	default:
+		return nil, fmt.Errorf("the key %s of %s is not supported", f.Name, f.IndirectFieldType)
+	}
+	if f.FieldType.Kind() == reflect.Ptr {
+		return v.Addr().Interface(), nil
+	}
+	return v.Interface(), nil
+}
+
+func (op *DataOperatorBuilder) belongsTo(mb *presets.ModelBuilder, rmb *presets.ModelBuilder, rel *schema.Relationship) {
+	if len(rel.References) != 1 {
+		panic(fmt.Sprintf("the association %s with composite foreign keys is not supported", rel.Name))
+	}
+	fk := rel.References[0].ForeignKey
+	pk := rel.References[0].PrimaryKey
+	ds := autocompleteDataSource(rmb, rel)
+
+	mb.Editing().Field(rel.Name).ComponentFunc(func(obj interface{}, field *presets.FieldContext, ctx *web.EventContext) h.HTMLComponent {
+		var value string
+		var selected []presets.OptionItem
+		if v, zero := fk.ValueOf(ctx.R.Context(), reflect.ValueOf(obj)); !zero {
+			value = fmt.Sprint(reflect.Indirect(reflect.ValueOf(v)).Interface())
+			related := rmb.NewModel()
+			db, _, err := op.tenantWhere(op.dbFrom(ctx), related, ctx)
+			if err == nil {
+				err = db.Where(clause.Eq{Column: clause.Column{Name: pk.DBName}, Value: value}).First(related).Error
+			}
+			if err == nil {
+				selected = append(selected, optionItem(related, pk))
+			}
+		}
+		return vx.VXAutocomplete().
+			Label(field.Label).
+			FieldName(field.FormKey).
+			Multiple(false).Chips(false).
+			SetDataSource(ds).
+			Value(value).
+			SelectedItems(selected).
+			ErrorMessages(field.Errors...).
+			Disabled(field.Disabled)
+	}).SetterFunc(func(obj interface{}, field *presets.FieldContext, ctx *web.EventContext) (err error) {
+		rv := reflect.ValueOf(obj)
+		var v interface{}
+		if s := ctx.R.FormValue(field.FormKey); s != "" {
+			if v, err = parseValue(fk, s); err != nil {
+				return
+			}
+		}
+		if err = fk.Set(ctx.R.Context(), rv, v); err != nil {
+			return
+		}
+		// gorm sets the foreign key from the loaded association when saving
+		return rel.Field.Set(ctx.R.Context(), rv, nil)
+	})
+}
+
+func (op *DataOperatorBuilder) many2Many(mb *presets.ModelBuilder, rmb *presets.ModelBuilder, rel *schema.Relationship) {
+	pk := rel.FieldSchema.PrioritizedPrimaryField
+	ds := autocompleteDataSource(rmb, rel)
+
+	eb := mb.Editing()
+	eb.Field(rel.Name).ComponentFunc(func(obj interface{}, field *presets.FieldContext, ctx *web.EventContext) h.HTMLComponent {
+		rv := reflect.ValueOf(obj)
+		related := rel.Field.ReflectValueOf(ctx.R.Context(), rv)
+		if related.Len() == 0 && vx.ObjectID(obj) != "" {
+			relatedPtr := reflect.New(related.Type())
+			if err := op.dbFrom(ctx).Model(obj).Association(rel.Name).Find(relatedPtr.Interface()); err != nil {
+				panic(err)
+			}
+			related = relatedPtr.Elem()
+		}
+
+		var values []string
+		var selected []presets.OptionItem
+		for i := 0; i < related.Len(); i++ {
+			item := optionItem(related.Index(i).Interface(), pk)
+			values = append(values, item.Value)
+			selected = append(selected, item)
+		}
+		return vx.VXAutocomplete().
+			Label(field.Label).
+			FieldName(field.FormKey).
+			SetDataSource(ds).
+			Value(values).
+			SelectedItems(selected).
+			ErrorMessages(field.Errors...).
+			Disabled(field.Disabled)
+	}).SetterFunc(func(obj interface{}, field *presets.FieldContext, ctx *web.EventContext) (err error) {
+		rctx := ctx.R.Context()
+		related, err := op.loadRelated(rmb, rel, ctx.R.Form[field.FormKey], ctx)
+		if err != nil {
+			return
+		}
+		if err = rel.Field.Set(rctx, reflect.ValueOf(obj), related.Interface()); err != nil {
+			return
+		}
+		ctx.R = ctx.R.WithContext(context.WithValue(rctx, replaceKey{rel.Name}, true))
+		return
+	})
+
+	saver := eb.Saver
+	eb.SaveFunc(func(obj interface{}, id string, ctx *web.EventContext) (err error) {
+		return op.Transaction(ctx, func(txCtx *web.EventContext) (err error) {
+			if err = saver(obj, id, txCtx); err != nil {
+				return
+			}
+			// the association is not changed if the field is not in the form
+			if txCtx.R.Context().Value(replaceKey{rel.Name}) == nil {
+				return
+			}
+			// only the join table is written, the related records are not upserted
+			association := op.dbFrom(txCtx).Model(obj).Omit(rel.Name + ".*").Association(rel.Name)
+			related := rel.Field.ReflectValueOf(txCtx.R.Context(), reflect.ValueOf(obj))
+			if related.Len() == 0 {
+				return association.Clear()
+			}
+			return association.Replace(related.Interface())
+		})
+	})
+}
+
+// loadRelated loads the related records of the ids by the Searcher of the related model, so that
+// the ids out of its scope, like of the other tenants, or of the records that the user can't get,
+// are refused instead of being created.
+func (op *DataOperatorBuilder) loadRelated(rmb *presets.ModelBuilder, rel *schema.Relationship, ids []string, ctx *web.EventContext) (r reflect.Value, err error) {
+	pk := rel.FieldSchema.PrioritizedPrimaryField
+	r = reflect.MakeSlice(rel.Field.IndirectFieldType, 0, len(ids))
+	if len(ids) == 0 {
+		return
+	}
+
+	var values []interface{}
+	wanted := make(map[string]bool)
+	for _, id := range ids {
+		var v interface{}
+		if v, err = parseValue(pk, id); err != nil {
+			return
+		}
+		key := fmt.Sprint(reflect.Indirect(reflect.ValueOf(v)).Interface())
+		if !wanted[key] {
+			values = append(values, v)
+		}
+		wanted[key] = true
+	}
+	objs, _, err := rmb.Listing().Searcher(rmb.NewModelSlice(), &presets.SearchParams{
+		SQLConditions: []*presets.SQLCondition{{
+			Query: fmt.Sprintf("%s IN ?", pk.DBName),
+			Args:  []interface{}{values},
+		}},
+	}, ctx)
+	if err != nil {
+		return
+	}
+
+	objsV := reflect.Indirect(reflect.ValueOf(objs))
+	found := make(map[string]bool)
+	for i := 0; i < objsV.Len(); i++ {
+		elem := objsV.Index(i)
+		if rmb.Info().Verifier().Do(presets.PermGet).ObjectOn(elem.Interface()).WithReq(ctx.R).IsAllowed() != nil {
+			return r, perm.PermissionDenied
+		}
+		v, _ := pk.ValueOf(ctx.R.Context(), elem)
+		found[fmt.Sprint(reflect.Indirect(reflect.ValueOf(v)).Interface())] = true
+		if rel.Field.IndirectFieldType.Elem().Kind() == reflect.Ptr && elem.Kind() != reflect.Ptr {
+			elem = elem.Addr()
+		} else if rel.Field.IndirectFieldType.Elem().Kind() != reflect.Ptr && elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		r = reflect.Append(r, elem)
+	}
+	for id := range wanted {
+		if !found[id] {
+			return r, fmt.Errorf("%s %s: %w", rel.FieldSchema.Name, id, presets.ErrRecordNotFound)
+		}
+	}
+	return
+}
+
+func (op *DataOperatorBuilder) hasMany(mb *presets.ModelBuilder, rmb *presets.ModelBuilder, rel *schema.Relationship) {
+	mb.Detailing().Field(rel.Name).ComponentFunc(func(obj interface{}, field *presets.FieldContext, ctx *web.EventContext) h.HTMLComponent {
+		// the related records are hidden from the user who can't list them
+		if rmb.Info().Verifier().Do(presets.PermList).WithReq(ctx.R).IsAllowed() != nil {
+			return nil
+		}
+		msgr := presets.MustGetMessages(ctx.R)
+		// the errors, like presets.ErrNoTenant of the request without the tenant, are shown in the field
+		var count int64
+		related := rmb.NewModelSlice()
+		db, _, err := op.tenantWhere(op.dbFrom(ctx), rmb.NewModel(), ctx)
+		if err == nil {
+			wh := db.Model(rmb.NewModel()).
+				Where(clause.And(rel.ToQueryConditions(ctx.R.Context(), reflect.Indirect(reflect.ValueOf(obj)))...))
+			if err = wh.Count(&count).Error; err == nil {
+				err = wh.Limit(hasManyLimit).Find(related).Error
+			}
+		}
+		if err != nil {
+			return vx.VXReadonlyField().
+				Label(field.Label).
+				Children(h.Div(h.Text(err.Error())).Class("error--text"))
+		}
+
+		var rows []h.HTMLComponent
+		relatedV := reflect.ValueOf(related).Elem()
+		for i := 0; i < relatedV.Len(); i++ {
+			robj := relatedV.Index(i).Interface()
+			if rmb.Info().Verifier().Do(presets.PermGet).ObjectOn(robj).WithReq(ctx.R).IsAllowed() != nil {
+				continue
+			}
+			var title h.HTMLComponent = h.Text(recordTitle(robj))
+			if rmb.Info().HasDetailing() {
+				title = h.A(title).Href(rmb.Info().DetailingHref(vx.ObjectID(robj)))
+			}
+			rows = append(rows, h.Tr(h.Td(title)))
+		}
+		if len(rows) == 0 {
+			rows = append(rows, h.Tr(h.Td(h.Text(msgr.ListingNoRecordToShow)).Class("grey--text")))
+		}
+
+		// the listing is filtered by the foreign key with the filter of presets.ListingBuilder.Filters
+		query := url.Values{}
+		for _, ref := range rel.References {
+			if ref.OwnPrimaryKey {
+				v, _ := ref.PrimaryKey.ValueOf(ctx.R.Context(), reflect.ValueOf(obj))
+				query.Set("f_"+strcase.ToSnake(ref.ForeignKey.Name), fmt.Sprint(v))
+			}
+		}
+
+		return vx.VXReadonlyField().
+			Label(field.Label).
+			Children(
+				VSimpleTable(h.Tbody(rows...)).Dense(true),
+				h.A(h.Text(fmt.Sprintf("%s (%d)", msgr.ViewAll, count))).
+					Href(rmb.Info().ListingHref()+"?"+query.Encode()),
+			)
+	})
+}
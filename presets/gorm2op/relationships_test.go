@@ -0,0 +1,104 @@
+package gorm2op
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qor5/admin/presets"
+	"github.com/qor5/web"
+)
+
+type testTag struct {
+	ID      uint
+	Name    string
+	BrandID string
+}
+
+func (t *testTag) TenantField() string {
+	return "BrandID"
+}
+
+type testPost struct {
+	ID    uint
+	Title string
+	Tags  []*testTag `gorm:"many2many:test_post_tags"`
+}
+
+func newFormContext(tenant string, form map[string][]string) *web.EventContext {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for k, vs := range form {
+		for _, v := range vs {
+			mw.WriteField(k, v)
+		}
+	}
+	mw.Close()
+	req := httptest.NewRequest("POST", "/", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req = req.WithContext(presets.WithTenant(req.Context(), tenant))
+	return &web.EventContext{R: req, W: httptest.NewRecorder()}
+}
+
+func TestMany2Many(t *testing.T) {
+	db := newTestDB(t, &testTag{}, &testPost{})
+	db.Create([]*testTag{{ID: 1, Name: "A1", BrandID: "a"}, {ID: 2, Name: "A2", BrandID: "a"}, {ID: 3, Name: "B3", BrandID: "b"}})
+
+	op := DataOperator(db)
+	pb := presets.New().DataOperator(op)
+	pb.Model(&testTag{})
+	mb := pb.Model(&testPost{})
+	mb.Editing("Title", "Tags")
+	op.Relationships(pb, mb, "Tags")
+	eb := mb.Editing()
+
+	save := func(id string, tags ...string) (vErr web.ValidationErrors, err error) {
+		ctx := newFormContext("a", map[string][]string{"Title": {"Post"}, "Tags": tags})
+		obj, vErr := eb.FetchAndUnmarshal(id, false, ctx)
+		if vErr.HaveErrors() {
+			return
+		}
+		err = eb.Saver(obj, id, ctx)
+		return
+	}
+	tagsOf := func(id uint) (names []string) {
+		db.Table("test_tags").
+			Joins("JOIN test_post_tags ON test_post_tags.test_tag_id = test_tags.id").
+			Where("test_post_tags.test_post_id = ?", id).
+			Order("test_tags.id").
+			Pluck("test_tags.name", &names)
+		return
+	}
+
+	if vErr, err := save("", "1", "2"); vErr.HaveErrors() || err != nil {
+		t.Fatalf("errors = %v, %v", vErr.Error(), err)
+	}
+	if names := tagsOf(1); len(names) != 2 || names[0] != "A1" || names[1] != "A2" {
+		t.Errorf("tags = %v", names)
+	}
+
+	// the tags of the other tenant and the ones that don't exist are refused, and not created
+	for _, id := range []string{"3", "99"} {
+		if vErr, _ := save("1", "1", id); !vErr.HaveErrors() {
+			t.Errorf("tag %s is saved", id)
+		}
+	}
+	var count int64
+	db.Model(&testTag{}).Count(&count)
+	if count != 3 {
+		t.Errorf("tags = %d, want 3", count)
+	}
+
+	if vErr, err := save("1", "2"); vErr.HaveErrors() || err != nil {
+		t.Fatalf("errors = %v, %v", vErr.Error(), err)
+	}
+	if names := tagsOf(1); len(names) != 1 || names[0] != "A2" {
+		t.Errorf("tags = %v", names)
+	}
+	var tag testTag
+	db.First(&tag, 2)
+	if tag.Name != "A2" || tag.BrandID != "a" {
+		t.Errorf("the tag is changed to %+v", tag)
+	}
+}
@@ -0,0 +1,63 @@
+package gorm2op
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/qor5/admin/presets"
+	"github.com/qor5/web"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// tenantField returns the tenant field of the model of obj, which can be a slice of the model,
+// and the tenant of the request parsed to the type of the field, the field is nil if the model is not presets.TenantScoped.
+func (op *DataOperatorBuilder) tenantField(db *gorm.DB, obj interface{}, ctx *web.EventContext) (f *schema.Field, tenant interface{}, err error) {
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	ts, ok := reflect.New(t).Interface().(presets.TenantScoped)
+	if !ok {
+		return
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err = stmt.Parse(obj); err != nil {
+		return
+	}
+	if f = stmt.Schema.LookUpField(ts.TenantField()); f == nil {
+		return nil, nil, fmt.Errorf("no tenant field %s in %s", ts.TenantField(), stmt.Schema.Name)
+	}
+	var s string
+	if ctx != nil && ctx.R != nil {
+		s = presets.TenantFromContext(ctx.R.Context())
+	}
+	if s == "" {
+		return nil, nil, presets.ErrNoTenant
+	}
+	if tenant, err = parseValue(f, s); err != nil {
+		return nil, nil, err
+	}
+	return
+}
+
+// tenantWhere adds the condition of the tenant of the request if the model of obj is presets.TenantScoped,
+// so that the records of the other tenants are never found.
+func (op *DataOperatorBuilder) tenantWhere(db *gorm.DB, obj interface{}, ctx *web.EventContext) (r *gorm.DB, scoped bool, err error) {
+	f, tenant, err := op.tenantField(db, obj, ctx)
+	if err != nil || f == nil {
+		return db, false, err
+	}
+	return db.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: f.DBName}, Value: tenant}), true, nil
+}
+
+// setTenant sets the tenant of the request to the record if its model is presets.TenantScoped.
+func (op *DataOperatorBuilder) setTenant(db *gorm.DB, obj interface{}, ctx *web.EventContext) (err error) {
+	f, tenant, err := op.tenantField(db, obj, ctx)
+	if err != nil || f == nil {
+		return
+	}
+	return f.Set(ctx.R.Context(), reflect.ValueOf(obj), tenant)
+}
@@ -0,0 +1,139 @@
+package gorm2op
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/qor5/admin/presets"
+	"github.com/qor5/x/perm"
+)
+
+type testShelf struct {
+	ID    uint
+	Name  string
+	Books []*testBook `gorm:"foreignKey:ShelfID"`
+}
+
+type testBook struct {
+	ID      uint
+	ShelfID uint
+	Title   string
+	BrandID string
+}
+
+func (b *testBook) TenantField() string {
+	return "BrandID"
+}
+
+func TestTenant(t *testing.T) {
+	db := newTestDB(t, &testShelf{}, &testBook{})
+	db.Create(&testShelf{ID: 1, Name: "Shelf"})
+	db.Create([]*testBook{{ID: 1, ShelfID: 1, Title: "A1", BrandID: "a"}, {ID: 2, ShelfID: 1, Title: "B2", BrandID: "b"}})
+	op := DataOperator(db)
+	ctx := newFormContext("a", nil)
+
+	if _, err := op.Fetch(&testBook{}, "2", ctx); !errors.Is(err, presets.ErrRecordNotFound) {
+		t.Errorf("the book of the other tenant is fetched, err = %v", err)
+	}
+	if obj, err := op.Fetch(&testBook{}, "1", ctx); err != nil || obj.(*testBook).Title != "A1" {
+		t.Errorf("the book of the tenant is not fetched, err = %v", err)
+	}
+	if _, err := op.Fetch(&testBook{}, "1", newFormContext("", nil)); !errors.Is(err, presets.ErrNoTenant) {
+		t.Errorf("the book is fetched without the tenant, err = %v", err)
+	}
+
+	r, total, err := op.Search(&[]*testBook{}, &presets.SearchParams{}, ctx)
+	if err != nil || total != 1 || len(r.([]*testBook)) != 1 {
+		t.Errorf("the books of the other tenant are searched, total = %d, err = %v", total, err)
+	}
+
+	// Select("*") keeps Save from upserting the book of the other tenant
+	if err = op.Save(&testBook{ID: 2, ShelfID: 1, Title: "changed", BrandID: "b"}, "2", ctx); !errors.Is(err, presets.ErrRecordNotFound) {
+		t.Errorf("the book of the other tenant is saved, err = %v", err)
+	}
+	var book testBook
+	db.First(&book, 2)
+	if book.Title != "B2" || book.BrandID != "b" {
+		t.Errorf("the book of the other tenant is changed to %+v", book)
+	}
+	var count int64
+	db.Model(&testBook{}).Count(&count)
+	if count != 2 {
+		t.Errorf("books = %d, want 2", count)
+	}
+
+	// the tenant of the request is set on create
+	created := &testBook{ShelfID: 1, Title: "A3", BrandID: "b"}
+	if err = op.Save(created, "", ctx); err != nil {
+		t.Fatal(err)
+	}
+	book = testBook{}
+	db.First(&book, created.ID)
+	if book.BrandID != "a" {
+		t.Errorf("the book is created for the tenant %q", book.BrandID)
+	}
+
+	if err = op.Delete(&testBook{}, "2", ctx); !errors.Is(err, presets.ErrRecordNotFound) {
+		t.Errorf("the book of the other tenant is deleted, err = %v", err)
+	}
+	db.Model(&testBook{}).Where("id = ?", 2).Count(&count)
+	if count != 1 {
+		t.Errorf("the book of the other tenant is deleted")
+	}
+	if err = op.Delete(&testBook{}, "1", ctx); err != nil {
+		t.Errorf("the book of the tenant is not deleted, err = %v", err)
+	}
+}
+
+func TestHasManyWithoutTenant(t *testing.T) {
+	db := newTestDB(t, &testShelf{}, &testBook{})
+	op := DataOperator(db)
+	pb := presets.New().DataOperator(op)
+	pb.Model(&testBook{})
+	mb := pb.Model(&testShelf{})
+	mb.Detailing("Name", "Books")
+	op.Relationships(pb, mb, "Books")
+
+	ctx := newFormContext("", nil)
+	comp := mb.Detailing().ToComponent(mb.Info(), &testShelf{ID: 1, Name: "Shelf"}, ctx)
+	content, err := comp.MarshalHTML(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), presets.ErrNoTenant.Error()) {
+		t.Errorf("the error is not shown: %s", content)
+	}
+}
+
+func TestHasManyPermission(t *testing.T) {
+	db := newTestDB(t, &testShelf{}, &testBook{})
+	db.Create(&testShelf{ID: 1, Name: "Shelf"})
+	db.Create([]*testBook{{ID: 1, ShelfID: 1, Title: "A1", BrandID: "a"}, {ID: 2, ShelfID: 1, Title: "A2", BrandID: "a"}})
+	op := DataOperator(db)
+
+	render := func(policies ...*perm.PolicyBuilder) string {
+		pb := presets.New().DataOperator(op).Permission(perm.New().Policies(append([]*perm.PolicyBuilder{
+			perm.PolicyFor(perm.Anybody).WhoAre(perm.Allowed).ToDo(perm.Anything).On(perm.Anything),
+		}, policies...)...))
+		pb.Model(&testBook{})
+		mb := pb.Model(&testShelf{})
+		mb.Detailing("Name", "Books")
+		op.Relationships(pb, mb, "Books")
+
+		comp := mb.Detailing().ToComponent(mb.Info(), &testShelf{ID: 1, Name: "Shelf"}, newFormContext("a", nil))
+		content, err := comp.MarshalHTML(context.TODO())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+
+	if content := render(perm.PolicyFor(perm.Anybody).WhoAre(perm.Denied).ToDo(presets.PermGet).On("*:test_books:2:*")); !strings.Contains(content, "<td>1</td>") || strings.Contains(content, "<td>2</td>") {
+		t.Errorf("the book that can't be read is shown: %s", content)
+	}
+	if content := render(perm.PolicyFor(perm.Anybody).WhoAre(perm.Denied).ToDo(presets.PermList).On("*:test_books:*")); strings.Contains(content, "<td>1</td>") {
+		t.Errorf("the books are shown to the user who can't list them: %s", content)
+	}
+}
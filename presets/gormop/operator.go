@@ -1,6 +1,7 @@
 package gormop
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -24,13 +25,38 @@ type DataOperatorBuilder struct {
 	db *gorm.DB
 }
 
+type txKey struct{}
+
+// Transaction runs fc in a database transaction, the operator uses the transaction
+// for all the operations called with the ctx passed to fc.
+func (op *DataOperatorBuilder) Transaction(ctx *web.EventContext, fc func(ctx *web.EventContext) error) (err error) {
+	return op.dbFrom(ctx).Transaction(func(tx *gorm.DB) error {
+		txCtx := *ctx
+		txCtx.R = ctx.R.WithContext(context.WithValue(ctx.R.Context(), txKey{}, tx))
+		return fc(&txCtx)
+	})
+}
+
+func (op *DataOperatorBuilder) dbFrom(ctx *web.EventContext) *gorm.DB {
+	if ctx != nil && ctx.R != nil {
+		if tx, ok := ctx.R.Context().Value(txKey{}).(*gorm.DB); ok {
+			return tx
+		}
+	}
+	return op.db
+}
+
 func (op *DataOperatorBuilder) Search(obj interface{}, params *presets.SearchParams, ctx *web.EventContext) (r interface{}, totalCount int, err error) {
+	return op.search(op.dbFrom(ctx), obj, params)
+}
+
+func (op *DataOperatorBuilder) search(db *gorm.DB, obj interface{}, params *presets.SearchParams) (r interface{}, totalCount int, err error) {
 	ilike := "ILIKE"
-	if op.db.Dialect().GetName() == "sqlite3" {
+	if db.Dialect().GetName() == "sqlite3" {
 		ilike = "LIKE"
 	}
 
-	wh := op.db.Model(obj)
+	wh := db.Model(obj)
 	if len(params.KeywordColumns) > 0 && len(params.Keyword) > 0 {
 		var segs []string
 		var args []interface{}
@@ -46,6 +72,10 @@ func (op *DataOperatorBuilder) Search(obj interface{}, params *presets.SearchPar
 		wh = wh.Where(strings.Replace(cond.Query, " ILIKE ", " "+ilike+" ", -1), cond.Args...)
 	}
 
+	if params.Keyset != nil {
+		return op.keysetSearch(wh, obj, params)
+	}
+
 	err = wh.Count(&totalCount).Error
 	if err != nil {
 		return
@@ -74,8 +104,35 @@ func (op *DataOperatorBuilder) Search(obj interface{}, params *presets.SearchPar
 	return
 }
 
-func (op *DataOperatorBuilder) primarySluggerWhere(obj interface{}, id string) *gorm.DB {
-	wh := op.db.Model(obj)
+// keysetSearch pages by the keyset condition instead of OFFSET,
+// and skips the count unless params.Keyset.WithCount is true.
+func (op *DataOperatorBuilder) keysetSearch(wh *gorm.DB, obj interface{}, params *presets.SearchParams) (r interface{}, totalCount int, err error) {
+	ks := params.Keyset
+	if ks.WithCount {
+		err = wh.Count(&totalCount).Error
+		if err != nil {
+			return
+		}
+	}
+
+	if cond, args := ks.Condition(); cond != "" {
+		wh = wh.Where(cond, args...)
+	}
+	if params.PerPage > 0 {
+		wh = wh.Limit(params.PerPage)
+	}
+	wh = wh.Order(ks.OrderBySQL())
+
+	err = wh.Find(obj).Error
+	if err != nil {
+		return
+	}
+	r = reflect.ValueOf(obj).Elem().Interface()
+	return
+}
+
+func (op *DataOperatorBuilder) primarySluggerWhere(db *gorm.DB, obj interface{}, id string) *gorm.DB {
+	wh := db.Model(obj)
 
 	if id == "" {
 		return wh
@@ -94,7 +151,7 @@ func (op *DataOperatorBuilder) primarySluggerWhere(obj interface{}, id string) *
 }
 
 func (op *DataOperatorBuilder) Fetch(obj interface{}, id string, ctx *web.EventContext) (r interface{}, err error) {
-	err = op.primarySluggerWhere(obj, id).Find(obj).Error
+	err = op.primarySluggerWhere(op.dbFrom(ctx), obj, id).Find(obj).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, presets.ErrRecordNotFound
@@ -107,14 +164,93 @@ func (op *DataOperatorBuilder) Fetch(obj interface{}, id string, ctx *web.EventC
 
 func (op *DataOperatorBuilder) Save(obj interface{}, id string, ctx *web.EventContext) (err error) {
 	if id == "" {
-		err = op.db.Create(obj).Error
+		err = op.dbFrom(ctx).Create(obj).Error
 		return
 	}
-	err = op.primarySluggerWhere(obj, id).Update(obj).Error
+	wh := op.primarySluggerWhere(op.dbFrom(ctx), obj, id)
+	if vc := presets.VersionConditionFrom(ctx); vc != nil {
+		return op.saveVersion(wh, obj, vc)
+	}
+	err = wh.Update(obj).Error
+	return
+}
+
+// saveVersion updates the record only if its version is not changed.
+func (op *DataOperatorBuilder) saveVersion(wh *gorm.DB, obj interface{}, vc *presets.VersionCondition) (err error) {
+	field, ok := wh.NewScope(obj).FieldByName(vc.FieldName)
+	if !ok {
+		return fmt.Errorf("no field %s", vc.FieldName)
+	}
+
+	result := wh.Where(fmt.Sprintf("%s = ?", field.DBName), vc.Value).Update(obj)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return presets.ErrEditConflict
+	}
 	return
 }
 
 func (op *DataOperatorBuilder) Delete(obj interface{}, id string, ctx *web.EventContext) (err error) {
-	err = op.primarySluggerWhere(obj, id).Delete(obj).Error
+	err = op.primarySluggerWhere(op.dbFrom(ctx), obj, id).Delete(obj).Error
 	return
 }
+
+// SearchTrash searches the soft-deleted records of the model with a DeletedAt field.
+func (op *DataOperatorBuilder) SearchTrash(obj interface{}, params *presets.SearchParams, ctx *web.EventContext) (r interface{}, totalCount int, err error) {
+	db := op.dbFrom(ctx)
+	col, err := deletedAtColumn(db, obj)
+	if err != nil {
+		return
+	}
+	return op.search(db.Unscoped().Where(fmt.Sprintf("%s IS NOT NULL", col)), obj, params)
+}
+
+func (op *DataOperatorBuilder) FetchTrash(obj interface{}, id string, ctx *web.EventContext) (r interface{}, err error) {
+	db := op.dbFrom(ctx)
+	col, err := deletedAtColumn(db, obj)
+	if err != nil {
+		return
+	}
+	err = op.primarySluggerWhere(db.Unscoped().Where(fmt.Sprintf("%s IS NOT NULL", col)), obj, id).Find(obj).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, presets.ErrRecordNotFound
+		}
+		return
+	}
+	r = obj
+	return
+}
+
+func (op *DataOperatorBuilder) Restore(obj interface{}, id string, ctx *web.EventContext) (err error) {
+	db := op.dbFrom(ctx)
+	col, err := deletedAtColumn(db, obj)
+	if err != nil {
+		return
+	}
+	result := op.primarySluggerWhere(db.Unscoped(), obj, id).UpdateColumn(col, nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return presets.ErrRecordNotFound
+	}
+	return
+}
+
+func (op *DataOperatorBuilder) DeletePermanently(obj interface{}, id string, ctx *web.EventContext) (err error) {
+	err = op.primarySluggerWhere(op.dbFrom(ctx).Unscoped(), obj, id).Delete(obj).Error
+	return
+}
+
+// deletedAtColumn returns the column of the DeletedAt field of obj, which can be a slice of the model.
+func deletedAtColumn(db *gorm.DB, obj interface{}) (col string, err error) {
+	scope := db.NewScope(obj)
+	field, ok := scope.FieldByName("DeletedAt")
+	if !ok {
+		return "", fmt.Errorf("%s is not soft-deleted, no DeletedAt field", scope.GetModelStruct().ModelType.Name())
+	}
+	return field.DBName, nil
+}
@@ -0,0 +1,124 @@
+package presets
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/qor5/web"
+)
+
+type HookEvent string
+
+const (
+	BeforeSave     HookEvent = "BeforeSave"
+	AfterSave      HookEvent = "AfterSave"
+	BeforeDelete   HookEvent = "BeforeDelete"
+	AfterDelete    HookEvent = "AfterDelete"
+	AfterPublish   HookEvent = "AfterPublish"
+	AfterUnpublish HookEvent = "AfterUnpublish"
+	AfterRestore   HookEvent = "AfterRestore"
+)
+
+// HookFunc handles the event of the record, old is the record in the database before the change,
+// which is nil for the new record, and obj is the record that is saved, published or restored, which is nil for the deletes.
+type HookFunc func(old interface{}, obj interface{}, ctx *web.EventContext) (err error)
+
+type hook struct {
+	event HookEvent
+	order int
+	f     HookFunc
+}
+
+// Hook subscribes f to the event of the model, so that the modules like activity and l10n don't need to wrap
+// the SaveFunc and DeleteFunc of the editing. The handlers run in the ascending order, and the ones with
+// the same order run in the order they are added. The save and delete hooks run in the same transaction
+// with the Saver and Deleter if the DataOperator implements Transactor, and an error of any handler
+// stops the rest and rolls back the change. See EditingBuilder.Save and EditingBuilder.Delete.
+func (mb *ModelBuilder) Hook(event HookEvent, order int, f HookFunc) (r *ModelBuilder) {
+	mb.hooks = append(mb.hooks, &hook{event: event, order: order, f: f})
+	sort.SliceStable(mb.hooks, func(i, j int) bool {
+		return mb.hooks[i].order < mb.hooks[j].order
+	})
+	return mb
+}
+
+func (mb *ModelBuilder) hasHooks(events ...HookEvent) bool {
+	for _, h := range mb.hooks {
+		for _, e := range events {
+			if h.event == e {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RunHooks runs the handlers of the event, for the events that are not fired by presets, like AfterPublish.
+func (mb *ModelBuilder) RunHooks(event HookEvent, old interface{}, obj interface{}, ctx *web.EventContext) (err error) {
+	for _, h := range mb.hooks {
+		if h.event != event {
+			continue
+		}
+		if err = h.f(old, obj, ctx); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Save saves the record by the Saver with the BeforeSave and AfterSave hooks, the old record is fetched
+// by the Fetcher if the model has any of the hooks. The updates are rejected with ErrEditLeaseHeld while
+// the edit lease of the record is held by someone else.
+func (b *EditingBuilder) Save(obj interface{}, id string, ctx *web.EventContext) (err error) {
+	if err = b.checkEditLeaseHeld(id, ctx); err != nil {
+		return
+	}
+	if !b.mb.hasHooks(BeforeSave, AfterSave) {
+		return b.Saver(obj, id, ctx)
+	}
+	return dataOperatorTransaction(b.mb.p.dataOperator)(ctx, func(ctx *web.EventContext) (err error) {
+		old, err := b.fetchOld(id, ctx)
+		if err != nil {
+			return
+		}
+		if err = b.mb.RunHooks(BeforeSave, old, obj, ctx); err != nil {
+			return
+		}
+		if err = b.Saver(obj, id, ctx); err != nil {
+			return
+		}
+		return b.mb.RunHooks(AfterSave, old, obj, ctx)
+	})
+}
+
+// Delete deletes the record by the Deleter with the BeforeDelete and AfterDelete hooks.
+func (b *EditingBuilder) Delete(obj interface{}, id string, ctx *web.EventContext) (err error) {
+	if !b.mb.hasHooks(BeforeDelete, AfterDelete) {
+		return b.Deleter(obj, id, ctx)
+	}
+	return dataOperatorTransaction(b.mb.p.dataOperator)(ctx, func(ctx *web.EventContext) (err error) {
+		old, err := b.fetchOld(id, ctx)
+		if err != nil {
+			return
+		}
+		if err = b.mb.RunHooks(BeforeDelete, old, nil, ctx); err != nil {
+			return
+		}
+		if err = b.Deleter(obj, id, ctx); err != nil {
+			return
+		}
+		return b.mb.RunHooks(AfterDelete, old, nil, ctx)
+	})
+}
+
+// fetchOld returns nil if the record is not found, like the records that are created with ids.
+func (b *EditingBuilder) fetchOld(id string, ctx *web.EventContext) (old interface{}, err error) {
+	if id == "" && !b.mb.singleton {
+		return
+	}
+	old, err = b.Fetcher(b.mb.NewModel(), id, ctx)
+	if errors.Is(err, ErrRecordNotFound) {
+		return nil, nil
+	}
+	return
+}
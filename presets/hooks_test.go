@@ -0,0 +1,67 @@
+package presets
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/qor5/web"
+)
+
+func TestHooks(t *testing.T) {
+	op := &restAPIItemsOperator{items: map[string]*restAPIItem{
+		"1": {ID: "1", Name: "A", Price: 1},
+	}}
+	mb := New().DataOperator(op).Model(&restAPIItem{})
+
+	var calls []string
+	record := func(name string) HookFunc {
+		return func(old interface{}, obj interface{}, ctx *web.EventContext) (err error) {
+			var oldName, name2 string
+			if old != nil {
+				oldName = old.(*restAPIItem).Name
+			}
+			if obj != nil {
+				name2 = obj.(*restAPIItem).Name
+			}
+			calls = append(calls, fmt.Sprintf("%s:%s->%s", name, oldName, name2))
+			return
+		}
+	}
+	mb.Hook(AfterSave, 10, record("after2")).
+		Hook(AfterSave, 0, record("after1")).
+		Hook(BeforeSave, 0, record("before")).
+		Hook(AfterDelete, 0, record("delete"))
+
+	ctx := &web.EventContext{R: httptest.NewRequest("POST", "/rest-api-items", nil)}
+	eb := mb.Editing()
+	if err := eb.Save(&restAPIItem{ID: "1", Name: "B"}, "1", ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := eb.Save(&restAPIItem{Name: "C"}, "", ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := eb.Delete(&restAPIItem{}, "1", ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"before:A->B", "after1:A->B", "after2:A->B",
+		"before:->C", "after1:->C", "after2:->C",
+		"delete:B->",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+
+	mb.Hook(BeforeSave, 0, func(old interface{}, obj interface{}, ctx *web.EventContext) (err error) {
+		return errors.New("rejected")
+	})
+	if err := eb.Save(&restAPIItem{ID: "3", Name: "D"}, "3", ctx); err == nil || err.Error() != "rejected" {
+		t.Errorf("err = %v", err)
+	}
+	if op.items["3"].Name != "C" {
+		t.Errorf("the record is saved after the error of the hook: %+v", op.items["3"])
+	}
+}
@@ -0,0 +1,450 @@
+package presets
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qor5/admin/presets/actions"
+	. "github.com/qor5/ui/vuetify"
+	"github.com/qor5/web"
+	"github.com/qor5/x/i18n"
+	"github.com/qor5/x/perm"
+	h "github.com/theplant/htmlgo"
+)
+
+const importPortalName = "presets_ImportPortal"
+
+// only the first invalid rows are listed in the dry run report
+const importMaxReportRows = 100
+
+var errImportInvalidRows = errors.New("import: invalid rows")
+
+// ImportBuilder imports records from a CSV file. Every row goes through the same
+// Setter, field setters, Validator and Saver as the creating form, so the validation
+// rules and the hooks wrapping the Saver (like activity) apply to imported records too.
+type ImportBuilder struct {
+	mb              *ModelBuilder
+	fields          []string
+	transactionFunc TransactionFunc
+}
+
+// Import enables importing on the model, the returned builder can be used to configure it.
+func (mb *ModelBuilder) Import() (r *ImportBuilder) {
+	if mb.importer == nil {
+		mb.importer = &ImportBuilder{mb: mb}
+		mb.RegisterEventFunc(actions.OpenImportDialog, mb.importer.openImportDialog)
+		mb.RegisterEventFunc(actions.ImportDryRun, mb.importer.dryRun)
+		mb.RegisterEventFunc(actions.DoImport, mb.importer.doImport)
+	}
+	return mb.importer
+}
+
+// Only sets the fields that the columns can be mapped to, by default all the fields of the creating form.
+func (b *ImportBuilder) Only(vs ...string) (r *ImportBuilder) {
+	b.fields = vs
+	return b
+}
+
+// TransactionFunc sets how the records are saved in a transaction,
+// by default the DataOperator is used if it implements Transactor.
+func (b *ImportBuilder) TransactionFunc(v TransactionFunc) (r *ImportBuilder) {
+	b.transactionFunc = v
+	return b
+}
+
+func (b *ImportBuilder) editingBuilder() *EditingBuilder {
+	if b.mb.creating != nil {
+		return b.mb.creating
+	}
+	return b.mb.editing
+}
+
+func (b *ImportBuilder) transaction() TransactionFunc {
+	if b.transactionFunc != nil {
+		return b.transactionFunc
+	}
+	return dataOperatorTransaction(b.mb.p.dataOperator)
+}
+
+// dataOperatorTransaction returns the transaction of the DataOperator if it implements Transactor,
+// otherwise fc is called without a transaction.
+func dataOperatorTransaction(op DataOperator) TransactionFunc {
+	if t, ok := op.(Transactor); ok {
+		return t.Transaction
+	}
+	return func(ctx *web.EventContext, fc func(ctx *web.EventContext) error) error {
+		return fc(ctx)
+	}
+}
+
+func (b *ImportBuilder) importableFields() (r []*FieldBuilder) {
+	eb := b.editingBuilder()
+	if len(b.fields) > 0 {
+		for _, name := range b.fields {
+			if f := eb.GetField(name); f != nil {
+				r = append(r, f)
+			}
+		}
+		return
+	}
+
+	for _, f := range eb.fields {
+		// nested fields can not be presented by a single column
+		if f.nestedFieldsBuilder != nil {
+			continue
+		}
+		r = append(r, f)
+	}
+	return
+}
+
+func (b *ImportBuilder) fieldLabel(f *FieldBuilder, ctx *web.EventContext) string {
+	return i18n.PT(ctx.R, ModelsI18nModuleKey, b.mb.label, b.editingBuilder().getLabel(f.NameLabel))
+}
+
+func (b *ImportBuilder) readFile(ctx *web.EventContext) (header []string, rows [][]string, err error) {
+	f, _, err := ctx.R.FormFile(ParamImportFile)
+	if err != nil {
+		if err == http.ErrMissingFile {
+			err = errors.New(MustGetMessages(ctx.R).ImportFileRequired)
+		}
+		return
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return
+	}
+	if len(records) == 0 {
+		err = errors.New(MustGetMessages(ctx.R).ImportFileRequired)
+		return
+	}
+
+	header = records[0]
+	// files saved by Excel start with a byte order mark
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	rows = records[1:]
+	return
+}
+
+// columnMapping returns the field name of each column, the columns are mapped to
+// the fields with the same name or label unless the mapping is changed in the dialog.
+func (b *ImportBuilder) columnMapping(header []string, fields []*FieldBuilder, ctx *web.EventContext) (r []string) {
+	r = make([]string, len(header))
+	for i, col := range header {
+		if vs, ok := ctx.R.Form[fmt.Sprintf("%s_%d", ParamImportMapping, i)]; ok {
+			for _, f := range fields {
+				if f.name == vs[0] {
+					r[i] = f.name
+				}
+			}
+			continue
+		}
+
+		col = strings.TrimSpace(col)
+		for _, f := range fields {
+			if strings.EqualFold(col, f.name) || strings.EqualFold(col, b.fieldLabel(f, ctx)) {
+				r[i] = f.name
+				break
+			}
+		}
+	}
+	return
+}
+
+type importRowResult struct {
+	line int
+	vErr web.ValidationErrors
+}
+
+// importRows runs all the rows through the editing flow, and saves them if save is true.
+func (b *ImportBuilder) importRows(rows [][]string, mapping []string, save bool, ctx *web.EventContext) (invalid []*importRowResult, imported int) {
+	eb := b.editingBuilder()
+	var mapped []interface{}
+	for _, name := range mapping {
+		if name != "" {
+			eb.getFieldOrDefault(name)
+			mapped = append(mapped, name)
+		}
+	}
+	// only set the mapped fields, so that the other fields keep the values from the Setter
+	fb := eb.FieldsBuilder.Only(mapped...)
+	if len(mapped) == 0 {
+		fb = eb.FieldsBuilder.Clone()
+	}
+
+	for i, row := range rows {
+		values := url.Values{}
+		for j, name := range mapping {
+			if name != "" && j < len(row) {
+				values.Set(name, row[j])
+			}
+		}
+
+		if vErr := b.importRow(fb, values, save, ctx); vErr.HaveErrors() {
+			// the header is the first line
+			invalid = append(invalid, &importRowResult{line: i + 2, vErr: vErr})
+			continue
+		}
+		imported++
+	}
+	return
+}
+
+func (b *ImportBuilder) importRow(fb *FieldsBuilder, values url.Values, save bool, ctx *web.EventContext) (vErr web.ValidationErrors) {
+	eb := b.editingBuilder()
+	rowCtx := &web.EventContext{
+		R:        importRowRequest(ctx.R, values),
+		W:        ctx.W,
+		Injector: ctx.Injector,
+	}
+
+	obj := b.mb.NewModel()
+	if eb.Setter != nil {
+		eb.Setter(obj, rowCtx)
+	}
+	if vErr = fb.Unmarshal(obj, b.mb.Info(), true, rowCtx); vErr.HaveErrors() {
+		return
+	}
+
+	if b.mb.Info().Verifier().Do(PermCreate).ObjectOn(obj).WithReq(ctx.R).IsAllowed() != nil {
+		vErr.GlobalError(perm.PermissionDenied.Error())
+		return
+	}
+
+	if vErr = eb.validate(obj, rowCtx); vErr.HaveErrors() {
+		return
+	}
+
+	if !save {
+		return
+	}
+	if err := eb.Save(obj, "", rowCtx); err != nil {
+		vErr.GlobalError(err.Error())
+	}
+	return
+}
+
+// importRowRequest returns a request that posts the values of a row like the creating form.
+func importRowRequest(r *http.Request, values url.Values) *http.Request {
+	rr := r.Clone(r.Context())
+	rr.Form = values
+	rr.PostForm = values
+	rr.MultipartForm = &multipart.Form{Value: values}
+	return rr
+}
+
+func (b *ImportBuilder) openImportDialog(ctx *web.EventContext) (r web.EventResponse, err error) {
+	if b.mb.Info().Verifier().Do(PermCreate).WithReq(ctx.R).IsAllowed() != nil {
+		ShowMessage(&r, perm.PermissionDenied.Error(), "warning")
+		return
+	}
+
+	msgr := MustGetMessages(ctx.R)
+	b.mb.p.dialog(
+		&r,
+		VCard(
+			VCardTitle(h.Text(msgr.Import)),
+			VCardText(
+				VFileInput().
+					Label(msgr.ImportFile).
+					FieldName(ParamImportFile).
+					Attr("accept", ".csv,text/csv"),
+			),
+			web.Portal(b.importPanel(nil, nil, nil, false, ctx)).Name(importPortalName),
+		),
+		"800px",
+	)
+	return
+}
+
+func (b *ImportBuilder) dryRun(ctx *web.EventContext) (r web.EventResponse, err error) {
+	if b.mb.Info().Verifier().Do(PermCreate).WithReq(ctx.R).IsAllowed() != nil {
+		ShowMessage(&r, perm.PermissionDenied.Error(), "warning")
+		return
+	}
+
+	header, rows, err1 := b.readFile(ctx)
+	if err1 != nil {
+		b.updateImportPanel(&r, nil, nil, nil, false, err1, ctx)
+		return
+	}
+
+	mapping := b.columnMapping(header, b.importableFields(), ctx)
+	invalid, _ := b.importRows(rows, mapping, false, ctx)
+	ctx.Flash = MustGetMessages(ctx.R).ImportDryRunResult(len(rows), len(invalid))
+	b.updateImportPanel(&r, header, mapping, invalid, true, nil, ctx)
+	return
+}
+
+func (b *ImportBuilder) doImport(ctx *web.EventContext) (r web.EventResponse, err error) {
+	if b.mb.Info().Verifier().Do(PermCreate).WithReq(ctx.R).IsAllowed() != nil {
+		ShowMessage(&r, perm.PermissionDenied.Error(), "warning")
+		return
+	}
+
+	header, rows, err1 := b.readFile(ctx)
+	if err1 != nil {
+		b.updateImportPanel(&r, nil, nil, nil, false, err1, ctx)
+		return
+	}
+
+	mapping := b.columnMapping(header, b.importableFields(), ctx)
+	var invalid []*importRowResult
+	var imported int
+	err1 = b.transaction()(ctx, func(txCtx *web.EventContext) error {
+		invalid, imported = b.importRows(rows, mapping, true, txCtx)
+		if len(invalid) > 0 {
+			return errImportInvalidRows
+		}
+		return nil
+	})
+	if err1 != nil {
+		if err1 == errImportInvalidRows {
+			err1 = nil
+			ctx.Flash = MustGetMessages(ctx.R).ImportDryRunResult(len(rows), len(invalid))
+		}
+		b.updateImportPanel(&r, header, mapping, invalid, true, err1, ctx)
+		return
+	}
+
+	ShowMessage(&r, MustGetMessages(ctx.R).ImportSuccessfully(imported), "")
+	r.PushState = web.Location(nil)
+	web.AppendVarsScripts(&r, closeDialogVarScript)
+	return
+}
+
+func (b *ImportBuilder) updateImportPanel(r *web.EventResponse, header []string, mapping []string, invalid []*importRowResult, dryRun bool, err error, ctx *web.EventContext) {
+	if err != nil {
+		vErr := &web.ValidationErrors{}
+		vErr.GlobalError(err.Error())
+		ctx.Flash = vErr
+	}
+	r.UpdatePortals = append(r.UpdatePortals, &web.PortalUpdate{
+		Name: importPortalName,
+		Body: b.importPanel(header, mapping, invalid, dryRun, ctx),
+	})
+}
+
+type importFieldOption struct {
+	Text  string `json:"text"`
+	Value string `json:"value"`
+}
+
+// importPanel shows the column mapping and the dry run report, the records can be
+// imported only after a dry run without errors.
+func (b *ImportBuilder) importPanel(header []string, mapping []string, invalid []*importRowResult, dryRun bool, ctx *web.EventContext) h.HTMLComponent {
+	msgr := MustGetMessages(ctx.R)
+
+	var alert h.HTMLComponent
+	switch v := ctx.Flash.(type) {
+	case *web.ValidationErrors:
+		alert = VAlert(h.Text(v.GetGlobalError())).
+			Border("left").
+			Type("error").
+			Elevation(2).
+			ColoredBorder(true)
+	case string:
+		alertType := "success"
+		if len(invalid) > 0 {
+			alertType = "warning"
+		}
+		alert = VAlert(h.Text(v)).
+			Border("left").
+			Type(alertType).
+			Elevation(2).
+			ColoredBorder(true)
+	}
+
+	fields := b.importableFields()
+	var mappingTable h.HTMLComponent
+	if len(header) > 0 {
+		options := []importFieldOption{{Text: msgr.ImportSkipColumn, Value: ""}}
+		for _, f := range fields {
+			options = append(options, importFieldOption{Text: b.fieldLabel(f, ctx), Value: f.name})
+		}
+
+		var trs []h.HTMLComponent
+		for i, col := range header {
+			trs = append(trs, h.Tr(
+				h.Td(h.Text(col)),
+				h.Td(VSelect().
+					Items(options).
+					ItemText("text").
+					ItemValue("value").
+					FieldName(fmt.Sprintf("%s_%d", ParamImportMapping, i)).
+					Value(mapping[i]).
+					Dense(true).
+					HideDetails(true)),
+			))
+		}
+		mappingTable = VSimpleTable(
+			h.Thead(h.Tr(h.Th(msgr.ImportColumn), h.Th(msgr.ImportField))),
+			h.Tbody(trs...),
+		).Dense(true).Class("mb-4")
+	}
+
+	var reportTable h.HTMLComponent
+	if len(invalid) > 0 {
+		var trs []h.HTMLComponent
+		for i, row := range invalid {
+			if i >= importMaxReportRows {
+				break
+			}
+			for _, e := range row.vErr.GetGlobalErrors() {
+				trs = append(trs, h.Tr(h.Td(h.Text(fmt.Sprint(row.line))), h.Td(), h.Td(h.Text(e))))
+			}
+			for _, f := range b.editingBuilder().fields {
+				for _, e := range row.vErr.GetFieldErrors(f.name) {
+					trs = append(trs, h.Tr(h.Td(h.Text(fmt.Sprint(row.line))), h.Td(h.Text(b.fieldLabel(f, ctx))), h.Td(h.Text(e))))
+				}
+			}
+		}
+		reportTable = VSimpleTable(
+			h.Thead(h.Tr(h.Th(msgr.ImportRow), h.Th(msgr.ImportField), h.Th(msgr.ImportErrors))),
+			h.Tbody(trs...),
+		).Dense(true)
+	}
+
+	return h.Components(
+		VCardText(
+			alert,
+			mappingTable,
+			reportTable,
+		),
+		VCardActions(
+			VSpacer(),
+			VBtn(msgr.Cancel).
+				Depressed(true).
+				Class("ml-2").
+				Attr("@click", closeDialogVarScript),
+			VBtn(msgr.ImportDryRun).
+				Depressed(true).
+				Class("ml-2").
+				Attr("@click", web.Plaid().EventFunc(actions.ImportDryRun).Go()),
+			VBtn(msgr.Import).
+				Color("primary").
+				Depressed(true).
+				Disabled(!dryRun || len(invalid) > 0).
+				Attr("@click", web.Plaid().EventFunc(actions.DoImport).Go()),
+		),
+	)
+}
+
+func (b *ImportBuilder) button(ctx *web.EventContext) h.HTMLComponent {
+	if b.mb.Info().Verifier().Do(PermCreate).WithReq(ctx.R).IsAllowed() != nil {
+		return nil
+	}
+
+	return VBtn(MustGetMessages(ctx.R).Import).
+		Color(ColorPrimary).
+		Depressed(true).
+		Class("ml-2").
+		Attr("@click", web.Plaid().EventFunc(actions.OpenImportDialog).Go())
+}
@@ -0,0 +1,94 @@
+package presets
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qor5/web"
+)
+
+type importItem struct {
+	ID    uint
+	Name  string
+	Price int
+}
+
+func newImportRequest(t *testing.T, content string, form map[string]string) *web.EventContext {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile(ParamImportFile, "items.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write([]byte(content))
+	for k, v := range form {
+		mw.WriteField(k, v)
+	}
+	mw.Close()
+
+	r := httptest.NewRequest("POST", "/admin/import-items", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	return &web.EventContext{R: r, W: httptest.NewRecorder()}
+}
+
+func TestImport(t *testing.T) {
+	var saved []*importItem
+	var transactions int
+	b := New()
+	mb := b.Model(&importItem{})
+	mb.Editing("Name", "Price").
+		ValidateFunc(func(obj interface{}, ctx *web.EventContext) (err web.ValidationErrors) {
+			if obj.(*importItem).Name == "" {
+				err.FieldError("Name", "Name is required")
+			}
+			return
+		}).
+		SaveFunc(func(obj interface{}, id string, ctx *web.EventContext) (err error) {
+			saved = append(saved, obj.(*importItem))
+			return
+		})
+	ib := mb.Import().TransactionFunc(func(ctx *web.EventContext, fc func(ctx *web.EventContext) error) error {
+		transactions++
+		return fc(ctx)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		ctx := newImportRequest(t, "\ufeffname,Price,Note\nA,1,x\n,2,y\n", nil)
+		r, err := ib.dryRun(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(saved) != 0 || transactions != 0 {
+			t.Errorf("dry run saved %d records in %d transactions", len(saved), transactions)
+		}
+		if len(r.UpdatePortals) != 1 || ctx.Flash != Messages_en_US.ImportDryRunResult(2, 1) {
+			t.Errorf("dry run report = %v", ctx.Flash)
+		}
+	})
+
+	t.Run("field errors", func(t *testing.T) {
+		ctx := newImportRequest(t, "Name,Price\nA,1\n,2\n", nil)
+		invalid, imported := ib.importRows([][]string{{"A", "1"}, {"", "2"}}, ib.columnMapping([]string{"Name", "Price"}, ib.importableFields(), ctx), false, ctx)
+		if imported != 1 || len(invalid) != 1 || invalid[0].line != 3 {
+			t.Fatalf("imported = %d, invalid = %v", imported, invalid)
+		}
+		if errs := invalid[0].vErr.GetFieldErrors("Name"); len(errs) != 1 {
+			t.Errorf("field errors = %v", errs)
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		ctx := newImportRequest(t, "Title,Price\nA,1\nB,2\n", map[string]string{ParamImportMapping + "_0": "Name"})
+		if _, err := ib.doImport(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if transactions != 1 || len(saved) != 2 {
+			t.Fatalf("saved %d records in %d transactions", len(saved), transactions)
+		}
+		if saved[1].Name != "B" || saved[1].Price != 2 {
+			t.Errorf("saved = %+v", saved[1])
+		}
+	})
+}
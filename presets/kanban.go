@@ -0,0 +1,180 @@
+package presets
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qor5/admin/presets/actions"
+	. "github.com/qor5/ui/vuetify"
+	vx "github.com/qor5/ui/vuetifyx"
+	"github.com/qor5/web"
+	"github.com/qor5/x/i18n"
+	"github.com/qor5/x/perm"
+	"github.com/sunfmin/reflectutils"
+	h "github.com/theplant/htmlgo"
+)
+
+// KanbanBuilder shows the records of the listing as the cards in the columns of the values of an enum field,
+// dragging a card to another column updates the field of the record by the Saver of the editing.
+type KanbanBuilder struct {
+	lb       *ListingBuilder
+	field    string
+	columns  []*KanbanColumn
+	cardFunc ObjectComponentFunc
+}
+
+type KanbanColumn struct {
+	Value string
+	// the label is translated in the ModelsI18nModuleKey module, the Value is shown if it is empty
+	Label string
+}
+
+// Kanban adds the kanban layout to the listing, the records are grouped by the field.
+// Moving the cards requires the PermUpdate permission on the field.
+func (b *ListingBuilder) Kanban(field string) (r *KanbanBuilder) {
+	if b.kanban == nil {
+		b.mb.RegisterEventFunc(actions.MoveKanbanCard, b.moveKanbanCard)
+	}
+	b.kanban = &KanbanBuilder{lb: b, field: field}
+	return b.kanban
+}
+
+// Column adds the column of the value, the records of the values without columns are shown in the extra columns.
+// Once the columns are added, the cards can only be moved to them.
+func (b *KanbanBuilder) Column(value string, label string) (r *KanbanBuilder) {
+	b.columns = append(b.columns, &KanbanColumn{Value: value, Label: label})
+	return b
+}
+
+// CardFunc sets the content of the card, by default it is the PageTitle() of the record or its id.
+func (b *KanbanBuilder) CardFunc(v ObjectComponentFunc) (r *KanbanBuilder) {
+	b.cardFunc = v
+	return b
+}
+
+func (b *KanbanBuilder) component(ctx *web.EventContext) h.HTMLComponent {
+	lb := b.lb
+	objs := lb.layoutSearch(ctx, "")
+
+	columns := append([]*KanbanColumn{}, b.columns...)
+	cards := make(map[string][]h.HTMLComponent)
+	for _, obj := range objs {
+		value := fmt.Sprint(reflectutils.MustGet(obj, b.field))
+		if _, ok := cards[value]; !ok && !hasKanbanColumn(columns, value) {
+			columns = append(columns, &KanbanColumn{Value: value})
+		}
+		cards[value] = append(cards[value], b.card(obj, ctx))
+	}
+
+	movable := lb.mb.Info().Verifier().Do(PermUpdate).WithReq(ctx.R).IsAllowed() == nil &&
+		lb.mb.Info().FieldAllowed(PermUpdate, nil, b.field, ctx.R)
+
+	var cols []h.HTMLComponent
+	for _, c := range columns {
+		label := c.Label
+		if label == "" {
+			label = c.Value
+		}
+		cols = append(cols, VCol(
+			VCard(
+				VCardTitle(
+					h.Text(i18n.PT(ctx.R, ModelsI18nModuleKey, lb.mb.label, label)),
+					VChip(h.Text(fmt.Sprint(len(cards[c.Value])))).XSmall(true).Class("ml-2"),
+				).Class("text-subtitle-2"),
+				h.Tag("vx-draggable").
+					Attr("group", "presets_kanban_"+lb.mb.uriName).
+					Attr("animation", "300").
+					Attr(":disabled", fmt.Sprint(!movable)).
+					Attr("data-value", c.Value).
+					Attr("@add", web.Plaid().
+						EventFunc(actions.MoveKanbanCard).
+						Query(ParamID, web.Var("$event.item.dataset.id")).
+						Query(ParamKanbanValue, web.Var("$event.to.dataset.value")).
+						Go()).
+					Children(cards[c.Value]...).
+					Class("px-2 pb-2").
+					Style("min-height: 120px;"),
+			).Color("grey lighten-4").Flat(true),
+		).Attr("style", "min-width: 280px; max-width: 320px;"))
+	}
+
+	return VRow(cols...).Class("flex-nowrap ma-0 pa-2").Attr("style", "overflow-x: auto;")
+}
+
+func hasKanbanColumn(columns []*KanbanColumn, value string) bool {
+	for _, c := range columns {
+		if c.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *KanbanBuilder) card(obj interface{}, ctx *web.EventContext) h.HTMLComponent {
+	id := vx.ObjectID(obj)
+	var content h.HTMLComponent
+	if b.cardFunc != nil {
+		content = b.cardFunc(obj, ctx)
+	} else {
+		content = h.Text(getPageTitle(obj, id))
+	}
+	return VCard(VCardText(content).Class("pa-3")).
+		Attr("data-id", id).
+		Attr("@click", b.lb.recordOnclick(id)).
+		Class("mt-2").
+		Attr("style", "cursor: pointer;")
+}
+
+// moveKanbanCard sets the field of the moved card to the value of its new column, and reloads the kanban.
+func (b *ListingBuilder) moveKanbanCard(ctx *web.EventContext) (r web.EventResponse, err error) {
+	if b.kanban == nil {
+		return r, errors.New("the kanban is not enabled")
+	}
+	defer func() {
+		if err == nil {
+			reload, _ := b.reloadList(ctx)
+			r.UpdatePortals = append(r.UpdatePortals, reload.UpdatePortals...)
+		}
+	}()
+
+	id := ctx.R.FormValue(ParamID)
+	field := b.kanban.field
+	value := ctx.R.FormValue(ParamKanbanValue)
+	if len(b.kanban.columns) > 0 && !hasKanbanColumn(b.kanban.columns, value) {
+		ShowMessage(&r, fmt.Sprintf("%s is not a column of the kanban", value), "warning")
+		return
+	}
+
+	eb := b.mb.editing
+	obj, err1 := eb.Fetcher(b.mb.NewModel(), id, ctx)
+	if err1 != nil {
+		ShowMessage(&r, err1.Error(), "warning")
+		return
+	}
+	if b.mb.Info().Verifier().Do(PermUpdate).ObjectOn(obj).WithReq(ctx.R).IsAllowed() != nil ||
+		!b.mb.Info().FieldAllowed(PermUpdate, obj, field, ctx.R) {
+		ShowMessage(&r, perm.PermissionDenied.Error(), "warning")
+		return
+	}
+	if err1 = reflectutils.Set(obj, field, value); err1 != nil {
+		ShowMessage(&r, err1.Error(), "warning")
+		return
+	}
+	if vErr := eb.validate(obj, ctx); vErr.HaveErrors() {
+		ShowMessage(&r, vErr.Error(), "warning")
+		return
+	}
+	saveCtx := ctx
+	if eb.versionField != "" {
+		saveCtx, err1 = eb.lockVersion(obj, id, ctx)
+	}
+	if err1 == nil {
+		err1 = eb.Save(obj, id, saveCtx)
+	}
+	if err1 != nil {
+		ShowMessage(&r, err1.Error(), "warning")
+		return
+	}
+	ShowMessage(&r, MustGetMessages(ctx.R).SuccessfullyUpdated, "")
+	return
+}
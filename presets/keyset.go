@@ -0,0 +1,304 @@
+package presets
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/qor5/admin/presets/actions"
+	. "github.com/qor5/ui/vuetify"
+	"github.com/qor5/web"
+	"github.com/sunfmin/reflectutils"
+	h "github.com/theplant/htmlgo"
+)
+
+// KeysetPagination pages the listing by the values of the sorted columns of the last record
+// instead of OFFSET, which keeps the big tables fast on the deep pages.
+// The pagination only shows the previous and next buttons, and the query params "after" and "before" are the cursors.
+// The DataOperator needs to support SearchParams.Keyset, like gorm2op does.
+// The records are sorted by the order_by query, or else by OrderBy, with the primary field at last.
+// The sorted columns must be NOT NULL, since the conditions of the cursor can't compare NULL,
+// the search returns an error if any record of the page has a NULL value.
+func (b *ListingBuilder) KeysetPagination(v bool) (r *ListingBuilder) {
+	b.keysetPagination = v
+	return b
+}
+
+// KeysetPaginationCount counts the records in keyset pagination mode, which is skipped by default
+// since counting the big tables is slow. The count is the total of the REST API list response.
+func (b *ListingBuilder) KeysetPaginationCount(v bool) (r *ListingBuilder) {
+	b.keysetCount = v
+	return b
+}
+
+// Condition returns the SQL condition of the records after After,
+// like (a > ?) OR (a = ? AND id > ?) for the order by a ASC, id ASC.
+func (p *KeysetParams) Condition() (query string, args []interface{}) {
+	if len(p.After) == 0 || len(p.After) != len(p.OrderBys) {
+		return
+	}
+
+	var ors []string
+	for i, ob := range p.OrderBys {
+		var ands []string
+		for j := 0; j < i; j++ {
+			ands = append(ands, fmt.Sprintf("%s = ?", p.OrderBys[j].DBColumn))
+			args = append(args, p.After[j])
+		}
+		op := ">"
+		if ob.Desc != p.Backward {
+			op = "<"
+		}
+		ands = append(ands, fmt.Sprintf("%s %s ?", ob.DBColumn, op))
+		args = append(args, p.After[i])
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	query = strings.Join(ors, " OR ")
+	return
+}
+
+// OrderBySQL returns the order by of the OrderBys, which is reversed when Backward.
+func (p *KeysetParams) OrderBySQL() string {
+	var orderBys []string
+	for _, ob := range p.OrderBys {
+		direction := "ASC"
+		if ob.Desc != p.Backward {
+			direction = "DESC"
+		}
+		orderBys = append(orderBys, fmt.Sprintf("%s %s", ob.DBColumn, direction))
+	}
+	return strings.Join(orderBys, ", ")
+}
+
+// Values returns the values of the OrderBys fields of the record.
+func (p *KeysetParams) Values(obj interface{}) (r []interface{}) {
+	for _, ob := range p.OrderBys {
+		v, _ := reflectutils.Get(obj, ob.FieldName)
+		r = append(r, v)
+	}
+	return
+}
+
+func encodeKeysetCursor(values []interface{}) string {
+	bs, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(bs)
+}
+
+// decodeKeysetCursor decodes the cursor into the values with the types of the OrderBys fields of the model.
+func decodeKeysetCursor(cursor string, orderBys []*KeysetOrderBy, model interface{}) (r []interface{}, err error) {
+	bs, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return
+	}
+	var raws []json.RawMessage
+	if err = json.Unmarshal(bs, &raws); err != nil {
+		return
+	}
+	if len(raws) != len(orderBys) {
+		return nil, fmt.Errorf("invalid cursor %q", cursor)
+	}
+
+	for i, ob := range orderBys {
+		t := reflectutils.GetType(model, ob.FieldName)
+		if t == nil {
+			return nil, fmt.Errorf("no field %s", ob.FieldName)
+		}
+		v := reflect.New(t)
+		if err = json.Unmarshal(raws[i], v.Interface()); err != nil {
+			return
+		}
+		r = append(r, v.Elem().Interface())
+	}
+	return
+}
+
+// keysetParams builds the KeysetParams of the current listing request,
+// the primary field is always appended to the order bys so that the order is unique.
+func (b *ListingBuilder) keysetParams(ctx *web.EventContext) (r *KeysetParams) {
+	qs := ctx.R.URL.Query()
+	r = &KeysetParams{WithCount: b.keysetCount}
+
+	hasPrimary := false
+	orderableFieldMap := b.orderableFieldMap()
+	for _, ob := range GetOrderBysFromQuery(qs) {
+		dbCol, ok := orderableFieldMap[ob.FieldName]
+		if !ok {
+			continue
+		}
+		r.OrderBys = append(r.OrderBys, &KeysetOrderBy{FieldName: ob.FieldName, DBColumn: dbCol, Desc: ob.OrderBy == "DESC"})
+	}
+	if len(r.OrderBys) == 0 {
+		r.OrderBys = b.defaultKeysetOrderBys()
+	}
+	for _, ob := range r.OrderBys {
+		if ob.FieldName == b.mb.primaryField {
+			hasPrimary = true
+		}
+	}
+	if !hasPrimary {
+		desc := true
+		if len(r.OrderBys) > 0 {
+			desc = r.OrderBys[len(r.OrderBys)-1].Desc
+		}
+		dbCol, ok := orderableFieldMap[b.mb.primaryField]
+		if !ok {
+			dbCol = b.mb.primaryField
+		}
+		r.OrderBys = append(r.OrderBys, &KeysetOrderBy{FieldName: b.mb.primaryField, DBColumn: dbCol, Desc: desc})
+	}
+
+	cursor := qs.Get(ParamKeysetAfter)
+	if before := qs.Get(ParamKeysetBefore); before != "" {
+		cursor = before
+		r.Backward = true
+	}
+	if cursor == "" {
+		r.Backward = false
+		return
+	}
+	after, err := decodeKeysetCursor(cursor, r.OrderBys, b.mb.model)
+	if err != nil {
+		// start from the first page with an invalid cursor
+		r.Backward = false
+		return
+	}
+	r.After = after
+	return
+}
+
+// defaultKeysetOrderBys parses OrderBy like "created_at DESC, id", the columns are mapped to the fields
+// by the orderable fields, or else by their camel case names.
+func (b *ListingBuilder) defaultKeysetOrderBys() (r []*KeysetOrderBy) {
+	fieldNames := make(map[string]string)
+	for _, v := range b.orderableFields {
+		fieldNames[v.DBColumn] = v.FieldName
+	}
+	for _, s := range strings.Split(b.orderBy, ",") {
+		parts := strings.Fields(s)
+		if len(parts) == 0 {
+			continue
+		}
+		col := parts[0]
+		name, ok := fieldNames[col]
+		if !ok {
+			name = strcase.ToCamel(col[strings.LastIndex(col, ".")+1:])
+			if strings.EqualFold(name, b.mb.primaryField) {
+				name = b.mb.primaryField
+			}
+		}
+		if reflectutils.GetType(b.mb.model, name) == nil {
+			panic(fmt.Sprintf("no field of the order by column %s for the keyset pagination", col))
+		}
+		r = append(r, &KeysetOrderBy{FieldName: name, DBColumn: col, Desc: len(parts) > 1 && strings.EqualFold(parts[1], "DESC")})
+	}
+	return
+}
+
+// keysetCursor encodes the values of the sorted fields of the record, the NULL values are refused.
+func keysetCursor(ks *KeysetParams, obj interface{}) (cursor string, err error) {
+	values := ks.Values(obj)
+	for i, v := range values {
+		if isNullValue(v) {
+			return "", fmt.Errorf("the keyset pagination requires the NOT NULL order by column %s", ks.OrderBys[i].DBColumn)
+		}
+	}
+	return encodeKeysetCursor(values), nil
+}
+
+func isNullValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return true
+	}
+	if valuer, ok := v.(driver.Valuer); ok {
+		dv, err := valuer.Value()
+		return err == nil && dv == nil
+	}
+	return false
+}
+
+// keysetSearch searches a page in keyset pagination mode, one more record is searched to know
+// whether there is a page in the direction. The cursors are empty if there is no previous or next page.
+func (b *ListingBuilder) keysetSearch(searchParams *SearchParams, ctx *web.EventContext) (objs interface{}, totalCount int, prevCursor string, nextCursor string, err error) {
+	ks := searchParams.Keyset
+	perPage := searchParams.PerPage
+	if perPage > 0 {
+		searchParams.PerPage = perPage + 1
+	}
+	objs, totalCount, err = b.Searcher(b.mb.NewModelSlice(), searchParams, ctx)
+	searchParams.PerPage = perPage
+	if err != nil {
+		return
+	}
+
+	v := reflect.ValueOf(objs)
+	more := perPage > 0 && int64(v.Len()) > perPage
+	if more {
+		v = v.Slice(0, int(perPage))
+	}
+	if ks.Backward {
+		reversed := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			reversed.Index(i).Set(v.Index(v.Len() - 1 - i))
+		}
+		v = reversed
+	}
+	objs = v.Interface()
+	if v.Len() == 0 {
+		return
+	}
+	var cursors []string
+	for i := 0; i < v.Len(); i++ {
+		var c string
+		if c, err = keysetCursor(ks, v.Index(i).Interface()); err != nil {
+			return
+		}
+		cursors = append(cursors, c)
+	}
+
+	hasPrev, hasNext := len(ks.After) > 0, more
+	if ks.Backward {
+		hasPrev, hasNext = more, true
+	}
+	if hasPrev {
+		prevCursor = cursors[0]
+	}
+	if hasNext {
+		nextCursor = cursors[len(cursors)-1]
+	}
+	return
+}
+
+// keysetPaginationComponent shows the previous and next buttons, which are disabled without the cursor.
+func (b *ListingBuilder) keysetPaginationComponent(prevCursor string, nextCursor string, ctx *web.EventContext, inDialog bool) h.HTMLComponent {
+	onclick := func(key string, cursor string, otherKey string) string {
+		e := web.Plaid().
+			Query(key, cursor).
+			Query(otherKey, "").
+			MergeQuery(true)
+		if inDialog {
+			e.URL(ctx.R.RequestURI).
+				EventFunc(actions.UpdateListingDialog)
+		} else {
+			e.PushState(true)
+		}
+		return e.Go()
+	}
+
+	return h.Div(
+		VBtn("").Icon(true).
+			Disabled(prevCursor == "").
+			Children(VIcon("navigate_before")).
+			Attr("@click", onclick(ParamKeysetBefore, prevCursor, ParamKeysetAfter)),
+		VBtn("").Icon(true).
+			Disabled(nextCursor == "").
+			Children(VIcon("navigate_next")).
+			Attr("@click", onclick(ParamKeysetAfter, nextCursor, ParamKeysetBefore)),
+	).Class("d-flex justify-end mt-2")
+}
@@ -0,0 +1,118 @@
+package presets
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/qor5/web"
+)
+
+type keysetItem struct {
+	ID   uint
+	Name string
+}
+
+func TestKeysetParams(t *testing.T) {
+	p := &KeysetParams{
+		OrderBys: []*KeysetOrderBy{
+			{FieldName: "Name", DBColumn: "name"},
+			{FieldName: "ID", DBColumn: "id", Desc: true},
+		},
+		After: []interface{}{"a", 3},
+	}
+	cond, args := p.Condition()
+	if cond != "(name > ?) OR (name = ? AND id < ?)" || !reflect.DeepEqual(args, []interface{}{"a", "a", 3}) {
+		t.Errorf("condition = %s %v", cond, args)
+	}
+	if orderBy := p.OrderBySQL(); orderBy != "name ASC, id DESC" {
+		t.Errorf("order by = %s", orderBy)
+	}
+
+	p.Backward = true
+	if cond, _ = p.Condition(); cond != "(name < ?) OR (name = ? AND id > ?)" {
+		t.Errorf("backward condition = %s", cond)
+	}
+	if orderBy := p.OrderBySQL(); orderBy != "name DESC, id ASC" {
+		t.Errorf("backward order by = %s", orderBy)
+	}
+
+	lb := New().Model(&restAPIItem{}).Listing().KeysetPagination(true)
+	ctx := &web.EventContext{R: httptest.NewRequest("GET", "/rest-api-items", nil)}
+	if lb.keysetParams(ctx).WithCount {
+		t.Errorf("the records are counted by default")
+	}
+	if !lb.KeysetPaginationCount(true).keysetParams(ctx).WithCount {
+		t.Errorf("the records are not counted with KeysetPaginationCount")
+	}
+
+	// the default order is used without the order_by query
+	lb = New().Model(&keysetItem{}).Listing().KeysetPagination(true).OrderBy("name ASC")
+	if orderBy := lb.keysetParams(ctx).OrderBySQL(); orderBy != "name ASC, ID ASC" {
+		t.Errorf("default order by = %s", orderBy)
+	}
+	if _, err := keysetCursor(&KeysetParams{OrderBys: []*KeysetOrderBy{{FieldName: "Note", DBColumn: "note"}}}, &struct{ Note *string }{}); err == nil {
+		t.Errorf("the cursor of the NULL value is encoded")
+	}
+}
+
+func TestKeysetSearch(t *testing.T) {
+	var items []*keysetItem
+	for i := 1; i <= 5; i++ {
+		items = append(items, &keysetItem{ID: uint(i)})
+	}
+
+	b := New()
+	lb := b.Model(&keysetItem{}).Listing().KeysetPagination(true)
+	// ordered by ID DESC by default
+	lb.SearchFunc(func(model interface{}, params *SearchParams, ctx *web.EventContext) (r interface{}, totalCount int, err error) {
+		ks := params.Keyset
+		var objs []*keysetItem
+		for i := range items {
+			item := items[len(items)-1-i]
+			if ks.Backward {
+				item = items[i]
+			}
+			if len(ks.After) > 0 {
+				after := ks.After[0].(uint)
+				if (!ks.Backward && item.ID >= after) || (ks.Backward && item.ID <= after) {
+					continue
+				}
+			}
+			if int64(len(objs)) == params.PerPage {
+				break
+			}
+			objs = append(objs, item)
+		}
+		return objs, 0, nil
+	})
+
+	search := func(query string) (ids []uint, prevCursor string, nextCursor string) {
+		ctx := &web.EventContext{R: httptest.NewRequest("GET", "/keyset-items?"+query, nil)}
+		objs, _, prevCursor, nextCursor, err := lb.keysetSearch(lb.newSearchParams(ctx, 2), ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, obj := range objs.([]*keysetItem) {
+			ids = append(ids, obj.ID)
+		}
+		return
+	}
+
+	ids, prev, next := search("")
+	if !reflect.DeepEqual(ids, []uint{5, 4}) || prev != "" || next == "" {
+		t.Fatalf("first page = %v, %q, %q", ids, prev, next)
+	}
+	ids, prev, next = search(ParamKeysetAfter + "=" + next)
+	if !reflect.DeepEqual(ids, []uint{3, 2}) || prev == "" || next == "" {
+		t.Fatalf("second page = %v, %q, %q", ids, prev, next)
+	}
+	ids, _, last := search(ParamKeysetAfter + "=" + next)
+	if !reflect.DeepEqual(ids, []uint{1}) || last != "" {
+		t.Fatalf("last page = %v, %q", ids, last)
+	}
+	ids, prev, _ = search(ParamKeysetBefore + "=" + prev)
+	if !reflect.DeepEqual(ids, []uint{5, 4}) || prev != "" {
+		t.Fatalf("previous page = %v, %q", ids, prev)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -22,16 +23,20 @@ import (
 type ListingBuilder struct {
 	mb              *ModelBuilder
 	bulkActions     []*BulkActionBuilder
+	bulkEditFields  []string
 	actions         []*ActionBuilder
 	actionsAsMenu   bool
 	rowMenu         *RowMenuBuilder
 	filterDataFunc  FilterDataFunc
+	filters         []*FilterBuilder
 	filterTabsFunc  FilterTabsFunc
 	newBtnFunc      ComponentFunc
 	pageFunc        web.PageFunc
 	cellWrapperFunc vx.CellWrapperFunc
 	Searcher        SearchFunc
+	customSearcher  bool
 	searchColumns   []string
+	fullTextSearch  bool
 
 	// title is the title of the listing page.
 	// its default value is "Listing ${modelName}".
@@ -50,12 +55,27 @@ type ListingBuilder struct {
 	// 3. all data will be returned in one page.
 	disablePagination bool
 
+	// keysetPagination pages by cursors instead of page numbers, see KeysetPagination.
+	keysetPagination bool
+	// see KeysetPaginationCount
+	keysetCount bool
+
 	orderBy           string
 	orderableFields   []*OrderableField
 	selectableColumns bool
 	conditions        []*SQLCondition
 	dialogWidth       string
 	dialogHeight      string
+	exporter          *ExportBuilder
+	viewStore         ListingViewStore
+	trash             *TrashBuilder
+	globalSearch      *GlobalSearchBuilder
+	aggregates        []*Aggregate
+	Aggregator        AggregateFunc
+	kanban            *KanbanBuilder
+	calendar          *CalendarBuilder
+	tree              *TreeBuilder
+	defaultLayout     string
 	FieldsBuilder
 }
 
@@ -102,6 +122,7 @@ func (b *ListingBuilder) DisablePagination(v bool) (r *ListingBuilder) {
 
 func (b *ListingBuilder) SearchFunc(v SearchFunc) (r *ListingBuilder) {
 	b.Searcher = v
+	b.customSearcher = true
 	return b
 }
 
@@ -115,6 +136,13 @@ func (b *ListingBuilder) SearchColumns(vs ...string) (r *ListingBuilder) {
 	return b
 }
 
+// FullTextSearch searches the keyword in the SearchColumns by the full-text search of the DataOperator,
+// the records are ordered by the relevance unless the listing is sorted by a column or paged by keyset.
+func (b *ListingBuilder) FullTextSearch(v bool) (r *ListingBuilder) {
+	b.fullTextSearch = v
+	return b
+}
+
 func (b *ListingBuilder) PerPage(v int64) (r *ListingBuilder) {
 	b.perPage = v
 	return b
@@ -236,6 +264,39 @@ func (b *ListingBuilder) listingComponent(
 			}
 		}
 
+		if b.exporter != nil {
+			if btn := b.exporter.button(ctx); btn != nil {
+				actionsComponent = append(actionsComponent, btn)
+			}
+		}
+
+		if !inDialog && !b.inTrash(ctx) {
+			if btns := b.layoutButtons(ctx); btns != nil {
+				actionsComponent = append(actionsComponent, btns)
+			}
+		}
+
+		if !inDialog {
+			if btn := b.listingViewButton(ctx); btn != nil {
+				actionsComponent = append(actionsComponent, btn)
+			}
+		}
+
+		if b.mb.importer != nil && !inDialog {
+			if btn := b.mb.importer.button(ctx); btn != nil {
+				actionsComponent = append(actionsComponent, btn)
+			}
+		}
+
+		if b.trash != nil && !inDialog {
+			// only the trash actions are available in the trash
+			if b.inTrash(ctx) {
+				filterTabs = nil
+				actionsComponent = b.trash.bulkButtons(ctx)
+			}
+			actionsComponent = append(actionsComponent, b.trash.button(ctx))
+		}
+
 		if filterTabs != nil || len(actionsComponent) > 0 {
 			tabsAndActionsBar = VToolbar(
 				filterTabs,
@@ -310,6 +371,9 @@ func (b *ListingBuilder) listingComponent(
 
 func (b *ListingBuilder) cellComponentFunc(f *FieldBuilder) vx.CellComponentFunc {
 	return func(obj interface{}, fieldName string, ctx *web.EventContext) h.HTMLComponent {
+		if b.isCellEditable(obj, f, ctx) {
+			return h.Td(b.editableCell(obj, f.name, nil, ctx))
+		}
 		return f.compFunc(obj, b.mb.getComponentFuncField(f), ctx)
 	}
 }
@@ -360,13 +424,7 @@ func (b *ListingBuilder) bulkPanel(
 			if bulk.selectedIdsProcessorNoticeFunc != nil {
 				noticeText = bulk.selectedIdsProcessorNoticeFunc(selectedIds, processedSelectedIds, unactionables)
 			} else {
-				var idsText string
-				if len(unactionables) <= 10 {
-					idsText = strings.Join(unactionables, ", ")
-				} else {
-					idsText = fmt.Sprintf("%s...(+%d)", strings.Join(unactionables[:10], ", "), len(unactionables)-10)
-				}
-				noticeText = msgr.BulkActionSelectedIdsProcessNotice(idsText)
+				noticeText = msgr.BulkActionSelectedIdsProcessNotice(bulkIdsText(unactionables))
 			}
 			processSelectedIdsNotice = VAlert(h.Text(noticeText)).
 				Type("warning")
@@ -381,7 +439,7 @@ func (b *ListingBuilder) bulkPanel(
 	}
 	return VCard(
 		VCardTitle(
-			h.Text(bulk.NameLabel.label),
+			h.Text(bulkActionLabel(bulk, bulk.NameLabel.label, ctx)),
 		),
 		VCardText(
 			errComp,
@@ -670,19 +728,23 @@ func (b *ListingBuilder) filterTabs(
 	ctx *web.EventContext,
 	inDialog bool,
 ) (r h.HTMLComponent) {
-	if b.filterTabsFunc == nil {
+	var tabsData []*FilterTab
+	if b.filterTabsFunc != nil {
+		tabsData = b.filterTabsFunc(ctx)
+		for i, tab := range tabsData {
+			if tab.ID == "" {
+				tab.ID = fmt.Sprintf("tab%d", i)
+			}
+		}
+	}
+	tabsData = append(tabsData, b.listingViewTabs(ctx, inDialog)...)
+	if len(tabsData) == 0 {
 		return
 	}
 
 	qs := ctx.R.URL.Query()
 
 	tabs := VTabs().ShowArrows(true)
-	tabsData := b.filterTabsFunc(ctx)
-	for i, tab := range tabsData {
-		if tab.ID == "" {
-			tab.ID = fmt.Sprintf("tab%d", i)
-		}
-	}
 	value := -1
 	activeTabValue := qs.Get(ActiveFilterTabQueryKey)
 
@@ -727,22 +789,24 @@ type sortedColumn struct {
 	Label string `json:"label"`
 }
 
-func (b *ListingBuilder) selectColumnsBtn(
+func selectColumnsParamNames(pageURL *url.URL) (displayColumnsName string, sortedColumnsName string) {
+	_, respath := path.Split(pageURL.Path)
+	return fmt.Sprintf("%s_display_columns", respath), fmt.Sprintf("%s_sorted_columns", respath)
+}
+
+// displaySortedColumns resolves the columns the user selected to display and their order,
+// from the url params or the cookie data, and returns the fields to render in that order.
+func (b *ListingBuilder) displaySortedColumns(
 	pageURL *url.URL,
 	ctx *web.EventContext,
-	inDialog bool,
-) (btn h.HTMLComponent, displaySortedFields []*FieldBuilder) {
+) (displayColumns []string, sortedColumns []string, displaySortedFields []*FieldBuilder) {
 	var (
-		_, respath         = path.Split(pageURL.Path)
-		displayColumnsName = fmt.Sprintf("%s_display_columns", respath)
-		sortedColumnsName  = fmt.Sprintf("%s_sorted_columns", respath)
-		originalColumns    []string
-		displayColumns     []string
-		sortedColumns      []string
+		displayColumnsName, sortedColumnsName = selectColumnsParamNames(pageURL)
+		originalColumns                       []string
 	)
 
 	for _, f := range b.fields {
-		if b.mb.Info().Verifier().Do(PermList).SnakeOn("f_"+f.name).WithReq(ctx.R).IsAllowed() != nil {
+		if !b.mb.Info().FieldAllowed(PermList, nil, f.name, ctx.R) {
 			continue
 		}
 		originalColumns = append(originalColumns, f.name)
@@ -859,6 +923,17 @@ func (b *ListingBuilder) selectColumnsBtn(
 		}
 	}
 
+	return
+}
+
+func (b *ListingBuilder) selectColumnsBtn(
+	pageURL *url.URL,
+	ctx *web.EventContext,
+	inDialog bool,
+) (btn h.HTMLComponent, displaySortedFields []*FieldBuilder) {
+	displayColumnsName, sortedColumnsName := selectColumnsParamNames(pageURL)
+	displayColumns, sortedColumns, displaySortedFields := b.displaySortedColumns(pageURL, ctx)
+
 	// set the data for selected columns on toolbar
 	selectColumns := selectColumns{
 		DisplayColumns: displayColumns,
@@ -963,6 +1038,8 @@ func (b *ListingBuilder) filterBar(
 			URL(ctx.R.RequestURI).
 			StringQuery(web.Var("$event.encodedFilterData")).
 			Query("page", 1).
+			Query(ParamKeysetAfter, "").
+			Query(ParamKeysetBefore, "").
 			ClearMergeQuery(web.Var("$event.filterKeys")).
 			EventFunc(actions.UpdateListingDialog).
 			Go())
@@ -1085,51 +1162,23 @@ func newQueryWithFieldToggleOrderBy(query url.Values, fieldName string) url.Valu
 	return newQuery
 }
 
-func (b *ListingBuilder) getTableComponents(
-	ctx *web.EventContext,
-	inDialog bool,
-) (
-	dataTable h.HTMLComponent,
-	// pagination, no-record message
-	datatableAdditions h.HTMLComponent,
-) {
-	msgr := MustGetMessages(ctx.R)
+// orderableFieldMap returns map[FieldName]DBColumn of the orderable fields.
+func (b *ListingBuilder) orderableFieldMap() map[string]string {
+	r := make(map[string]string)
+	for _, v := range b.orderableFields {
+		r[v.FieldName] = v.DBColumn
+	}
+	return r
+}
 
+// newSearchParams builds the SearchParams of the current listing request,
+// including keyword, order by, conditions and filters from the url query.
+func (b *ListingBuilder) newSearchParams(ctx *web.EventContext, perPage int64) (searchParams *SearchParams) {
 	qs := ctx.R.URL.Query()
 
-	var perPage int64 = 0
-	if !b.disablePagination {
-		var requestPerPage int64
-		qPerPageStr := qs.Get("per_page")
-		qPerPage, _ := strconv.ParseInt(qPerPageStr, 10, 64)
-		if qPerPage != 0 {
-			setLocalPerPage(ctx, b.mb, qPerPage)
-			requestPerPage = qPerPage
-		} else if cPerPage := getLocalPerPage(ctx, b.mb); cPerPage != 0 {
-			requestPerPage = cPerPage
-		}
-
-		perPage = b.perPage
-		if requestPerPage != 0 {
-			perPage = requestPerPage
-		}
-		if perPage == 0 {
-			perPage = 50
-		}
-		if perPage > 1000 {
-			perPage = 1000
-		}
-
-	}
-
 	var orderBySQL string
-	orderBys := GetOrderBysFromQuery(qs)
-	// map[FieldName]DBColumn
-	orderableFieldMap := make(map[string]string)
-	for _, v := range b.orderableFields {
-		orderableFieldMap[v.FieldName] = v.DBColumn
-	}
-	for _, ob := range orderBys {
+	orderableFieldMap := b.orderableFieldMap()
+	for _, ob := range GetOrderBysFromQuery(qs) {
 		dbCol, ok := orderableFieldMap[ob.FieldName]
 		if !ok {
 			continue
@@ -1140,6 +1189,7 @@ func (b *ListingBuilder) getTableComponents(
 	if orderBySQL != "" {
 		orderBySQL = orderBySQL[:len(orderBySQL)-1]
 	}
+	sortedByQuery := orderBySQL != ""
 	if orderBySQL == "" {
 		if b.orderBy != "" {
 			orderBySQL = b.orderBy
@@ -1147,23 +1197,33 @@ func (b *ListingBuilder) getTableComponents(
 			orderBySQL = fmt.Sprintf("%s DESC", b.mb.primaryField)
 		}
 	}
-	searchParams := &SearchParams{
+	searchParams = &SearchParams{
 		KeywordColumns: b.searchColumns,
 		Keyword:        qs.Get("keyword"),
 		PerPage:        perPage,
 		OrderBy:        orderBySQL,
 		PageURL:        ctx.R.URL,
 		SQLConditions:  b.conditions,
+		FullText:       b.fullTextSearch,
 	}
+	searchParams.OrderByRelevance = searchParams.FullText && searchParams.Keyword != "" && !sortedByQuery
 
 	searchParams.Page, _ = strconv.ParseInt(qs.Get("page"), 10, 64)
 	if searchParams.Page == 0 {
 		searchParams.Page = 1
 	}
 
-	var fd vx.FilterData
+	// the trash is paged by page numbers
+	if b.keysetPagination && !b.inTrash(ctx) {
+		searchParams.Page = 1
+		searchParams.Keyset = b.keysetParams(ctx)
+		searchParams.OrderBy = searchParams.Keyset.OrderBySQL()
+		// the cursors can not be built from the relevance
+		searchParams.OrderByRelevance = false
+	}
+
 	if b.filterDataFunc != nil {
-		fd = b.filterDataFunc(ctx)
+		fd := b.filterDataFunc(ctx)
 		cond, args := fd.SetByQueryString(ctx.R.URL.RawQuery)
 
 		searchParams.SQLConditions = append(searchParams.SQLConditions, &SQLCondition{
@@ -1171,6 +1231,53 @@ func (b *ListingBuilder) getTableComponents(
 			Args:  args,
 		})
 	}
+	return
+}
+
+func (b *ListingBuilder) getTableComponents(
+	ctx *web.EventContext,
+	inDialog bool,
+) (
+	dataTable h.HTMLComponent,
+	// pagination, no-record message
+	datatableAdditions h.HTMLComponent,
+) {
+	if layoutComp, ok := b.layoutComponents(ctx); ok {
+		return layoutComp, nil
+	}
+
+	msgr := MustGetMessages(ctx.R)
+
+	qs := ctx.R.URL.Query()
+
+	var perPage int64 = 0
+	if !b.disablePagination {
+		var requestPerPage int64
+		qPerPageStr := qs.Get("per_page")
+		qPerPage, _ := strconv.ParseInt(qPerPageStr, 10, 64)
+		if qPerPage != 0 {
+			setLocalPerPage(ctx, b.mb, qPerPage)
+			requestPerPage = qPerPage
+		} else if cPerPage := getLocalPerPage(ctx, b.mb); cPerPage != 0 {
+			requestPerPage = cPerPage
+		}
+
+		perPage = b.perPage
+		if requestPerPage != 0 {
+			perPage = requestPerPage
+		}
+		if perPage == 0 {
+			perPage = 50
+		}
+		if perPage > 1000 {
+			perPage = 1000
+		}
+
+	}
+
+	orderBys := GetOrderBysFromQuery(qs)
+	orderableFieldMap := b.orderableFieldMap()
+	searchParams := b.newSearchParams(ctx, perPage)
 
 	if b.Searcher == nil || b.mb.p.dataOperator == nil {
 		panic("presets.New().DataOperator(...) required")
@@ -1178,15 +1285,23 @@ func (b *ListingBuilder) getTableComponents(
 
 	var objs interface{}
 	var totalCount int
+	var prevCursor, nextCursor string
 	var err error
 
-	objs, totalCount, err = b.Searcher(b.mb.NewModelSlice(), searchParams, ctx)
+	inTrash := b.inTrash(ctx)
+	if inTrash {
+		objs, totalCount, err = b.trash.search(searchParams, ctx)
+	} else if searchParams.Keyset != nil {
+		objs, totalCount, prevCursor, nextCursor, err = b.keysetSearch(searchParams, ctx)
+	} else {
+		objs, totalCount, err = b.Searcher(b.mb.NewModelSlice(), searchParams, ctx)
+	}
 
 	if err != nil {
 		panic(err)
 	}
 
-	haveCheckboxes := len(b.bulkActions) > 0
+	haveCheckboxes := len(b.bulkActions) > 0 || inTrash
 
 	var cellWraperFunc = func(cell h.MutableAttrHTMLComponent, id string, obj interface{}, dataTableID string) h.HTMLComponent {
 		tdbind := cell
@@ -1215,6 +1330,14 @@ func (b *ListingBuilder) getTableComponents(
 	if b.cellWrapperFunc != nil {
 		cellWraperFunc = b.cellWrapperFunc
 	}
+	rowMenuItemFuncs := b.RowMenu().listingItemFuncs(ctx)
+	if inTrash {
+		// the deleted records can not be fetched, so the cells don't open them like the default wrapper
+		cellWraperFunc = func(cell h.MutableAttrHTMLComponent, id string, obj interface{}, dataTableID string) h.HTMLComponent {
+			return cell
+		}
+		rowMenuItemFuncs = b.trash.rowMenuItemFuncs()
+	}
 
 	var displayFields = b.fields
 	var selectColumnsBtn h.HTMLComponent
@@ -1255,6 +1378,9 @@ func (b *ListingBuilder) getTableComponents(
 					)
 				qs.Del("__execute_event__")
 				newQuery := newQueryWithFieldToggleOrderBy(qs, field)
+				// the cursors are built from the old order bys
+				newQuery.Del(ParamKeysetAfter)
+				newQuery.Del(ParamKeysetBefore)
 				onclick := web.Plaid().
 					Queries(newQuery)
 				if inDialog {
@@ -1274,7 +1400,7 @@ func (b *ListingBuilder) getTableComponents(
 			row.SetAttr(":class", fmt.Sprintf(`{"vx-list-item--active primary--text": vars.presetsRightDrawer && vars.currEditingListItemID==="%s-%s"}`, dataTableID, id))
 			return row
 		}).
-		RowMenuItemFuncs(b.RowMenu().listingItemFuncs(ctx)...).
+		RowMenuItemFuncs(rowMenuItemFuncs...).
 		Selectable(haveCheckboxes).
 		SelectionParamName(ParamSelectedIds).
 		SelectedCountLabel(msgr.ListingSelectedCountNotice).
@@ -1312,14 +1438,21 @@ func (b *ListingBuilder) getTableComponents(
 	}
 	dataTable = sDataTable
 
+	var columns []string
 	for _, f := range displayFields {
-		if b.mb.Info().Verifier().Do(PermList).SnakeOn("f_"+f.name).WithReq(ctx.R).IsAllowed() != nil {
+		if !b.mb.Info().FieldAllowed(PermList, nil, f.name, ctx.R) {
 			continue
 		}
 		f = b.getFieldOrDefault(f.name) // fill in empty compFunc and setter func with default
 		dataTable.(*vx.DataTableBuilder).Column(f.name).
 			Title(i18n.PT(ctx.R, ModelsI18nModuleKey, b.mb.label, b.mb.getLabel(f.NameLabel))).
 			CellComponentFunc(b.cellComponentFunc(f))
+		columns = append(columns, f.name)
+	}
+
+	// the trash is not aggregated
+	if len(b.aggregates) > 0 && !inTrash && reflect.ValueOf(objs).Len() > 0 {
+		sDataTable.Tfoot(b.aggregatesRow(searchParams, columns, haveCheckboxes, len(rowMenuItemFuncs) > 0 || selectColumnsBtn != nil, ctx))
 	}
 
 	if b.disablePagination {
@@ -1327,6 +1460,14 @@ func (b *ListingBuilder) getTableComponents(
 		// the pagination component and the no-record message to page.
 		return
 	}
+	if searchParams.Keyset != nil {
+		if reflect.ValueOf(objs).Len() > 0 {
+			datatableAdditions = b.keysetPaginationComponent(prevCursor, nextCursor, ctx, inDialog)
+		} else {
+			datatableAdditions = h.Div(h.Text(msgr.ListingNoRecordToShow)).Class("mt-10 text-center grey--text text--darken-2")
+		}
+		return
+	}
 	if totalCount > 0 {
 		tpb := vx.VXTablePagination().
 			Total(int64(totalCount)).
@@ -1408,7 +1549,7 @@ func (b *ListingBuilder) actionsComponent(
 				onclick.URL(ctx.R.RequestURI).
 					Query(ParamInDialog, inDialog)
 			}
-			btn = VBtn(b.mb.getLabel(ba.NameLabel)).
+			btn = VBtn(bulkActionLabel(ba, b.mb.getLabel(ba.NameLabel), ctx)).
 				Color(buttonColor).
 				Depressed(true).
 				Dark(true).
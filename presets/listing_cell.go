@@ -0,0 +1,161 @@
+package presets
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qor5/admin/presets/actions"
+	. "github.com/qor5/ui/vuetify"
+	vx "github.com/qor5/ui/vuetifyx"
+	"github.com/qor5/web"
+	"github.com/qor5/x/perm"
+	h "github.com/theplant/htmlgo"
+)
+
+// Editable renders the editing component of the field in the listing cell, only for the listing fields.
+// The field is saved alone by the Saver of the editing, with the setter of the editing field and the Validator,
+// the users without PermUpdate on the field see the listing component.
+func (b *FieldBuilder) Editable() (r *FieldBuilder) {
+	b.editable = true
+	return b
+}
+
+func listingCellPortalName(id string, field string) string {
+	return fmt.Sprintf("listingCell_%s_%s", id, field)
+}
+
+func (b *ListingBuilder) isCellEditable(obj interface{}, f *FieldBuilder, ctx *web.EventContext) bool {
+	if !f.editable || b.inTrash(ctx) {
+		return false
+	}
+	return b.mb.Info().FieldAllowed(PermUpdate, obj, f.name, ctx.R)
+}
+
+// cellFields returns the editing field of the cell, or a new field with the default editing component
+// if the field is not in the editing form.
+func (b *ListingBuilder) cellFields(name string) (r *FieldsBuilder) {
+	eb := b.mb.editing
+	r = eb.FieldsBuilder.Clone()
+	if f := eb.GetField(name); f != nil {
+		r.fields = []*FieldBuilder{f}
+	} else {
+		r.Field(name)
+	}
+	r.getFieldOrDefault(name)
+	return
+}
+
+// editableCell renders the field in its own form, so that the cells of the same field in different rows don't conflict.
+func (b *ListingBuilder) editableCell(obj interface{}, name string, vErr *web.ValidationErrors, ctx *web.EventContext) h.HTMLComponent {
+	id := vx.ObjectID(obj)
+	return web.Scope(
+		web.Portal(b.editableCellContent(obj, id, name, vErr, ctx)).Name(listingCellPortalName(id, name)),
+	).VSlot("{ plaidForm }")
+}
+
+func (b *ListingBuilder) editableCellContent(obj interface{}, id string, name string, vErr *web.ValidationErrors, ctx *web.EventContext) h.HTMLComponent {
+	if vErr == nil {
+		vErr = &web.ValidationErrors{}
+	}
+	f := b.cellFields(name).GetField(name)
+
+	onclick := web.Plaid().
+		URL(b.mb.Info().ListingHref()).
+		EventFunc(actions.UpdateListingCell).
+		Query(ParamID, id).
+		Query(ParamListingCellField, f.name)
+	if IsInDialog(ctx.R.Context()) {
+		onclick.URL(ctx.R.RequestURI).
+			Query(ParamInDialog, true).
+			Query(ParamListingQueries, ctx.Queries().Encode())
+	} else if isInDialogFromQuery(ctx) {
+		// the cell is re-rendered with the errors by the event of the cell in the dialog
+		onclick.URL(ctx.R.RequestURI)
+	}
+	return h.Div(
+		b.mb.editing.versionHiddenField(obj),
+		h.Div(
+			f.compFunc(obj, &FieldContext{
+				ModelInfo: b.mb.Info(),
+				Name:      f.name,
+				FormKey:   f.name,
+				Errors:    vErr.GetFieldErrors(f.name),
+				Context:   f.context,
+			}, ctx),
+		).Class("flex-grow-1"),
+		VBtn("").Icon(true).Small(true).
+			Children(VIcon("check").Small(true)).
+			Attr("@click", onclick.Go()),
+	).Class("d-flex align-center")
+}
+
+// updateListingCell saves the field of the cell like the editing form, then reloads the listing,
+// so that the whole row is re-rendered with the fields changed by the Saver.
+func (b *ListingBuilder) updateListingCell(ctx *web.EventContext) (r web.EventResponse, err error) {
+	id := ctx.R.FormValue(ParamID)
+	name := ctx.R.FormValue(ParamListingCellField)
+	if f := b.GetField(name); f == nil || !f.editable {
+		return r, fmt.Errorf("listing field %s is not editable", name)
+	}
+
+	msgr := MustGetMessages(ctx.R)
+	eb := b.mb.editing
+	obj, err1 := eb.Fetcher(b.mb.NewModel(), id, ctx)
+	if err1 != nil {
+		ShowMessage(&r, err1.Error(), "warning")
+		return
+	}
+	if !b.mb.Info().FieldAllowed(PermUpdate, obj, name, ctx.R) {
+		ShowMessage(&r, perm.PermissionDenied.Error(), "warning")
+		return
+	}
+
+	vErr := b.cellFields(name).Unmarshal(obj, b.mb.Info(), true, ctx)
+	if !vErr.HaveErrors() {
+		vErr = eb.validate(obj, ctx)
+	}
+	if vErr.HaveErrors() {
+		// the global errors and the errors of the other fields can not be shown in the cell
+		if gErr := vErr.GetGlobalError(); gErr != "" {
+			ShowMessage(&r, gErr, "warning")
+		} else if len(vErr.GetFieldErrors(name)) == 0 {
+			ShowMessage(&r, vErr.Error(), "warning")
+		}
+		r.UpdatePortals = append(r.UpdatePortals, &web.PortalUpdate{
+			Name: listingCellPortalName(id, name),
+			Body: b.editableCellContent(obj, id, name, &vErr, ctx),
+		})
+		return
+	}
+
+	saveCtx := ctx
+	if eb.versionField != "" {
+		saveCtx, err1 = eb.lockVersion(obj, id, ctx)
+	}
+	if err1 == nil {
+		err1 = eb.Save(obj, id, saveCtx)
+	}
+	if err1 != nil {
+		if !errors.Is(err1, ErrEditConflict) {
+			ShowMessage(&r, err1.Error(), "warning")
+			return
+		}
+		ShowMessage(&r, msgr.EditConflictNotice, "warning")
+	} else {
+		ShowMessage(&r, msgr.SuccessfullyUpdated, "")
+	}
+
+	// the Saver may change the other fields of the row, like the version
+	if isInDialogFromQuery(ctx) {
+		web.AppendVarsScripts(&r,
+			web.Plaid().
+				URL(ctx.R.RequestURI).
+				EventFunc(actions.UpdateListingDialog).
+				StringQuery(ctx.R.URL.Query().Get(ParamListingQueries)).
+				Go(),
+		)
+	} else {
+		r.PushState = web.Location(nil)
+	}
+	return
+}
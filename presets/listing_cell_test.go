@@ -0,0 +1,82 @@
+package presets
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qor5/web"
+)
+
+type listingCellItem struct {
+	ID    uint
+	Name  string
+	Stock int
+}
+
+func TestUpdateListingCell(t *testing.T) {
+	stored := listingCellItem{ID: 1, Name: "A", Stock: 2}
+	var saved *listingCellItem
+
+	b := New().DataOperator(&restAPIItemsOperator{})
+	mb := b.Model(&listingCellItem{})
+	mb.Editing("Name").
+		FetchFunc(func(obj interface{}, id string, ctx *web.EventContext) (r interface{}, err error) {
+			v := stored
+			return &v, nil
+		}).
+		SaveFunc(func(obj interface{}, id string, ctx *web.EventContext) (err error) {
+			saved = obj.(*listingCellItem)
+			stored = *saved
+			return
+		}).
+		ValidateFunc(func(obj interface{}, ctx *web.EventContext) (err web.ValidationErrors) {
+			if obj.(*listingCellItem).Stock < 0 {
+				err.FieldError("Stock", "stock can not be negative")
+			}
+			return
+		})
+	lb := mb.Listing("Name", "Stock")
+	lb.Field("Stock").Editable()
+
+	update := func(field string, value string) (r web.EventResponse, err error) {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		mw.WriteField(ParamID, "1")
+		mw.WriteField(ParamListingCellField, field)
+		mw.WriteField(field, value)
+		mw.Close()
+		req := httptest.NewRequest("POST", "/listing-cell-items?__execute_event__=presets_UpdateListingCell", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		return lb.updateListingCell(&web.EventContext{R: req, W: httptest.NewRecorder()})
+	}
+
+	r, err := update("Stock", "-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved != nil || len(r.UpdatePortals) != 1 {
+		t.Fatalf("saved = %+v, portals = %d", saved, len(r.UpdatePortals))
+	}
+	content, _ := r.UpdatePortals[0].Body.MarshalHTML(context.TODO())
+	if !strings.Contains(string(content), "stock can not be negative") {
+		t.Errorf("content = %s", content)
+	}
+
+	if r, err = update("Stock", "5"); err != nil {
+		t.Fatal(err)
+	}
+	if saved == nil || saved.Stock != 5 || saved.Name != "A" {
+		t.Fatalf("saved = %+v", saved)
+	}
+	if r.PushState == nil || len(r.UpdatePortals) != 0 {
+		t.Errorf("the listing is not reloaded, portals = %+v", r.UpdatePortals)
+	}
+
+	if _, err = update("Name", "B"); err == nil {
+		t.Errorf("not editable field is updated")
+	}
+}
@@ -0,0 +1,206 @@
+package presets
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/iancoleman/strcase"
+	vx "github.com/qor5/ui/vuetifyx"
+	"github.com/qor5/web"
+	"github.com/qor5/x/i18n"
+)
+
+// FilterBuilder overrides the filter generated from the type of the field, see ListingBuilder.Filters.
+type FilterBuilder struct {
+	lb           *ListingBuilder
+	name         string
+	label        string
+	column       string
+	options      []*vx.SelectItem
+	folded       bool
+	itemFunc     func(ctx *web.EventContext) *vx.FilterItem
+	autocomplete *vx.AutocompleteDataSource
+}
+
+// Filters generates the filters of the fields from their types, strings can be equal to or contain the value,
+// numbers and times are filtered by ranges, bools and the fields with Options are selected, and the belongs-to
+// associations, like CategoryID with a Category field, are selected by the autocomplete of the associated model.
+// It replaces the FilterDataFunc, use Filter(name) to override the generated filter.
+func (b *ListingBuilder) Filters(vs ...string) (r *ListingBuilder) {
+	var filters []*FilterBuilder
+	for _, name := range vs {
+		filters = append(filters, b.Filter(name))
+	}
+	b.filters = filters
+	b.FilterDataFunc(b.generatedFilterData)
+	return b
+}
+
+// Filter returns the filter of the field to override it, it is added to the filters if it is not in the Filters.
+func (b *ListingBuilder) Filter(name string) (r *FilterBuilder) {
+	for _, f := range b.filters {
+		if f.name == name {
+			return f
+		}
+	}
+
+	r = &FilterBuilder{lb: b, name: name}
+	b.filters = append(b.filters, r)
+	b.FilterDataFunc(b.generatedFilterData)
+	return
+}
+
+func (b *FilterBuilder) Label(v string) (r *FilterBuilder) {
+	b.label = v
+	return b
+}
+
+// Column sets the database column of the filter, its default value is the snake case of the field name.
+func (b *FilterBuilder) Column(v string) (r *FilterBuilder) {
+	b.column = v
+	return b
+}
+
+// Options makes the filter a select of the options, for the enums.
+func (b *FilterBuilder) Options(v ...*vx.SelectItem) (r *FilterBuilder) {
+	b.options = v
+	return b
+}
+
+func (b *FilterBuilder) Folded(v bool) (r *FilterBuilder) {
+	b.folded = v
+	return b
+}
+
+// ItemFunc replaces the generated filter item, the key of the item is the snake case of the field name if it is empty.
+func (b *FilterBuilder) ItemFunc(v func(ctx *web.EventContext) *vx.FilterItem) (r *FilterBuilder) {
+	b.itemFunc = v
+	return b
+}
+
+func (b *ListingBuilder) generatedFilterData(ctx *web.EventContext) (r vx.FilterData) {
+	for _, f := range b.filters {
+		if !b.mb.Info().FieldAllowed(PermList, nil, f.name, ctx.R) {
+			continue
+		}
+		var it *vx.FilterItem
+		if f.itemFunc != nil {
+			it = f.itemFunc(ctx)
+		} else {
+			it = f.item(ctx)
+		}
+		if it.Key == "" {
+			it.Key = strcase.ToSnake(f.name)
+		}
+		r = append(r, it)
+	}
+	return
+}
+
+// modelField returns the struct field of the model, and the field of its belongs-to association if there is one.
+func (b *FilterBuilder) modelField() (field reflect.StructField, association *reflect.StructField) {
+	t := indirectType(reflect.TypeOf(b.lb.mb.model))
+	field, ok := t.FieldByName(b.name)
+	if !ok {
+		panic(fmt.Sprintf("filter %s: no field %s in %s", b.name, b.name, t.Name()))
+	}
+	if strings.HasSuffix(b.name, "ID") {
+		if af, ok := t.FieldByName(strings.TrimSuffix(b.name, "ID")); ok && indirectType(af.Type).Kind() == reflect.Struct {
+			association = &af
+		}
+	}
+	return
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// configureAutocomplete configures the autocomplete of the associated model for the belongs-to filter,
+// it is called when the mux is initialized, after all the models are registered.
+func (b *FilterBuilder) configureAutocomplete() {
+	if b.itemFunc != nil || b.options != nil || b.autocomplete != nil {
+		return
+	}
+	_, af := b.modelField()
+	if af == nil {
+		return
+	}
+	m := b.lb.mb.p.GetModelBuilder(reflect.New(indirectType(af.Type)).Interface())
+	if m == nil {
+		return
+	}
+	b.autocomplete = m.listing.ConfigureAutocompleteDataSource(&AutocompleteDataSourceConfig{
+		OptionValue: m.primaryField,
+		OptionText: func(obj interface{}) string {
+			return getPageTitle(obj, vx.ObjectID(obj))
+		},
+	}, "filter")
+}
+
+func (b *ListingBuilder) configureFilters() {
+	for _, f := range b.filters {
+		f.configureAutocomplete()
+	}
+}
+
+// quoteColumn quotes every part of the column, like "table"."column".
+func quoteColumn(col string) string {
+	parts := strings.Split(col, ".")
+	for i, p := range parts {
+		parts[i] = `"` + strings.ReplaceAll(p, `"`, `""`) + `"`
+	}
+	return strings.Join(parts, ".")
+}
+
+func (b *FilterBuilder) item(ctx *web.EventContext) (r *vx.FilterItem) {
+	mb := b.lb.mb
+	column := b.column
+	if column == "" {
+		column = strcase.ToSnake(b.name)
+	}
+	column = quoteColumn(column)
+	label := b.label
+	if label == "" {
+		label = i18n.PT(ctx.R, ModelsI18nModuleKey, mb.label, mb.getLabel(NameLabel{name: b.name}))
+	}
+
+	r = &vx.FilterItem{
+		Key:          strcase.ToSnake(b.name),
+		Label:        label,
+		Folded:       b.folded,
+		SQLCondition: fmt.Sprintf("%s %s ?", column, vx.SQLOperatorPlaceholder),
+	}
+
+	field, _ := b.modelField()
+	t := indirectType(field.Type)
+	switch {
+	case b.options != nil:
+		r.ItemType = vx.ItemTypeSelect
+		r.Options = b.options
+	case b.autocomplete != nil:
+		r.ItemType = vx.ItemTypeSelect
+		r.AutocompleteDataSource = b.autocomplete
+	case t == reflect.TypeOf(time.Time{}):
+		r.ItemType = vx.ItemTypeDatetimeRange
+	case t.Kind() == reflect.Bool:
+		msgr := MustGetMessages(ctx.R)
+		r.ItemType = vx.ItemTypeSelect
+		r.Options = []*vx.SelectItem{
+			{Text: msgr.Yes, Value: "true", SQLCondition: fmt.Sprintf("%s = true", column)},
+			{Text: msgr.No, Value: "false", SQLCondition: fmt.Sprintf("%s = false", column)},
+		}
+	case t.Kind() == reflect.String:
+		r.ItemType = vx.ItemTypeString
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Float64:
+		r.ItemType = vx.ItemTypeNumber
+	default:
+		panic(fmt.Sprintf("filter %s: can not generate the filter of %s, use ItemFunc(...)", b.name, field.Type))
+	}
+	return
+}
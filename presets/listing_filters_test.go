@@ -0,0 +1,62 @@
+package presets
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	vx "github.com/qor5/ui/vuetifyx"
+	"github.com/qor5/web"
+)
+
+type filterCategory struct {
+	ID   uint
+	Name string
+}
+
+type filterProduct struct {
+	ID         uint
+	Name       string
+	Price      float64
+	Published  bool
+	Status     string
+	CreatedAt  time.Time
+	CategoryID uint
+	Category   *filterCategory
+}
+
+func TestFilters(t *testing.T) {
+	b := New().DataOperator(&restAPIItemsOperator{})
+	lb := b.Model(&filterProduct{}).Listing().
+		Filters("Name", "Price", "Published", "Status", "CreatedAt", "CategoryID")
+	lb.Filter("Status").Options(
+		&vx.SelectItem{Text: "Draft", Value: "draft"},
+		&vx.SelectItem{Text: "Online", Value: "online"},
+	)
+	b.Model(&filterCategory{})
+	lb.configureFilters()
+
+	ctx := &web.EventContext{R: httptest.NewRequest("GET", "/filter-products", nil), W: httptest.NewRecorder()}
+	fd := lb.filterDataFunc(ctx)
+	var types []vx.FilterItemType
+	for _, it := range fd {
+		types = append(types, it.ItemType)
+	}
+	if !reflect.DeepEqual(types, []vx.FilterItemType{
+		vx.ItemTypeString, vx.ItemTypeNumber, vx.ItemTypeSelect, vx.ItemTypeSelect, vx.ItemTypeDatetimeRange, vx.ItemTypeSelect,
+	}) {
+		t.Errorf("types = %v", types)
+	}
+	if fd[5].Key != "f_category_id" || fd[5].AutocompleteDataSource == nil {
+		t.Errorf("category filter = %+v", fd[5])
+	}
+
+	cond, args := fd.SetByQueryString(`f_name.ilike=a'b&f_price.gte=1&f_published=true&f_category_id=2`)
+	if cond != `"category_id" = ? AND "name" ILIKE ? AND "price" >= ? AND "published" = true` {
+		t.Errorf("condition = %s", cond)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"2", "%a'b%", "1"}) {
+		t.Errorf("args = %v", args)
+	}
+}
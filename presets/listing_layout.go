@@ -0,0 +1,143 @@
+package presets
+
+import (
+	"reflect"
+
+	"github.com/qor5/admin/presets/actions"
+	. "github.com/qor5/ui/vuetify"
+	"github.com/qor5/web"
+	h "github.com/theplant/htmlgo"
+)
+
+const (
+	ListingLayoutTable    = "table"
+	ListingLayoutKanban   = "kanban"
+	ListingLayoutCalendar = "calendar"
+	ListingLayoutTree     = "tree"
+)
+
+// layoutMaxRecords limits the records of the kanban, the calendar and the levels of the tree, which are not paged.
+const layoutMaxRecords = 1000
+
+// DefaultLayout sets the layout of the listing when the url query has none, see Kanban, Calendar and Tree.
+func (b *ListingBuilder) DefaultLayout(v string) (r *ListingBuilder) {
+	b.defaultLayout = v
+	return b
+}
+
+// layout returns the layout of the current request, the listing dialog and the trash are always tables.
+func (b *ListingBuilder) layout(ctx *web.EventContext) string {
+	if IsInDialog(ctx.R.Context()) || b.inTrash(ctx) {
+		return ListingLayoutTable
+	}
+	v := ctx.R.URL.Query().Get(ParamListingLayout)
+	if v == "" {
+		v = b.defaultLayout
+	}
+	switch {
+	case v == ListingLayoutKanban && b.kanban != nil:
+		return ListingLayoutKanban
+	case v == ListingLayoutCalendar && b.calendar != nil:
+		return ListingLayoutCalendar
+	case v == ListingLayoutTree && b.tree != nil:
+		return ListingLayoutTree
+	}
+	return ListingLayoutTable
+}
+
+// layoutComponents renders the records in the layout other than the table.
+func (b *ListingBuilder) layoutComponents(ctx *web.EventContext) (r h.HTMLComponent, ok bool) {
+	switch b.layout(ctx) {
+	case ListingLayoutKanban:
+		return b.kanban.component(ctx), true
+	case ListingLayoutCalendar:
+		return b.calendar.component(ctx), true
+	case ListingLayoutTree:
+		return b.tree.component(ctx), true
+	}
+	return nil, false
+}
+
+// layoutSearch searches the records with the keyword, the conditions and the filters of the listing,
+// conds like the date range of the calendar are added to them. The order of the listing is used if orderBy is empty.
+func (b *ListingBuilder) layoutSearch(ctx *web.EventContext, orderBy string, conds ...*SQLCondition) (objs []interface{}) {
+	searchParams := b.newSearchParams(ctx, layoutMaxRecords)
+	searchParams.Page = 1
+	searchParams.Keyset = nil
+	searchParams.SQLConditions = append(append([]*SQLCondition{}, searchParams.SQLConditions...), conds...)
+	if orderBy != "" {
+		searchParams.OrderBy = orderBy
+		searchParams.OrderByRelevance = false
+	}
+
+	r, _, err := b.Searcher(b.mb.NewModelSlice(), searchParams, ctx)
+	if err != nil {
+		panic(err)
+	}
+	return objectsOf(r)
+}
+
+func objectsOf(r interface{}) (objs []interface{}) {
+	v := reflect.ValueOf(r)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	for i := 0; i < v.Len(); i++ {
+		objs = append(objs, v.Index(i).Interface())
+	}
+	return
+}
+
+// layoutButtons switches the layouts, the other queries like the filters are kept.
+func (b *ListingBuilder) layoutButtons(ctx *web.EventContext) h.HTMLComponent {
+	if b.kanban == nil && b.calendar == nil && b.tree == nil {
+		return nil
+	}
+	msgr := MustGetMessages(ctx.R)
+	current := b.layout(ctx)
+
+	btn := func(layout string, icon string, title string) h.HTMLComponent {
+		return VBtn("").Icon(true).
+			Children(VIcon(icon).Small(true)).
+			Attr("title", title).
+			Attr("value", layout).
+			Attr("@click", web.Plaid().
+				PushState(true).
+				MergeQuery(true).
+				Query(ParamListingLayout, layout).
+				Query("page", "").
+				Query(ParamKeysetAfter, "").
+				Query(ParamKeysetBefore, "").
+				Go())
+	}
+
+	btns := []h.HTMLComponent{btn(ListingLayoutTable, "view_list", msgr.ListingLayoutTable)}
+	if b.kanban != nil {
+		btns = append(btns, btn(ListingLayoutKanban, "view_week", msgr.ListingLayoutKanban))
+	}
+	if b.calendar != nil {
+		btns = append(btns, btn(ListingLayoutCalendar, "calendar_today", msgr.ListingLayoutCalendar))
+	}
+	if b.tree != nil {
+		btns = append(btns, btn(ListingLayoutTree, "account_tree", msgr.ListingLayoutTree))
+	}
+	return VBtnToggle(btns...).Value(current).Dense(true).Mandatory(true).Class("ml-2")
+}
+
+// recordOnclick opens the record like the cells of the listing do.
+func (b *ListingBuilder) recordOnclick(id string) string {
+	mb := b.mb
+	if mb.hasDetailing && !mb.detailing.drawer {
+		return web.Plaid().PushStateURL(mb.Info().DetailingHref(id)).Go()
+	}
+
+	event := actions.Edit
+	if mb.hasDetailing {
+		event = actions.DetailingDrawer
+	}
+	return web.Plaid().
+		URL(mb.Info().ListingHref()).
+		EventFunc(event).
+		Query(ParamID, id).
+		Go()
+}
@@ -0,0 +1,92 @@
+package presets
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qor5/web"
+)
+
+func TestKanban(t *testing.T) {
+	op := &restAPIItemsOperator{items: map[string]*restAPIItem{
+		"1": {ID: "1", Name: "A", Note: "todo"},
+		"2": {ID: "2", Name: "B", Note: "done"},
+		"3": {ID: "3", Name: "C", Note: "archived"},
+	}}
+	b := New().DataOperator(op)
+	lb := b.Model(&restAPIItem{}).Listing()
+	lb.Kanban("Note").Column("todo", "To Do").Column("done", "Done")
+
+	newCtx := func(url string) *web.EventContext {
+		return &web.EventContext{R: httptest.NewRequest("POST", url, nil), W: httptest.NewRecorder()}
+	}
+	if l := lb.layout(newCtx("/rest-api-items?presets_listing_layout=kanban")); l != ListingLayoutKanban {
+		t.Errorf("layout = %s", l)
+	}
+	if l := lb.layout(newCtx("/rest-api-items?presets_listing_layout=calendar")); l != ListingLayoutTable {
+		t.Errorf("the layout without the calendar is %s", l)
+	}
+
+	ctx := newCtx("/rest-api-items?presets_listing_layout=kanban")
+	comp, ok := lb.layoutComponents(ctx)
+	if !ok {
+		t.Fatalf("the kanban is not rendered")
+	}
+	content, _ := comp.MarshalHTML(context.TODO())
+	for _, s := range []string{"To Do", "data-value='done'", "data-value='archived'", "data-id='3'"} {
+		if !strings.Contains(string(content), s) {
+			t.Errorf("%s is not in the kanban", s)
+		}
+	}
+
+	if _, err := lb.moveKanbanCard(newCtx("/rest-api-items?__execute_event__=presets_MoveKanbanCard&id=1&presets_kanban_value=done")); err != nil {
+		t.Fatal(err)
+	}
+	if n := op.items["1"].Note; n != "done" {
+		t.Errorf("the note of the moved card is %s", n)
+	}
+}
+
+type calendarItem struct {
+	ID    uint
+	Start time.Time
+	End   *time.Time
+}
+
+func TestCalendar(t *testing.T) {
+	date := time.Date(2024, 2, 15, 10, 0, 0, 0, time.Local)
+	start, end := calendarRange(CalendarViewMonth, date)
+	if start.Format(calendarDateLayout) != "2024-01-28" || end.Format(calendarDateLayout) != "2024-03-03" {
+		t.Errorf("month range = %s - %s", start, end)
+	}
+	start, end = calendarRange(CalendarViewWeek, date)
+	if start.Format(calendarDateLayout) != "2024-02-11" || end.Format(calendarDateLayout) != "2024-02-18" {
+		t.Errorf("week range = %s - %s", start, end)
+	}
+
+	cb := New().DataOperator(&restAPIItemsOperator{}).Model(&calendarItem{}).Listing().
+		Calendar("Start", "start").EndField("End", "end")
+	endAt := time.Date(2024, 2, 20, 9, 0, 0, 0, time.Local)
+	days := cb.days(&calendarItem{Start: time.Date(2024, 2, 9, 18, 0, 0, 0, time.Local), End: &endAt}, start, end)
+	if len(days) != 7 || days[0].Format(calendarDateLayout) != "2024-02-11" {
+		t.Errorf("days in the week = %v", days)
+	}
+	if days = cb.days(&calendarItem{Start: date}, start, end); len(days) != 1 || days[0].Day() != 15 {
+		t.Errorf("days without the end = %v", days)
+	}
+	if days = cb.days(&calendarItem{}, start, end); len(days) != 0 {
+		t.Errorf("days without the start = %v", days)
+	}
+
+	// the months are moved from the first day, so March 31 goes to February and April
+	ctx := &web.EventContext{R: httptest.NewRequest("GET", "/calendar-items?presets_calendar_date=2024-03-31", nil)}
+	content, _ := cb.component(ctx).MarshalHTML(context.TODO())
+	for _, s := range []string{"March 2024", "2024-02-01", "2024-04-01", ">Sun<"} {
+		if !strings.Contains(string(content), s) {
+			t.Errorf("%s is not in the calendar", s)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package presets
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/qor5/admin/presets/actions"
+	. "github.com/qor5/ui/vuetify"
+	"github.com/qor5/web"
+	"github.com/qor5/x/perm"
+	h "github.com/theplant/htmlgo"
+)
+
+// ListingView is a listing state saved by a user with a name,
+// Query is the encoded url query of the listing, like the filters, keyword, order_by, columns and per_page.
+type ListingView struct {
+	ID    string
+	Name  string
+	Query string
+	// shared to all the users who can list the model
+	Shared bool
+	// created by the current user, only the owner can update or delete the view
+	Owned bool
+}
+
+// ListingViewStore saves the listing views of the models, see gorm2op.ListingViewStore.
+// model is the uri name of the model.
+type ListingViewStore interface {
+	// ListViews returns the views created by the current user and the shared views.
+	ListViews(model string, ctx *web.EventContext) ([]*ListingView, error)
+	// SaveView creates the view if its ID is empty, otherwise updates the view of the current user.
+	SaveView(model string, view *ListingView, ctx *web.EventContext) error
+	// DeleteView deletes the view of the current user.
+	DeleteView(model string, id string, ctx *web.EventContext) error
+}
+
+const listingViewTabIDPrefix = "view_"
+
+// SavedViews lets the users save the current listing state as a named view, the views are shown next to the filter tabs.
+// Sharing a view to everyone requires the PermShareListingView permission.
+func (b *ListingBuilder) SavedViews(v ListingViewStore) (r *ListingBuilder) {
+	if b.viewStore == nil && v != nil {
+		b.mb.RegisterEventFunc(actions.OpenSaveListingViewDialog, b.openSaveListingViewDialog)
+		b.mb.RegisterEventFunc(actions.SaveListingView, b.saveListingView)
+		b.mb.RegisterEventFunc(actions.DeleteListingView, b.deleteListingView)
+	}
+	b.viewStore = v
+	return b
+}
+
+// listingViewTabs returns the saved views as filter tabs, whose query is the whole listing query.
+func (b *ListingBuilder) listingViewTabs(ctx *web.EventContext, inDialog bool) (r []*FilterTab) {
+	if b.viewStore == nil || inDialog {
+		return
+	}
+	views, err := b.viewStore.ListViews(b.mb.uriName, ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, v := range views {
+		query, _ := url.ParseQuery(v.Query)
+		label := h.Components(h.Text(v.Name))
+		if v.Shared {
+			label = append(label, VIcon("people").Small(true).Class("ml-1"))
+		}
+		if v.Owned {
+			label = append(label, VIcon("close").Small(true).Class("ml-1").
+				Attr("@click.stop", web.Plaid().
+					EventFunc(actions.DeleteListingView).
+					Query(ParamListingViewID, v.ID).
+					Go()))
+		}
+		r = append(r, &FilterTab{
+			ID:            listingViewTabIDPrefix + v.ID,
+			Label:         v.Name,
+			AdvancedLabel: label,
+			Query:         query,
+		})
+	}
+	return
+}
+
+// activeListingView returns the view of the active tab if it is owned by the current user.
+func (b *ListingBuilder) activeListingView(ctx *web.EventContext) (r *ListingView, err error) {
+	id := strings.TrimPrefix(ctx.R.URL.Query().Get(ActiveFilterTabQueryKey), listingViewTabIDPrefix)
+	if id == "" || id == ctx.R.URL.Query().Get(ActiveFilterTabQueryKey) {
+		return
+	}
+	views, err := b.viewStore.ListViews(b.mb.uriName, ctx)
+	if err != nil {
+		return
+	}
+	for _, v := range views {
+		if v.ID == id && v.Owned {
+			return v, nil
+		}
+	}
+	return
+}
+
+// listingViewQuery returns the current listing state as the url query,
+// the columns and per page saved in the cookies are included too.
+func (b *ListingBuilder) listingViewQuery(ctx *web.EventContext) url.Values {
+	qs := ctx.R.URL.Query()
+	for _, k := range []string{web.EventFuncIDName, "page", ParamKeysetAfter, ParamKeysetBefore, ActiveFilterTabQueryKey,
+		ParamListingViewID, ParamListingViewName, ParamListingViewShared} {
+		qs.Del(k)
+	}
+
+	if !b.disablePagination && qs.Get("per_page") == "" {
+		if perPage := getLocalPerPage(ctx, b.mb); perPage != 0 {
+			qs.Set("per_page", fmt.Sprint(perPage))
+		}
+	}
+	if b.selectableColumns {
+		displayColumnsName, sortedColumnsName := selectColumnsParamNames(ctx.R.URL)
+		displayColumns, sortedColumns, _ := b.displaySortedColumns(ctx.R.URL, ctx)
+		qs.Set(displayColumnsName, strings.Join(displayColumns, ","))
+		qs.Set(sortedColumnsName, strings.Join(sortedColumns, ","))
+	}
+	return qs
+}
+
+func (b *ListingBuilder) listingViewButton(ctx *web.EventContext) h.HTMLComponent {
+	if b.viewStore == nil {
+		return nil
+	}
+
+	return VBtn(MustGetMessages(ctx.R).SaveListingView).
+		Depressed(true).
+		Class("ml-2").
+		Attr("@click", web.Plaid().EventFunc(actions.OpenSaveListingViewDialog).Go())
+}
+
+func (b *ListingBuilder) openSaveListingViewDialog(ctx *web.EventContext) (r web.EventResponse, err error) {
+	if b.mb.Info().Verifier().Do(PermList).WithReq(ctx.R).IsAllowed() != nil {
+		ShowMessage(&r, perm.PermissionDenied.Error(), "warning")
+		return
+	}
+
+	view, err := b.activeListingView(ctx)
+	if err != nil {
+		return
+	}
+	if view == nil {
+		view = &ListingView{}
+	}
+
+	msgr := MustGetMessages(ctx.R)
+	canShare := b.mb.Info().Verifier().Do(PermShareListingView).WithReq(ctx.R).IsAllowed() == nil
+	saveEvent := func(id string) string {
+		return web.Plaid().
+			EventFunc(actions.SaveListingView).
+			Query(ParamListingViewID, id).
+			Go()
+	}
+
+	var buttons []h.HTMLComponent
+	if view.ID != "" {
+		buttons = append(buttons,
+			VBtn(msgr.SaveListingViewAsNew).
+				Depressed(true).
+				Class("ml-2").
+				Attr("@click", saveEvent("")),
+			VBtn(msgr.Update).
+				Color("primary").
+				Depressed(true).
+				Class("ml-2").
+				Attr("@click", saveEvent(view.ID)),
+		)
+	} else {
+		buttons = append(buttons, VBtn(msgr.Create).
+			Color("primary").
+			Depressed(true).
+			Class("ml-2").
+			Attr("@click", saveEvent("")))
+	}
+
+	b.mb.p.dialog(
+		&r,
+		VCard(
+			VCardTitle(h.Text(msgr.SaveListingView)),
+			VCardText(
+				VTextField().
+					Label(msgr.ListingViewName).
+					FieldName(ParamListingViewName).
+					Value(view.Name),
+				h.If(canShare,
+					VCheckbox().
+						Label(msgr.ListingViewShared).
+						FieldName(ParamListingViewShared).
+						InputValue(view.Shared),
+				),
+			),
+			VCardActions(
+				VSpacer(),
+				VBtn(msgr.Cancel).
+					Depressed(true).
+					Class("ml-2").
+					Attr("@click", closeDialogVarScript),
+				h.Components(buttons...),
+			),
+		),
+		"500px",
+	)
+	return
+}
+
+func (b *ListingBuilder) saveListingView(ctx *web.EventContext) (r web.EventResponse, err error) {
+	if b.mb.Info().Verifier().Do(PermList).WithReq(ctx.R).IsAllowed() != nil {
+		ShowMessage(&r, perm.PermissionDenied.Error(), "warning")
+		return
+	}
+
+	msgr := MustGetMessages(ctx.R)
+	view := &ListingView{
+		ID:     ctx.R.FormValue(ParamListingViewID),
+		Name:   strings.TrimSpace(ctx.R.FormValue(ParamListingViewName)),
+		Shared: ctx.R.FormValue(ParamListingViewShared) == "true",
+	}
+	if view.Name == "" {
+		ShowMessage(&r, msgr.ListingViewNameRequired, "warning")
+		return
+	}
+	if view.Shared && b.mb.Info().Verifier().Do(PermShareListingView).WithReq(ctx.R).IsAllowed() != nil {
+		ShowMessage(&r, perm.PermissionDenied.Error(), "warning")
+		return
+	}
+
+	query := b.listingViewQuery(ctx)
+	view.Query = query.Encode()
+	if err = b.viewStore.SaveView(b.mb.uriName, view, ctx); err != nil {
+		return
+	}
+
+	query.Set(ActiveFilterTabQueryKey, listingViewTabIDPrefix+view.ID)
+	ShowMessage(&r, msgr.ListingViewSaved, "")
+	r.PushState = web.Location(query)
+	web.AppendVarsScripts(&r, closeDialogVarScript)
+	return
+}
+
+func (b *ListingBuilder) deleteListingView(ctx *web.EventContext) (r web.EventResponse, err error) {
+	if err = b.viewStore.DeleteView(b.mb.uriName, ctx.R.FormValue(ParamListingViewID), ctx); err != nil {
+		return
+	}
+
+	ShowMessage(&r, MustGetMessages(ctx.R).ListingViewDeleted, "")
+	r.PushState = web.Location(nil)
+	return
+}
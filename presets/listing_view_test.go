@@ -0,0 +1,71 @@
+package presets
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/qor5/web"
+)
+
+type listingViewItem struct {
+	ID   uint
+	Name string
+}
+
+type memoryListingViewStore struct {
+	views []*ListingView
+}
+
+func (s *memoryListingViewStore) ListViews(model string, ctx *web.EventContext) ([]*ListingView, error) {
+	return s.views, nil
+}
+
+func (s *memoryListingViewStore) SaveView(model string, view *ListingView, ctx *web.EventContext) error {
+	if view.ID == "" {
+		view.ID = fmt.Sprint(len(s.views) + 1)
+		view.Owned = true
+		s.views = append(s.views, view)
+	}
+	return nil
+}
+
+func (s *memoryListingViewStore) DeleteView(model string, id string, ctx *web.EventContext) error {
+	return nil
+}
+
+func TestSaveListingView(t *testing.T) {
+	store := &memoryListingViewStore{}
+	b := New()
+	lb := b.Model(&listingViewItem{}).Listing().SavedViews(store)
+
+	form := url.Values{ParamListingViewName: []string{" Mine "}}
+	r := httptest.NewRequest("POST", "/listing-view-items?__execute_event__=presets_SaveListingView&f_name=a&order_by=Name_ASC&page=3&per_page=20", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx := &web.EventContext{R: r, W: httptest.NewRecorder()}
+
+	er, err := lb.saveListingView(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.views) != 1 {
+		t.Fatalf("views = %v", store.views)
+	}
+	view := store.views[0]
+	if view.Name != "Mine" || view.Query != "f_name=a&order_by=Name_ASC&per_page=20" {
+		t.Errorf("view = %+v", view)
+	}
+	if er.PushState == nil {
+		t.Fatalf("push state is missing")
+	}
+
+	tabs := lb.listingViewTabs(ctx, false)
+	if len(tabs) != 1 || tabs[0].ID != "view_1" || tabs[0].Query.Get("f_name") != "a" {
+		t.Errorf("tabs = %+v", tabs)
+	}
+	if tabs := lb.listingViewTabs(ctx, true); len(tabs) != 0 {
+		t.Errorf("tabs in dialog = %+v", tabs)
+	}
+}
@@ -1,7 +1,9 @@
 package presets
 
 import (
+	"fmt"
 	"strings"
+	"time"
 )
 
 type Messages struct {
@@ -55,6 +57,86 @@ type Messages struct {
 	Language                                   string
 	Colon                                      string
 	NotFoundPageNotice                         string
+	Export                                     string
+	Import                                     string
+	ImportFile                                 string
+	ImportDryRun                               string
+	ImportColumn                               string
+	ImportField                                string
+	ImportSkipColumn                           string
+	ImportRow                                  string
+	ImportErrors                               string
+	ImportFileRequired                         string
+	ImportDryRunResultTemplate                 string
+	ImportSuccessfullyTemplate                 string
+	SaveListingView                            string
+	SaveListingViewAsNew                       string
+	ListingViewName                            string
+	ListingViewShared                          string
+	ListingViewNameRequired                    string
+	ListingViewSaved                           string
+	ListingViewDeleted                         string
+	EditConflictTitle                          string
+	EditConflictNotice                         string
+	EditConflictField                          string
+	EditConflictMine                           string
+	EditConflictCurrent                        string
+	EditConflictKeep                           string
+	EditConflictDiscard                        string
+	Trash                                      string
+	TrashBack                                  string
+	Restore                                    string
+	DeletePermanently                          string
+	DeletePermanentlyConfirmationTextTemplate  string
+	TrashNoRecordsSelected                     string
+	TrashRestoredTemplate                      string
+	TrashDeletedTemplate                       string
+	GlobalSearch                               string
+	GlobalSearchNoResults                      string
+	Yes                                        string
+	No                                         string
+	ViewAll                                    string
+	ValidationRequiredTemplate                 string
+	ValidationMinTemplate                      string
+	ValidationMaxTemplate                      string
+	ValidationMinLengthTemplate                string
+	ValidationMaxLengthTemplate                string
+	ValidationMinItemsTemplate                 string
+	ValidationMaxItemsTemplate                 string
+	ValidationLenTemplate                      string
+	ValidationEmailTemplate                    string
+	ValidationURLTemplate                      string
+	ValidationOneOfTemplate                    string
+	BulkEdit                                   string
+	BulkEditFields                             string
+	BulkEditFieldsRequired                     string
+	BulkEditResultTemplate                     string
+	Duplicate                                  string
+	SuccessfullyDuplicated                     string
+	EditLeaseHeldTemplate                      string
+	EditLeaseExpired                           string
+	EditLeaseTakeOver                          string
+	EditLeaseRelease                           string
+	EditLeaseReleased                          string
+	EditLeaseReload                            string
+	AggregateSum                               string
+	AggregateAvg                               string
+	AggregateMin                               string
+	AggregateMax                               string
+	AggregateCount                             string
+	ListingLayoutTable                         string
+	ListingLayoutKanban                        string
+	ListingLayoutCalendar                      string
+	CalendarToday                              string
+	CalendarMonth                              string
+	CalendarWeek                               string
+	CalendarMonthTitleTemplate                 string
+	CalendarMonths                             string
+	CalendarWeekdays                           string
+	ListingLayoutTree                          string
+	TreeCycleNotice                            string
+	WizardBack                                 string
+	WizardNext                                 string
 }
 
 func (msgr *Messages) DeleteConfirmationText(id string) string {
@@ -85,6 +167,58 @@ func (msgr *Messages) BulkActionSelectedIdsProcessNotice(ids string) string {
 		Replace(msgr.BulkActionSelectedIdsProcessNoticeTemplate)
 }
 
+func (msgr *Messages) ImportDryRunResult(total int, invalid int) string {
+	return strings.NewReplacer("{total}", fmt.Sprint(total), "{invalid}", fmt.Sprint(invalid)).
+		Replace(msgr.ImportDryRunResultTemplate)
+}
+
+func (msgr *Messages) ImportSuccessfully(count int) string {
+	return strings.NewReplacer("{count}", fmt.Sprint(count)).
+		Replace(msgr.ImportSuccessfullyTemplate)
+}
+
+func (msgr *Messages) DeletePermanentlyConfirmationText(count int) string {
+	return strings.NewReplacer("{count}", fmt.Sprint(count)).
+		Replace(msgr.DeletePermanentlyConfirmationTextTemplate)
+}
+
+func (msgr *Messages) TrashRestored(count int) string {
+	return strings.NewReplacer("{count}", fmt.Sprint(count)).
+		Replace(msgr.TrashRestoredTemplate)
+}
+
+func (msgr *Messages) TrashDeleted(count int) string {
+	return strings.NewReplacer("{count}", fmt.Sprint(count)).
+		Replace(msgr.TrashDeletedTemplate)
+}
+
+func (msgr *Messages) BulkEditResult(updated string, failed string) string {
+	return strings.NewReplacer("{updated}", updated, "{failed}", failed).
+		Replace(msgr.BulkEditResultTemplate)
+}
+
+// CalendarMonthTitle returns the title of the month of t, like "January 2006".
+func (msgr *Messages) CalendarMonthTitle(t time.Time) string {
+	return strings.NewReplacer("{month}", strings.Split(msgr.CalendarMonths, ",")[t.Month()-1], "{year}", fmt.Sprint(t.Year())).
+		Replace(msgr.CalendarMonthTitleTemplate)
+}
+
+// CalendarWeekday returns the short name of the weekday, like "Sun".
+func (msgr *Messages) CalendarWeekday(d time.Weekday) string {
+	return strings.Split(msgr.CalendarWeekdays, ",")[d]
+}
+
+func (msgr *Messages) EditLeaseHeld(name string) string {
+	return strings.NewReplacer("{name}", name).
+		Replace(msgr.EditLeaseHeldTemplate)
+}
+
+// ValidationMessage returns the message of the validation template, like ValidationMaxLengthTemplate.
+func (msgr *Messages) ValidationMessage(template string, field string, param string) string {
+	return strings.NewReplacer("{field}", field, "{param}", param).
+		Replace(template)
+}
+
 func (msgr *Messages) FilterBy(filter string) string {
 	return strings.NewReplacer("{filter}", filter).
 		Replace(msgr.FilterByTemplate)
@@ -141,6 +275,86 @@ var Messages_en_US = &Messages{
 	Language:                                   "Language",
 	Colon:                                      ":",
 	NotFoundPageNotice:                         "Sorry, the requested page cannot be found. Please check the URL.",
+	Export:                                     "Export",
+	Import:                                     "Import",
+	ImportFile:                                 "CSV File",
+	ImportDryRun:                               "Dry Run",
+	ImportColumn:                               "Column",
+	ImportField:                                "Field",
+	ImportSkipColumn:                           "Do not import",
+	ImportRow:                                  "Row",
+	ImportErrors:                               "Errors",
+	ImportFileRequired:                         "Please select a CSV file",
+	ImportDryRunResultTemplate:                 "{total} rows, {invalid} with errors",
+	ImportSuccessfullyTemplate:                 "Successfully imported {count} records",
+	SaveListingView:                            "Save View",
+	SaveListingViewAsNew:                       "Save as New",
+	ListingViewName:                            "View Name",
+	ListingViewShared:                          "Share with everyone",
+	ListingViewNameRequired:                    "Please enter the view name",
+	ListingViewSaved:                           "View saved",
+	ListingViewDeleted:                         "View deleted",
+	EditConflictTitle:                          "Edit Conflict",
+	EditConflictNotice:                         "This record has been changed by someone else since you opened it",
+	EditConflictField:                          "Field",
+	EditConflictMine:                           "Your Value",
+	EditConflictCurrent:                        "Current Value",
+	EditConflictKeep:                           "Keep My Changes",
+	EditConflictDiscard:                        "Discard My Changes",
+	Trash:                                      "Trash",
+	TrashBack:                                  "Back to List",
+	Restore:                                    "Restore",
+	DeletePermanently:                          "Delete Permanently",
+	DeletePermanentlyConfirmationTextTemplate: "Are you sure you want to permanently delete {count} records? This cannot be undone.",
+	TrashNoRecordsSelected:                    "Please select the records first",
+	TrashRestoredTemplate:                     "{count} records are restored",
+	TrashDeletedTemplate:                      "{count} records are deleted permanently",
+	GlobalSearch:                              "Search Everything",
+	GlobalSearchNoResults:                     "No results found",
+	Yes:                                       "Yes",
+	No:                                        "No",
+	ViewAll:                                   "View All",
+	ValidationRequiredTemplate:                "{field} is required",
+	ValidationMinTemplate:                     "{field} must be at least {param}",
+	ValidationMaxTemplate:                     "{field} must be at most {param}",
+	ValidationMinLengthTemplate:               "{field} must be at least {param} characters",
+	ValidationMaxLengthTemplate:               "{field} must be at most {param} characters",
+	ValidationMinItemsTemplate:                "{field} must have at least {param} items",
+	ValidationMaxItemsTemplate:                "{field} must have at most {param} items",
+	ValidationLenTemplate:                     "{field} must be {param} characters",
+	ValidationEmailTemplate:                   "{field} must be a valid email address",
+	ValidationURLTemplate:                     "{field} must be a valid URL",
+	ValidationOneOfTemplate:                   "{field} must be one of {param}",
+	BulkEdit:                                  "Bulk Edit",
+	BulkEditFields:                            "Fields",
+	BulkEditFieldsRequired:                    "Please select the fields to edit",
+	BulkEditResultTemplate:                    "Updated: {updated}. Failed: {failed}",
+	Duplicate:                                 "Duplicate",
+	SuccessfullyDuplicated:                    "Successfully Duplicated",
+	EditLeaseHeldTemplate:                     "{name} is currently editing this record, the form is read-only",
+	EditLeaseExpired:                          "The record is not being edited by anyone now",
+	EditLeaseTakeOver:                         "Take Over",
+	EditLeaseRelease:                          "Release",
+	EditLeaseReleased:                         "Successfully Released",
+	EditLeaseReload:                           "Edit",
+	AggregateSum:                              "Sum",
+	AggregateAvg:                              "Average",
+	AggregateMin:                              "Min",
+	AggregateMax:                              "Max",
+	AggregateCount:                            "Count",
+	ListingLayoutTable:                        "Table",
+	ListingLayoutKanban:                       "Kanban",
+	ListingLayoutCalendar:                     "Calendar",
+	CalendarToday:                             "Today",
+	CalendarMonth:                             "Month",
+	CalendarWeek:                              "Week",
+	CalendarMonthTitleTemplate:                "{month} {year}",
+	CalendarMonths:                            "January,February,March,April,May,June,July,August,September,October,November,December",
+	CalendarWeekdays:                          "Sun,Mon,Tue,Wed,Thu,Fri,Sat",
+	ListingLayoutTree:                         "Tree",
+	TreeCycleNotice:                           "The record can not be moved under itself or its descendants",
+	WizardBack:                                "Back",
+	WizardNext:                                "Next",
 }
 
 var Messages_zh_CN = &Messages{
@@ -194,6 +408,86 @@ var Messages_zh_CN = &Messages{
 	Language:                                   "语言",
 	Colon:                                      "：",
 	NotFoundPageNotice:                         "很抱歉，所请求的页面不存在，请检查URL。",
+	Export:                                     "导出",
+	Import:                                     "导入",
+	ImportFile:                                 "CSV 文件",
+	ImportDryRun:                               "试运行",
+	ImportColumn:                               "列",
+	ImportField:                                "字段",
+	ImportSkipColumn:                           "不导入",
+	ImportRow:                                  "行",
+	ImportErrors:                               "错误",
+	ImportFileRequired:                         "请选择 CSV 文件",
+	ImportDryRunResultTemplate:                 "共 {total} 行，{invalid} 行有错误",
+	ImportSuccessfullyTemplate:                 "成功导入了 {count} 条记录",
+	SaveListingView:                            "保存视图",
+	SaveListingViewAsNew:                       "另存为新视图",
+	ListingViewName:                            "视图名称",
+	ListingViewShared:                          "共享给所有人",
+	ListingViewNameRequired:                    "请输入视图名称",
+	ListingViewSaved:                           "视图已保存",
+	ListingViewDeleted:                         "视图已删除",
+	EditConflictTitle:                          "编辑冲突",
+	EditConflictNotice:                         "在您打开此记录后，它已被其他人修改",
+	EditConflictField:                          "字段",
+	EditConflictMine:                           "您的值",
+	EditConflictCurrent:                        "当前值",
+	EditConflictKeep:                           "保留我的修改",
+	EditConflictDiscard:                        "放弃我的修改",
+	Trash:                                      "回收站",
+	TrashBack:                                  "返回列表",
+	Restore:                                    "恢复",
+	DeletePermanently:                          "永久删除",
+	DeletePermanentlyConfirmationTextTemplate: "你确定要永久删除这{count}条记录吗？此操作无法撤销。",
+	TrashNoRecordsSelected:                    "请先选择记录",
+	TrashRestoredTemplate:                     "{count}条记录已恢复",
+	TrashDeletedTemplate:                      "{count}条记录已永久删除",
+	GlobalSearch:                              "全局搜索",
+	GlobalSearchNoResults:                     "没有找到结果",
+	Yes:                                       "是",
+	No:                                        "否",
+	ViewAll:                                   "查看全部",
+	ValidationRequiredTemplate:                "{field}不能为空",
+	ValidationMinTemplate:                     "{field}不能小于{param}",
+	ValidationMaxTemplate:                     "{field}不能大于{param}",
+	ValidationMinLengthTemplate:               "{field}至少需要{param}个字符",
+	ValidationMaxLengthTemplate:               "{field}最多{param}个字符",
+	ValidationMinItemsTemplate:                "{field}至少需要{param}项",
+	ValidationMaxItemsTemplate:                "{field}最多{param}项",
+	ValidationLenTemplate:                     "{field}必须是{param}个字符",
+	ValidationEmailTemplate:                   "{field}必须是有效的邮箱地址",
+	ValidationURLTemplate:                     "{field}必须是有效的URL",
+	ValidationOneOfTemplate:                   "{field}必须是{param}之一",
+	BulkEdit:                                  "批量编辑",
+	BulkEditFields:                            "字段",
+	BulkEditFieldsRequired:                    "请选择要编辑的字段",
+	BulkEditResultTemplate:                    "已更新：{updated}。失败：{failed}",
+	Duplicate:                                 "复制",
+	SuccessfullyDuplicated:                    "复制成功",
+	EditLeaseHeldTemplate:                     "{name} 正在编辑此记录，表单为只读",
+	EditLeaseExpired:                          "当前没有人在编辑此记录",
+	EditLeaseTakeOver:                         "接管",
+	EditLeaseRelease:                          "释放",
+	EditLeaseReleased:                         "释放成功",
+	EditLeaseReload:                           "编辑",
+	AggregateSum:                              "合计",
+	AggregateAvg:                              "平均",
+	AggregateMin:                              "最小",
+	AggregateMax:                              "最大",
+	AggregateCount:                            "计数",
+	ListingLayoutTable:                        "表格",
+	ListingLayoutKanban:                       "看板",
+	ListingLayoutCalendar:                     "日历",
+	CalendarToday:                             "今天",
+	CalendarMonth:                             "月",
+	CalendarWeek:                              "周",
+	CalendarMonthTitleTemplate:                "{year}年{month}",
+	CalendarMonths:                            "1月,2月,3月,4月,5月,6月,7月,8月,9月,10月,11月,12月",
+	CalendarWeekdays:                          "周日,周一,周二,周三,周四,周五,周六",
+	ListingLayoutTree:                         "树",
+	TreeCycleNotice:                           "不能将记录移动到其自身或其子记录之下",
+	WizardBack:                                "上一步",
+	WizardNext:                                "下一步",
 }
 
 var Messages_ja_JP = &Messages{
@@ -246,4 +540,84 @@ var Messages_ja_JP = &Messages{
 	Language:                                   "言語",
 	Colon:                                      ":",
 	NotFoundPageNotice:                         "申し訳ありませんが、リクエストされたページは見つかりませんでした。URLを確認してください。",
+	Export:                                     "エクスポート",
+	Import:                                     "インポート",
+	ImportFile:                                 "CSVファイル",
+	ImportDryRun:                               "ドライラン",
+	ImportColumn:                               "列",
+	ImportField:                                "フィールド",
+	ImportSkipColumn:                           "インポートしない",
+	ImportRow:                                  "行",
+	ImportErrors:                               "エラー",
+	ImportFileRequired:                         "CSVファイルを選択してください",
+	ImportDryRunResultTemplate:                 "{total} 行中 {invalid} 行にエラーがあります",
+	ImportSuccessfullyTemplate:                 "{count} 件のレコードをインポートしました",
+	SaveListingView:                            "ビューを保存",
+	SaveListingViewAsNew:                       "新規ビューとして保存",
+	ListingViewName:                            "ビュー名",
+	ListingViewShared:                          "全員と共有",
+	ListingViewNameRequired:                    "ビュー名を入力してください",
+	ListingViewSaved:                           "ビューを保存しました",
+	ListingViewDeleted:                         "ビューを削除しました",
+	EditConflictTitle:                          "編集の競合",
+	EditConflictNotice:                         "このレコードは開いた後に他のユーザーによって変更されました",
+	EditConflictField:                          "フィールド",
+	EditConflictMine:                           "あなたの値",
+	EditConflictCurrent:                        "現在の値",
+	EditConflictKeep:                           "変更を保持",
+	EditConflictDiscard:                        "変更を破棄",
+	Trash:                                      "ゴミ箱",
+	TrashBack:                                  "リストに戻る",
+	Restore:                                    "復元",
+	DeletePermanently:                          "完全に削除",
+	DeletePermanentlyConfirmationTextTemplate: "{count} 件のレコードを完全に削除してもよろしいですか？この操作は元に戻せません。",
+	TrashNoRecordsSelected:                    "レコードを選択してください",
+	TrashRestoredTemplate:                     "{count} 件のレコードを復元しました",
+	TrashDeletedTemplate:                      "{count} 件のレコードを完全に削除しました",
+	GlobalSearch:                              "全体検索",
+	GlobalSearchNoResults:                     "結果が見つかりません",
+	Yes:                                       "はい",
+	No:                                        "いいえ",
+	ViewAll:                                   "すべて表示",
+	ValidationRequiredTemplate:                "{field}は必須です",
+	ValidationMinTemplate:                     "{field}は{param}以上にしてください",
+	ValidationMaxTemplate:                     "{field}は{param}以下にしてください",
+	ValidationMinLengthTemplate:               "{field}は{param}文字以上にしてください",
+	ValidationMaxLengthTemplate:               "{field}は{param}文字以下にしてください",
+	ValidationMinItemsTemplate:                "{field}は{param}件以上にしてください",
+	ValidationMaxItemsTemplate:                "{field}は{param}件以下にしてください",
+	ValidationLenTemplate:                     "{field}は{param}文字にしてください",
+	ValidationEmailTemplate:                   "{field}は有効なメールアドレスにしてください",
+	ValidationURLTemplate:                     "{field}は有効なURLにしてください",
+	ValidationOneOfTemplate:                   "{field}は{param}のいずれかにしてください",
+	BulkEdit:                                  "一括編集",
+	BulkEditFields:                            "フィールド",
+	BulkEditFieldsRequired:                    "編集するフィールドを選択してください",
+	BulkEditResultTemplate:                    "更新済み：{updated}。失敗：{failed}",
+	Duplicate:                                 "複製",
+	SuccessfullyDuplicated:                    "複製しました",
+	EditLeaseHeldTemplate:                     "{name} がこのレコードを編集中のため、フォームは読み取り専用です",
+	EditLeaseExpired:                          "現在このレコードを編集しているユーザーはいません",
+	EditLeaseTakeOver:                         "引き継ぐ",
+	EditLeaseRelease:                          "解除",
+	EditLeaseReleased:                         "解除しました",
+	EditLeaseReload:                           "編集",
+	AggregateSum:                              "合計",
+	AggregateAvg:                              "平均",
+	AggregateMin:                              "最小",
+	AggregateMax:                              "最大",
+	AggregateCount:                            "件数",
+	ListingLayoutTable:                        "テーブル",
+	ListingLayoutKanban:                       "カンバン",
+	ListingLayoutCalendar:                     "カレンダー",
+	CalendarToday:                             "今日",
+	CalendarMonth:                             "月",
+	CalendarWeek:                              "週",
+	CalendarMonthTitleTemplate:                "{year}年{month}",
+	CalendarMonths:                            "1月,2月,3月,4月,5月,6月,7月,8月,9月,10月,11月,12月",
+	CalendarWeekdays:                          "日,月,火,水,木,金,土",
+	ListingLayoutTree:                         "ツリー",
+	TreeCycleNotice:                           "レコードをそれ自身またはその子孫の下に移動することはできません",
+	WizardBack:                                "戻る",
+	WizardNext:                                "次へ",
 }
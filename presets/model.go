@@ -31,6 +31,9 @@ type ModelBuilder struct {
 	detailing           *DetailingBuilder
 	editing             *EditingBuilder
 	creating            *EditingBuilder
+	importer            *ImportBuilder
+	duplicate           *DuplicateBuilder
+	hooks               []*hook
 	writeFields         *FieldsBuilder
 	hasDetailing        bool
 	rightDrawerWidth    string
@@ -86,6 +89,7 @@ func (mb *ModelBuilder) registerDefaultEventFuncs() {
 	mb.RegisterEventFunc(actions.ReloadList, mb.listing.reloadList)
 	mb.RegisterEventFunc(actions.OpenListingDialog, mb.listing.openListingDialog)
 	mb.RegisterEventFunc(actions.UpdateListingDialog, mb.listing.updateListingDialog)
+	mb.RegisterEventFunc(actions.UpdateListingCell, mb.listing.updateListingCell)
 
 	// list editor
 	mb.RegisterEventFunc(actions.AddRowEvent, addListItemRow(mb))
@@ -108,7 +112,7 @@ func (mb *ModelBuilder) newListing() (lb *ListingBuilder) {
 		dialogWidth:   "1200px",
 	}
 	if mb.p.dataOperator != nil {
-		mb.listing.SearchFunc(mb.p.dataOperator.Search)
+		mb.listing.Searcher = mb.p.dataOperator.Search
 	}
 
 	rmb := mb.listing.RowMenu()
@@ -156,6 +160,14 @@ func (b ModelInfo) DetailingHref(id string) string {
 	return fmt.Sprintf("%s/%s/%s", b.mb.p.prefix, b.mb.uriName, id)
 }
 
+func (b ModelInfo) ExportHref() string {
+	return fmt.Sprintf("%s/%s/export", b.mb.p.prefix, b.mb.uriName)
+}
+
+func (b ModelInfo) RESTAPIHref() string {
+	return fmt.Sprintf("%s%s/%s", b.mb.p.prefix, b.mb.p.restAPIPrefix, b.mb.uriName)
+}
+
 func (b ModelInfo) HasDetailing() bool {
 	return b.mb.hasDetailing
 }
@@ -0,0 +1,411 @@
+package presets
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/iancoleman/strcase"
+	"github.com/qor5/ui/vuetifyx"
+	"github.com/qor5/web"
+)
+
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*openAPISchema `json:"schemas"`
+}
+
+type openAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []*openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIBody                `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+func openAPIRef(name string) *openAPISchema {
+	return &openAPISchema{Ref: "#/components/schemas/" + name}
+}
+
+func openAPIJSONBody(schema *openAPISchema) map[string]*openAPIMediaType {
+	return map[string]*openAPIMediaType{"application/json": {Schema: schema}}
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// openAPISchemas builds the schemas of the Go types like how encoding/json encodes them,
+// named struct types are added to the components and referenced.
+type openAPISchemas map[string]*openAPISchema
+
+func (s openAPISchemas) typeSchema(t reflect.Type) (r *openAPISchema) {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+	defer func() {
+		if nullable && r.Ref == "" {
+			r.Nullable = true
+		}
+	}()
+
+	switch {
+	case t == timeType:
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	case t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType):
+		// the encoded value is unknown
+		return &openAPISchema{}
+	case t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType):
+		return &openAPISchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &openAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &openAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: s.typeSchema(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: s.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t, nil)
+		}
+		if _, ok := s[t.Name()]; !ok {
+			// add before building the properties for the types that reference themselves
+			s[t.Name()] = &openAPISchema{}
+			*s[t.Name()] = *s.structSchema(t, nil)
+		}
+		return openAPIRef(t.Name())
+	}
+	return &openAPISchema{}
+}
+
+// structSchema returns the object schema of the struct, only the fields in only are included if it is not nil.
+func (s openAPISchemas) structSchema(t reflect.Type, only map[string]bool) (r *openAPISchema) {
+	r = &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := jsonFieldName(t, sf.Name)
+		if name == "" {
+			continue
+		}
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		// the fields of embedded structs are promoted like encoding/json
+		if sf.Anonymous && name == sf.Name && ft.Kind() == reflect.Struct && ft != timeType {
+			for k, v := range s.structSchema(ft, only).Properties {
+				r.Properties[k] = v
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if only != nil && !only[sf.Name] {
+			continue
+		}
+		r.Properties[name] = s.typeSchema(sf.Type)
+	}
+	return
+}
+
+func (b *Builder) openAPIDocument(ctx *web.EventContext) (r *openAPIDocument) {
+	r = &openAPIDocument{
+		OpenAPI:    "3.0.3",
+		Info:       openAPIInfo{Title: b.brandTitle, Version: "1.0.0"},
+		Paths:      make(map[string]map[string]*openAPIOperation),
+		Components: openAPIComponents{Schemas: make(map[string]*openAPISchema)},
+	}
+	schemas := openAPISchemas(r.Components.Schemas)
+	schemas.typeSchema(reflect.TypeOf(RESTAPIErrorResponse{}))
+
+	for _, m := range b.models {
+		t := m.modelType.Elem()
+		name := m.openAPIName()
+		schemas[name] = schemas.structSchema(t, nil)
+		schemas[name+"Input"] = schemas.structSchema(t, fieldNameSet(m.editing.fields))
+		createInput := name + "Input"
+		if m.creating != nil {
+			createInput = name + "CreatingInput"
+			schemas[createInput] = schemas.structSchema(t, fieldNameSet(m.creating.fields))
+		}
+
+		if b.restAPIPrefix == "" {
+			continue
+		}
+		m.openAPIPaths(r, createInput, ctx)
+	}
+	return
+}
+
+func fieldNameSet(fields []*FieldBuilder) map[string]bool {
+	r := make(map[string]bool)
+	for _, f := range fields {
+		r[f.name] = true
+	}
+	return r
+}
+
+// openAPIName names the schemas and the operations of the model by its uri name,
+// so that the models of the same type don't have the same operationIds.
+func (mb *ModelBuilder) openAPIName() string {
+	return strcase.ToCamel(mb.uriName)
+}
+
+// openAPIPaths adds the operations of the REST API that the current user is allowed to do.
+func (mb *ModelBuilder) openAPIPaths(doc *openAPIDocument, createInput string, ctx *web.EventContext) {
+	name := mb.openAPIName()
+	listPath := mb.Info().RESTAPIHref()
+	objPath := listPath + "/{id}"
+	errorResponses := func(rs map[string]*openAPIResponse, notFound bool) map[string]*openAPIResponse {
+		rs["403"] = &openAPIResponse{Description: "Permission denied", Content: openAPIJSONBody(openAPIRef("RESTAPIErrorResponse"))}
+		if notFound {
+			rs["404"] = &openAPIResponse{Description: "Record not found", Content: openAPIJSONBody(openAPIRef("RESTAPIErrorResponse"))}
+		}
+		return rs
+	}
+	idParam := &openAPIParameter{Name: "id", In: "path", Required: true, Schema: &openAPISchema{Type: "string"}}
+	allowed := func(v string) bool {
+		return mb.Info().Verifier().Do(v).WithReq(ctx.R).IsAllowed() == nil
+	}
+
+	listOps := make(map[string]*openAPIOperation)
+	objOps := make(map[string]*openAPIOperation)
+	if allowed(PermList) {
+		listSchema := &openAPISchema{
+			Type: "object",
+			Properties: map[string]*openAPISchema{
+				"data":     {Type: "array", Items: openAPIRef(name)},
+				"total":    {Type: "integer", Format: "int64"},
+				"page":     {Type: "integer", Format: "int64"},
+				"per_page": {Type: "integer", Format: "int64"},
+			},
+		}
+		if mb.listing.keysetPagination {
+			listSchema.Properties["prev_cursor"] = &openAPISchema{Type: "string"}
+			listSchema.Properties["next_cursor"] = &openAPISchema{Type: "string"}
+		}
+		listOps["get"] = &openAPIOperation{
+			OperationID: "list" + name,
+			Tags:        []string{mb.label},
+			Parameters:  mb.listing.openAPIParameters(ctx),
+			Responses: errorResponses(map[string]*openAPIResponse{
+				"200": {
+					Description: "OK",
+					Content:     openAPIJSONBody(listSchema),
+				},
+			}, false),
+		}
+	}
+	if allowed(PermCreate) {
+		listOps["post"] = &openAPIOperation{
+			OperationID: "create" + name,
+			Tags:        []string{mb.label},
+			RequestBody: &openAPIBody{Required: true, Content: openAPIJSONBody(openAPIRef(createInput))},
+			Responses: errorResponses(map[string]*openAPIResponse{
+				"201": {Description: "Created", Content: openAPIJSONBody(openAPIRef(name))},
+				"422": {Description: "Validation failed", Content: openAPIJSONBody(openAPIRef("RESTAPIErrorResponse"))},
+			}, false),
+		}
+	}
+	if allowed(PermGet) {
+		objOps["get"] = &openAPIOperation{
+			OperationID: "get" + name,
+			Tags:        []string{mb.label},
+			Parameters:  []*openAPIParameter{idParam},
+			Responses: errorResponses(map[string]*openAPIResponse{
+				"200": {Description: "OK", Content: openAPIJSONBody(openAPIRef(name))},
+			}, true),
+		}
+	}
+	if allowed(PermUpdate) {
+		for method, op := range map[string]string{"put": "update", "patch": "patch"} {
+			objOps[method] = &openAPIOperation{
+				OperationID: op + name,
+				Tags:        []string{mb.label},
+				Parameters:  []*openAPIParameter{idParam},
+				RequestBody: &openAPIBody{Required: true, Content: openAPIJSONBody(openAPIRef(name + "Input"))},
+				Responses: errorResponses(map[string]*openAPIResponse{
+					"200": {Description: "OK", Content: openAPIJSONBody(openAPIRef(name))},
+					"422": {Description: "Validation failed", Content: openAPIJSONBody(openAPIRef("RESTAPIErrorResponse"))},
+				}, true),
+			}
+		}
+	}
+	if allowed(PermDelete) {
+		objOps["delete"] = &openAPIOperation{
+			OperationID: "delete" + name,
+			Tags:        []string{mb.label},
+			Parameters:  []*openAPIParameter{idParam},
+			Responses: errorResponses(map[string]*openAPIResponse{
+				"204": {Description: "Deleted"},
+			}, true),
+		}
+	}
+
+	if len(listOps) > 0 {
+		doc.Paths[listPath] = listOps
+	}
+	if len(objOps) > 0 {
+		doc.Paths[objPath] = objOps
+	}
+}
+
+// openAPIParameters returns the query parameters that are mapped to the SearchParams of the listing.
+func (b *ListingBuilder) openAPIParameters(ctx *web.EventContext) (r []*openAPIParameter) {
+	if len(b.searchColumns) > 0 {
+		r = append(r, &openAPIParameter{Name: "keyword", In: "query", Schema: &openAPISchema{Type: "string"}})
+	}
+	if !b.disablePagination {
+		if b.keysetPagination {
+			r = append(r,
+				&openAPIParameter{Name: ParamKeysetAfter, In: "query", Description: "next_cursor of the previous response", Schema: &openAPISchema{Type: "string"}},
+				&openAPIParameter{Name: ParamKeysetBefore, In: "query", Description: "prev_cursor of the previous response", Schema: &openAPISchema{Type: "string"}},
+			)
+		} else {
+			r = append(r, &openAPIParameter{Name: "page", In: "query", Schema: &openAPISchema{Type: "integer", Format: "int64"}})
+		}
+		r = append(r, &openAPIParameter{Name: "per_page", In: "query", Description: "at most 1000", Schema: &openAPISchema{Type: "integer", Format: "int64"}})
+	}
+	if len(b.orderableFields) > 0 {
+		var names []string
+		for _, f := range b.orderableFields {
+			names = append(names, f.FieldName)
+		}
+		r = append(r, &openAPIParameter{
+			Name:        "order_by",
+			In:          "query",
+			Description: fmt.Sprintf("comma separated <field>_ASC or <field>_DESC, the field is one of %s", strings.Join(names, ", ")),
+			Schema:      &openAPISchema{Type: "string"},
+		})
+	}
+
+	if b.filterDataFunc == nil {
+		return
+	}
+	for _, it := range b.filterDataFunc(ctx) {
+		r = append(r, openAPIFilterParameters(it)...)
+	}
+	return
+}
+
+// openAPIFilterParameters returns the query parameters of the filter item like vuetifyx.FilterData.SetByQueryString parses them.
+func openAPIFilterParameters(it *vuetifyx.FilterItem) (r []*openAPIParameter) {
+	param := func(mod string, schema *openAPISchema) *openAPIParameter {
+		name := it.Key
+		if mod != "" {
+			name = it.Key + "." + mod
+		}
+		return &openAPIParameter{Name: name, In: "query", Description: it.Label, Schema: schema}
+	}
+	var options []string
+	for _, o := range it.Options {
+		options = append(options, o.Value)
+	}
+
+	switch it.ItemType {
+	case vuetifyx.ItemTypeDatetimeRange:
+		for _, mod := range []string{"gte", "lt"} {
+			r = append(r, param(mod, &openAPISchema{Type: "string", Description: "2006-01-02 15:04"}))
+		}
+	case vuetifyx.ItemTypeDateRange:
+		for _, mod := range []string{"gte", "lte"} {
+			r = append(r, param(mod, &openAPISchema{Type: "string", Format: "date"}))
+		}
+	case vuetifyx.ItemTypeDate:
+		r = append(r, param("", &openAPISchema{Type: "string", Format: "date"}))
+	case vuetifyx.ItemTypeNumber:
+		for _, mod := range []string{"", "gte", "lte", "gt", "lt"} {
+			r = append(r, param(mod, &openAPISchema{Type: "number"}))
+		}
+	case vuetifyx.ItemTypeString:
+		for _, mod := range []string{"", "ilike"} {
+			r = append(r, param(mod, &openAPISchema{Type: "string"}))
+		}
+	case vuetifyx.ItemTypeSelect:
+		r = append(r, param("", &openAPISchema{Type: "string", Enum: options}))
+	case vuetifyx.ItemTypeMultipleSelect:
+		for _, mod := range []string{"in", "notIn"} {
+			r = append(r, param(mod, &openAPISchema{Type: "string", Description: "comma separated values of " + strings.Join(options, ", ")}))
+		}
+	case vuetifyx.ItemTypeLinkageSelect:
+		r = append(r, param("", &openAPISchema{Type: "string", Description: "comma separated values of the levels"}))
+	}
+	return
+}
+
+func (b *Builder) serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(b.openAPIDocument(&web.EventContext{R: r, W: w}))
+}
@@ -0,0 +1,122 @@
+package presets
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/qor5/ui/vuetifyx"
+	"github.com/qor5/web"
+)
+
+type openAPIBase struct {
+	ID        uint
+	CreatedAt time.Time
+}
+
+type openAPIItem struct {
+	openAPIBase
+	Name     string `json:"name"`
+	Price    *float64
+	Tags     []string
+	Parent   *openAPIItem
+	Internal string `json:"-"`
+}
+
+func TestOpenAPI(t *testing.T) {
+	b := New().URIPrefix("/admin").RESTAPIPrefix("/api").OpenAPIPath("/openapi.json")
+	mb := b.Model(&openAPIItem{})
+	mb.Listing().
+		SearchColumns("name").
+		OrderableFields([]*OrderableField{{FieldName: "Name", DBColumn: "name"}}).
+		FilterDataFunc(func(ctx *web.EventContext) vuetifyx.FilterData {
+			return vuetifyx.FilterData{
+				{Key: "created", ItemType: vuetifyx.ItemTypeDateRange, SQLCondition: "created_at %s ?"},
+			}
+		})
+	mb.Editing("Name", "Price")
+
+	r := httptest.NewRequest("GET", "/admin/openapi.json", nil)
+	w := httptest.NewRecorder()
+	b.ServeHTTP(w, r)
+	if w.Code != 200 {
+		t.Fatalf("status = %d", w.Code)
+	}
+
+	var doc openAPIDocument
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	item := doc.Components.Schemas["OpenApiItems"]
+	if item == nil {
+		t.Fatalf("schemas = %v", doc.Components.Schemas)
+	}
+	for name, typ := range map[string]string{"ID": "integer", "CreatedAt": "string", "name": "string", "Price": "number", "Tags": "array"} {
+		if p := item.Properties[name]; p == nil || p.Type != typ {
+			t.Errorf("property %s = %+v, excepted type %s", name, p, typ)
+		}
+	}
+	if p := item.Properties["Parent"]; p == nil || p.Ref != "#/components/schemas/openAPIItem" {
+		t.Errorf("property Parent = %+v", p)
+	}
+	if _, ok := item.Properties["Internal"]; ok {
+		t.Errorf("property Internal should be skipped")
+	}
+	if input := doc.Components.Schemas["OpenApiItemsInput"]; len(input.Properties) != 2 {
+		t.Errorf("input properties = %v", input.Properties)
+	}
+
+	list := doc.Paths["/admin/api/open-api-items"]["get"]
+	if list == nil {
+		t.Fatalf("paths = %v", doc.Paths)
+	}
+	var params []string
+	for _, p := range list.Parameters {
+		params = append(params, p.Name)
+	}
+	excepted := []string{"keyword", "page", "per_page", "order_by", "f_created.gte", "f_created.lte"}
+	if len(params) != len(excepted) {
+		t.Fatalf("parameters = %v, excepted %v", params, excepted)
+	}
+	for i := range excepted {
+		if params[i] != excepted[i] {
+			t.Errorf("parameters = %v, excepted %v", params, excepted)
+		}
+	}
+	for _, method := range []string{"get", "put", "patch", "delete"} {
+		if doc.Paths["/admin/api/open-api-items/{id}"][method] == nil {
+			t.Errorf("operation %s is missing", method)
+		}
+	}
+}
+
+func TestOpenAPIModelsOfSameType(t *testing.T) {
+	b := New().URIPrefix("/admin").RESTAPIPrefix("/api").OpenAPIPath("/openapi.json")
+	b.Model(&openAPIItem{}).Editing("Name")
+	b.Model(&openAPIItem{}).URIName("archived-items").Editing("Name", "Price")
+
+	w := httptest.NewRecorder()
+	b.ServeHTTP(w, httptest.NewRequest("GET", "/admin/openapi.json", nil))
+	var doc openAPIDocument
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := map[string]bool{}
+	for _, ops := range doc.Paths {
+		for _, op := range ops {
+			if ids[op.OperationID] {
+				t.Errorf("operationId %s is duplicated", op.OperationID)
+			}
+			ids[op.OperationID] = true
+		}
+	}
+	if !ids["getArchivedItems"] || !ids["listArchivedItems"] {
+		t.Errorf("operationIds = %v", ids)
+	}
+	if input := doc.Components.Schemas["ArchivedItemsInput"]; input == nil || len(input.Properties) != 2 {
+		t.Errorf("schemas = %v", doc.Components.Schemas)
+	}
+}
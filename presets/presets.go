@@ -5,6 +5,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"reflect"
 	"regexp"
 	"strings"
 
@@ -61,6 +62,9 @@ type Builder struct {
 	menuGroups                            MenuGroups
 	menuOrder                             []interface{}
 	wrapHandlers                          map[string]func(in http.Handler) (out http.Handler)
+	restAPIPrefix                         string
+	openAPIPath                           string
+	tenantResolver                        TenantResolverFunc
 }
 
 type AssetFunc func(ctx *web.EventContext)
@@ -107,6 +111,7 @@ func New() *Builder {
 	}
 
 	r.GetWebBuilder().RegisterEventFunc(OpenConfirmDialog, r.openConfirmDialog)
+	r.GetWebBuilder().RegisterEventFunc(actions.GlobalSearch, r.globalSearch)
 	r.layoutFunc = r.defaultLayout
 	r.detailLayoutFunc = r.defaultLayout
 	return r
@@ -136,6 +141,21 @@ func (b *Builder) URIPrefix(v string) (r *Builder) {
 	return b
 }
 
+// RESTAPIPrefix mounts a JSON REST API for every model under the prefix,
+// like "/api" for "/admin/api/products", it is disabled if the prefix is empty.
+// PUT replaces all the writable editing fields of the record, and PATCH only sets the fields in the request body.
+func (b *Builder) RESTAPIPrefix(v string) (r *Builder) {
+	b.restAPIPrefix = v
+	return b
+}
+
+// OpenAPIPath serves the OpenAPI 3 document of the models at the path under the presets prefix,
+// like "/openapi.json", it is disabled if the path is empty.
+func (b *Builder) OpenAPIPath(v string) (r *Builder) {
+	b.openAPIPath = v
+	return b
+}
+
 func (b *Builder) GetURIPrefix() string {
 	return b.prefix
 }
@@ -314,6 +334,17 @@ func (b *Builder) Model(v interface{}) (r *ModelBuilder) {
 	return r
 }
 
+// GetModelBuilder returns the registered model builder of the model type of v, or nil if it is not registered.
+func (b *Builder) GetModelBuilder(v interface{}) (r *ModelBuilder) {
+	t := indirectType(reflect.TypeOf(v))
+	for _, m := range b.models {
+		if indirectType(reflect.TypeOf(m.model)) == t {
+			return m
+		}
+	}
+	return nil
+}
+
 func (b *Builder) DataOperator(v DataOperator) (r *Builder) {
 	b.dataOperator = v
 	return b
@@ -923,6 +954,7 @@ func (b *Builder) defaultLayout(in web.PageFunc, cfg *LayoutConfig) (out web.Pag
 		}
 
 		showSearchBox := cfg == nil || !cfg.SearchBoxInvisible
+		showGlobalSearch := b.hasGlobalSearch()
 
 		msgr := i18n.MustGetModuleMessages(ctx.R, CoreI18nModuleKey, Messages_en_US).(*Messages)
 
@@ -955,7 +987,7 @@ func (b *Builder) defaultLayout(in web.PageFunc, cfg *LayoutConfig) (out web.Pag
 							HideDetails(true).
 							Value(ctx.R.URL.Query().Get("keyword")).
 							Attr("@keyup.enter", web.Plaid().
-								ClearMergeQuery("page").
+								ClearMergeQuery([]string{"page", ParamKeysetAfter, ParamKeysetBefore}).
 								Query("keyword", web.Var("[$event.target.value]")).
 								MergeQuery(true).
 								PushState(true).
@@ -967,6 +999,11 @@ func (b *Builder) defaultLayout(in web.PageFunc, cfg *LayoutConfig) (out web.Pag
 						// ).Method("GET"),
 					).AlignCenter(true).Attr("style", "max-width: 650px"),
 				),
+				h.If(showGlobalSearch,
+					VBtn("").Icon(true).
+						Children(VIcon("manage_search")).
+						Attr("@click", "vars.presetsGlobalSearch = true"),
+				),
 				h.If(showNotificationCenter,
 					notifier,
 				),
@@ -981,6 +1018,7 @@ func (b *Builder) defaultLayout(in web.PageFunc, cfg *LayoutConfig) (out web.Pag
 			web.Portal().Name(DeleteConfirmPortalName),
 			web.Portal().Name(DefaultConfirmDialogPortalName),
 			web.Portal().Name(ListingDialogPortalName),
+			h.If(showGlobalSearch, b.globalSearchDialog(ctx)),
 
 			VProgressLinear().
 				Attr(":active", "isFetching").
@@ -1205,6 +1243,17 @@ func (b *Builder) initMux() {
 		b.wrap(nil, b.layoutFunc(b.getHomePageFunc(), b.homePageLayoutConfig)),
 	)
 
+	if b.openAPIPath != "" {
+		openAPIPath := b.prefix + b.openAPIPath
+		mux.Handle(pat.Get(openAPIPath), b.wrapHandler(http.HandlerFunc(b.serveOpenAPI)))
+		log.Println("mounted url", openAPIPath)
+	}
+
+	for _, m := range b.models {
+		// the filters of the belongs-to associations need the associated models to be registered
+		m.listing.configureFilters()
+	}
+
 	for _, m := range b.models {
 		pluralUri := inflection.Plural(m.uriName)
 		info := m.Info()
@@ -1222,6 +1271,26 @@ func (b *Builder) initMux() {
 			b.wrap(m, b.layoutFunc(inPageFunc, m.layoutConfig)),
 		)
 		log.Println("mounted url", routePath)
+		if m.listing.exporter != nil {
+			// mount before the detailing page, which would match it as an id
+			routePath = info.ExportHref()
+			mux.Handle(
+				pat.Get(routePath),
+				b.wrapHandler(m.listing.exporter),
+			)
+			log.Println("mounted url", routePath)
+		}
+		if b.restAPIPrefix != "" {
+			api := &restAPIHandler{mb: m}
+			routePath = info.RESTAPIHref()
+			mux.Handle(pat.Get(routePath), b.wrapHandler(http.HandlerFunc(api.list)))
+			mux.Handle(pat.Post(routePath), b.wrapHandler(http.HandlerFunc(api.create)))
+			mux.Handle(pat.Get(routePath+"/:id"), b.wrapHandler(http.HandlerFunc(api.get)))
+			mux.Handle(pat.Put(routePath+"/:id"), b.wrapHandler(http.HandlerFunc(api.replace)))
+			mux.Handle(pat.Patch(routePath+"/:id"), b.wrapHandler(http.HandlerFunc(api.update)))
+			mux.Handle(pat.Delete(routePath+"/:id"), b.wrapHandler(http.HandlerFunc(api.delete)))
+			log.Println("mounted url", routePath)
+		}
 		if m.hasDetailing {
 			routePath = fmt.Sprintf("%s/%s/:id", b.prefix, pluralUri)
 			mux.Handle(
@@ -1261,8 +1330,12 @@ func (b *Builder) wrap(m *ModelBuilder, pf web.PageFunc) http.Handler {
 		p.MergeHub(&m.EventsHub)
 	}
 
+	return b.wrapHandler(p)
+}
+
+func (b *Builder) wrapHandler(in http.Handler) http.Handler {
 	handlers := b.I18n().EnsureLanguage(
-		p,
+		b.resolveTenant(in),
 	)
 	for _, wrapHandler := range b.wrapHandlers {
 		handlers = wrapHandler(handlers)
@@ -0,0 +1,401 @@
+package presets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/qor5/web"
+	"github.com/qor5/x/perm"
+	"github.com/sunfmin/reflectutils"
+	"goji.io/pat"
+)
+
+type RESTAPIListResponse struct {
+	Data interface{} `json:"data"`
+	// it is 0 in keyset pagination mode unless ListingBuilder.KeysetPaginationCount is enabled
+	Total   int   `json:"total"`
+	Page    int64 `json:"page"`
+	PerPage int64 `json:"per_page"`
+	// the cursors of the previous and next pages in keyset pagination mode, see ListingBuilder.KeysetPagination
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type RESTAPIErrorResponse struct {
+	Error        string              `json:"error"`
+	GlobalErrors []string            `json:"global_errors,omitempty"`
+	FieldErrors  map[string][]string `json:"field_errors,omitempty"`
+}
+
+// restAPIHandler serves the JSON REST API of a model with the same funcs
+// and permission checks as the listing and editing pages.
+type restAPIHandler struct {
+	mb *ModelBuilder
+}
+
+func (h *restAPIHandler) editingBuilder(id string) *EditingBuilder {
+	if h.mb.creating != nil && id == "" {
+		return h.mb.creating
+	}
+	return h.mb.editing
+}
+
+func (h *restAPIHandler) list(w http.ResponseWriter, r *http.Request) {
+	ctx := &web.EventContext{R: r, W: w}
+	lb := h.mb.listing
+	if h.mb.Info().Verifier().Do(PermList).WithReq(r).IsAllowed() != nil {
+		writeRESTAPIError(w, perm.PermissionDenied)
+		return
+	}
+	if lb.Searcher == nil {
+		writeRESTAPIError(w, errors.New("presets.New().DataOperator(...) required"))
+		return
+	}
+
+	var perPage int64
+	if !lb.disablePagination {
+		perPage, _ = strconv.ParseInt(r.URL.Query().Get("per_page"), 10, 64)
+		if perPage == 0 {
+			perPage = lb.perPage
+		}
+		if perPage == 0 {
+			perPage = 50
+		}
+		if perPage > 1000 {
+			perPage = 1000
+		}
+	}
+
+	searchParams := lb.newSearchParams(ctx, perPage)
+	resp := &RESTAPIListResponse{
+		Page:    searchParams.Page,
+		PerPage: perPage,
+	}
+	var err error
+	if searchParams.Keyset != nil {
+		resp.Data, resp.Total, resp.PrevCursor, resp.NextCursor, err = lb.keysetSearch(searchParams, ctx)
+	} else {
+		resp.Data, resp.Total, err = lb.Searcher(h.mb.NewModelSlice(), searchParams, ctx)
+	}
+	if err != nil {
+		writeRESTAPIError(w, err)
+		return
+	}
+	if resp.Data, err = h.readableList(resp.Data, r); err != nil {
+		writeRESTAPIError(w, err)
+		return
+	}
+
+	writeRESTAPIJSON(w, http.StatusOK, resp)
+}
+
+func (h *restAPIHandler) get(w http.ResponseWriter, r *http.Request) {
+	ctx := &web.EventContext{R: r, W: w}
+	obj, err := h.mb.editing.Fetcher(h.mb.NewModel(), pat.Param(r, "id"), ctx)
+	if err != nil {
+		writeRESTAPIError(w, err)
+		return
+	}
+	if h.mb.Info().Verifier().Do(PermGet).ObjectOn(obj).WithReq(r).IsAllowed() != nil {
+		writeRESTAPIError(w, perm.PermissionDenied)
+		return
+	}
+
+	h.writeReadable(w, http.StatusOK, obj, r)
+}
+
+func (h *restAPIHandler) create(w http.ResponseWriter, r *http.Request) {
+	h.save(w, r, "", false)
+}
+
+// replace is PUT, which replaces all the writable editing fields of the record.
+func (h *restAPIHandler) replace(w http.ResponseWriter, r *http.Request) {
+	h.save(w, r, pat.Param(r, "id"), true)
+}
+
+// update is PATCH, which only sets the fields in the request body.
+func (h *restAPIHandler) update(w http.ResponseWriter, r *http.Request) {
+	h.save(w, r, pat.Param(r, "id"), false)
+}
+
+func (h *restAPIHandler) save(w http.ResponseWriter, r *http.Request, id string, replace bool) {
+	ctx := &web.EventContext{R: r, W: w}
+	eb := h.editingBuilder(id)
+
+	var err error
+	obj := h.mb.NewModel()
+	permission := PermCreate
+	if id != "" {
+		permission = PermUpdate
+		if obj, err = eb.Fetcher(obj, id, ctx); err != nil {
+			writeRESTAPIError(w, err)
+			return
+		}
+	}
+
+	if eb.Setter != nil {
+		eb.Setter(obj, ctx)
+	}
+	vErr, err := h.decode(eb, obj, id == "", replace, ctx)
+	if err != nil {
+		writeRESTAPIJSON(w, http.StatusBadRequest, &RESTAPIErrorResponse{Error: err.Error()})
+		return
+	}
+	if vErr.HaveErrors() {
+		writeRESTAPIJSON(w, http.StatusUnprocessableEntity, h.validationErrorResponse(&vErr))
+		return
+	}
+
+	if h.mb.Info().Verifier().Do(permission).ObjectOn(obj).WithReq(r).IsAllowed() != nil {
+		writeRESTAPIError(w, perm.PermissionDenied)
+		return
+	}
+
+	if vErr := eb.validate(obj, ctx); vErr.HaveErrors() {
+		writeRESTAPIJSON(w, http.StatusUnprocessableEntity, h.validationErrorResponse(&vErr))
+		return
+	}
+
+	if err = eb.Save(obj, id, ctx); err != nil {
+		writeRESTAPIError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if id == "" {
+		status = http.StatusCreated
+	}
+	h.writeReadable(w, status, obj, r)
+}
+
+func (h *restAPIHandler) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := &web.EventContext{R: r, W: w}
+	id := pat.Param(r, "id")
+	obj, err := h.mb.editing.Fetcher(h.mb.NewModel(), id, ctx)
+	if err != nil {
+		writeRESTAPIError(w, err)
+		return
+	}
+	if h.mb.Info().Verifier().Do(PermDelete).ObjectOn(obj).WithReq(r).IsAllowed() != nil {
+		writeRESTAPIError(w, perm.PermissionDenied)
+		return
+	}
+
+	if err = h.mb.editing.Delete(obj, id, ctx); err != nil {
+		writeRESTAPIError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decode sets the editing fields in the request body to obj, or all the editing fields if replace,
+// which are the zero values and the empty form values for the setters if they are not in the body.
+// The fields that are not writable for the current user are ignored like in the editing form.
+// The fields with the custom SetterFunc are set by their setters with the values as the form values,
+// so that the setters like hashing the passwords are not skipped.
+func (h *restAPIHandler) decode(eb *EditingBuilder, obj interface{}, creating bool, replace bool, ctx *web.EventContext) (vErr web.ValidationErrors, err error) {
+	r := ctx.R
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+	var keys map[string]json.RawMessage
+	if err = json.Unmarshal(body, &keys); err != nil {
+		return
+	}
+	fromObj := h.mb.NewModel()
+	if err = json.Unmarshal(body, fromObj); err != nil {
+		return
+	}
+
+	info := h.mb.Info()
+	for _, f := range eb.fields {
+		key := jsonFieldName(h.mb.modelType.Elem(), f.name)
+		if key == "" || !hasJSONKey(keys, key) && !replace {
+			continue
+		}
+		if !info.FieldWritable(obj, f.name, creating, r) {
+			continue
+		}
+		if f.setterFunc != nil && f.customSetter {
+			var v string
+			if raw := jsonValue(keys, key); raw != nil {
+				if v, err = jsonFormValue(raw); err != nil {
+					return vErr, fmt.Errorf("%s: %w", key, err)
+				}
+			}
+			fr := r.Clone(r.Context())
+			fr.Form = url.Values{f.name: []string{v}}
+			fr.PostForm = fr.Form
+			if err1 := f.setterFunc(obj, &FieldContext{
+				ModelInfo: info,
+				FormKey:   f.name,
+				Name:      f.name,
+				Label:     eb.getLabel(f.NameLabel),
+			}, &web.EventContext{R: fr, W: ctx.W}); err1 != nil {
+				vErr.FieldError(f.name, err1.Error())
+			}
+			continue
+		}
+		val, err1 := reflectutils.Get(fromObj, f.name)
+		if err1 != nil {
+			continue
+		}
+		if err = reflectutils.Set(obj, f.name, val); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// jsonFormValue converts the JSON value to the form value for the SetterFunc, null is the empty value.
+func jsonFormValue(raw json.RawMessage) (v string, err error) {
+	var i interface{}
+	if err = json.Unmarshal(raw, &i); err != nil {
+		return
+	}
+	switch t := i.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return t, nil
+	case float64:
+		return strings.TrimSpace(string(raw)), nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	}
+	return "", errors.New("the field with the setter must be a string, a number or a boolean")
+}
+
+// writeReadable writes obj without the fields that the current user can not get.
+func (h *restAPIHandler) writeReadable(w http.ResponseWriter, status int, obj interface{}, r *http.Request) {
+	v, err := h.mb.Info().readableJSON(PermGet, obj, r)
+	if err != nil {
+		writeRESTAPIError(w, err)
+		return
+	}
+	writeRESTAPIJSON(w, status, v)
+}
+
+// readableList removes the fields that the current user can not list from the records.
+func (h *restAPIHandler) readableList(data interface{}, r *http.Request) (v interface{}, err error) {
+	rv := reflect.ValueOf(data)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice {
+		return data, nil
+	}
+	records := make([]interface{}, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		var record interface{}
+		if record, err = h.mb.Info().readableJSON(PermList, rv.Index(i).Interface(), r); err != nil {
+			return
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (h *restAPIHandler) validationErrorResponse(vErr *web.ValidationErrors) (r *RESTAPIErrorResponse) {
+	r = &RESTAPIErrorResponse{
+		Error:        "validation failed",
+		GlobalErrors: vErr.GetGlobalErrors(),
+		FieldErrors:  make(map[string][]string),
+	}
+
+	if names, ok := fieldErrorNames(vErr); ok {
+		for _, name := range names {
+			r.FieldErrors[name] = vErr.GetFieldErrors(name)
+		}
+		return
+	}
+
+	// look up all the fields of the model if ValidationErrors can not list its fields
+	names := map[string]bool{}
+	for _, f := range h.mb.editing.fields {
+		names[f.name] = true
+	}
+	for _, f := range reflect.VisibleFields(h.mb.modelType.Elem()) {
+		names[f.Name] = true
+	}
+	for name := range names {
+		if errs := vErr.GetFieldErrors(name); len(errs) > 0 {
+			r.FieldErrors[name] = errs
+		}
+	}
+	return
+}
+
+// fieldErrorNames returns the names of all the fields with errors, including the nested ones like Items[0].Name,
+// which are read from the unexported map of ValidationErrors, ok is false if it is not found.
+func fieldErrorNames(vErr *web.ValidationErrors) (r []string, ok bool) {
+	m := reflect.ValueOf(vErr).Elem().FieldByName("fieldErrors")
+	if !m.IsValid() || m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	for _, k := range m.MapKeys() {
+		r = append(r, k.String())
+	}
+	return r, true
+}
+
+// jsonFieldName returns the key of the struct field in the encoded JSON.
+func jsonFieldName(t reflect.Type, name string) string {
+	sf, ok := t.FieldByName(name)
+	if !ok {
+		return name
+	}
+	tag := strings.Split(sf.Tag.Get("json"), ",")[0]
+	if tag == "-" {
+		return ""
+	}
+	if tag != "" {
+		return tag
+	}
+	return name
+}
+
+// hasJSONKey matches the key case-insensitively like encoding/json.
+func hasJSONKey(keys map[string]json.RawMessage, key string) bool {
+	return jsonValue(keys, key) != nil
+}
+
+// jsonValue returns the value of the key case-insensitively like json.Unmarshal.
+func jsonValue(keys map[string]json.RawMessage, key string) json.RawMessage {
+	if key == "" {
+		return nil
+	}
+	for k, v := range keys {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return nil
+}
+
+func writeRESTAPIJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeRESTAPIError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrRecordNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, perm.PermissionDenied):
+		status = http.StatusForbidden
+	case errors.Is(err, ErrEditLeaseHeld):
+		status = http.StatusConflict
+	}
+	writeRESTAPIJSON(w, status, &RESTAPIErrorResponse{Error: err.Error()})
+}
@@ -0,0 +1,220 @@
+package presets
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qor5/web"
+)
+
+type restAPIItem struct {
+	ID    string
+	Name  string `json:"name"`
+	Price int
+	Note  string
+}
+
+type restAPIItemsOperator struct {
+	items map[string]*restAPIItem
+}
+
+func (op *restAPIItemsOperator) Search(obj interface{}, params *SearchParams, ctx *web.EventContext) (r interface{}, totalCount int, err error) {
+	var items []*restAPIItem
+	for _, id := range []string{"1", "2", "3"} {
+		if item, ok := op.items[id]; ok {
+			items = append(items, item)
+		}
+	}
+	totalCount = len(items)
+	start := int((params.Page - 1) * params.PerPage)
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + int(params.PerPage)
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end], totalCount, nil
+}
+
+func (op *restAPIItemsOperator) Fetch(obj interface{}, id string, ctx *web.EventContext) (r interface{}, err error) {
+	item, ok := op.items[id]
+	if !ok {
+		return nil, ErrRecordNotFound
+	}
+	v := *item
+	return &v, nil
+}
+
+func (op *restAPIItemsOperator) Save(obj interface{}, id string, ctx *web.EventContext) (err error) {
+	item := obj.(*restAPIItem)
+	if id == "" {
+		item.ID = "3"
+	}
+	op.items[item.ID] = item
+	return
+}
+
+func (op *restAPIItemsOperator) Delete(obj interface{}, id string, ctx *web.EventContext) (err error) {
+	delete(op.items, id)
+	return
+}
+
+func TestRESTAPI(t *testing.T) {
+	op := &restAPIItemsOperator{items: map[string]*restAPIItem{
+		"1": {ID: "1", Name: "A", Price: 1},
+		"2": {ID: "2", Name: "B", Price: 2},
+	}}
+	b := New().URIPrefix("/admin").RESTAPIPrefix("/api").DataOperator(op)
+	b.Model(&restAPIItem{}).
+		Editing("Name", "Price").
+		ValidateFunc(func(obj interface{}, ctx *web.EventContext) (err web.ValidationErrors) {
+			if obj.(*restAPIItem).Name == "" {
+				err.FieldError("Name", "Name is required")
+			}
+			if obj.(*restAPIItem).Name == "nested" {
+				err.FieldError("Items[0].Name", "Name is required")
+			}
+			return
+		})
+
+	cases := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		status   int
+		excepted string
+	}{
+		{
+			name:     "list",
+			method:   "GET",
+			path:     "/admin/api/rest-api-items?per_page=1&page=2",
+			status:   200,
+			excepted: `{"data":[{"ID":"2","name":"B","Price":2,"Note":""}],"total":2,"page":2,"per_page":1}`,
+		},
+		{
+			name:     "get",
+			method:   "GET",
+			path:     "/admin/api/rest-api-items/1",
+			status:   200,
+			excepted: `{"ID":"1","name":"A","Price":1,"Note":""}`,
+		},
+		{
+			name:     "not found",
+			method:   "GET",
+			path:     "/admin/api/rest-api-items/4",
+			status:   404,
+			excepted: `{"error":"record not found"}`,
+		},
+		{
+			name:     "validation errors",
+			method:   "POST",
+			path:     "/admin/api/rest-api-items",
+			body:     `{"name":"","Price":3}`,
+			status:   422,
+			excepted: `{"error":"validation failed","field_errors":{"Name":["Name is required"]}}`,
+		},
+		{
+			name:     "create ignores the fields that are not editable",
+			method:   "POST",
+			path:     "/admin/api/rest-api-items",
+			body:     `{"name":"C","Price":3,"Note":"x"}`,
+			status:   201,
+			excepted: `{"ID":"3","name":"C","Price":3,"Note":""}`,
+		},
+		{
+			name:     "update only the fields in the body",
+			method:   "PATCH",
+			path:     "/admin/api/rest-api-items/1",
+			body:     `{"Price":10}`,
+			status:   200,
+			excepted: `{"ID":"1","name":"A","Price":10,"Note":""}`,
+		},
+		{
+			name:     "replace all the editing fields",
+			method:   "PUT",
+			path:     "/admin/api/rest-api-items/1",
+			body:     `{"name":"D"}`,
+			status:   200,
+			excepted: `{"ID":"1","name":"D","Price":0,"Note":""}`,
+		},
+		{
+			name:     "nested validation errors",
+			method:   "PATCH",
+			path:     "/admin/api/rest-api-items/1",
+			body:     `{"name":"nested"}`,
+			status:   422,
+			excepted: `{"error":"validation failed","field_errors":{"Items[0].Name":["Name is required"]}}`,
+		},
+		{
+			name:   "delete",
+			method: "DELETE",
+			path:   "/admin/api/rest-api-items/2",
+			status: 204,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(c.method, c.path, strings.NewReader(c.body))
+			w := httptest.NewRecorder()
+			b.ServeHTTP(w, r)
+			if w.Code != c.status {
+				t.Fatalf("status = %d, excepted %d, body: %s", w.Code, c.status, w.Body.String())
+			}
+			if c.excepted == "" {
+				return
+			}
+			var got, excepted interface{}
+			json.Unmarshal(w.Body.Bytes(), &got)
+			json.Unmarshal([]byte(c.excepted), &excepted)
+			gotJSON, _ := json.Marshal(got)
+			exceptedJSON, _ := json.Marshal(excepted)
+			if string(gotJSON) != string(exceptedJSON) {
+				t.Errorf("body = %s, excepted %s", gotJSON, exceptedJSON)
+			}
+		})
+	}
+
+	if _, ok := op.items["2"]; ok {
+		t.Errorf("item 2 is not deleted")
+	}
+}
+
+func TestRESTAPISetterFunc(t *testing.T) {
+	op := &restAPIItemsOperator{items: map[string]*restAPIItem{
+		"1": {ID: "1", Name: "A", Price: 1},
+	}}
+	b := New().URIPrefix("/admin").RESTAPIPrefix("/api").DataOperator(op)
+	eb := b.Model(&restAPIItem{}).Editing("Name", "Note")
+	eb.Field("Note").SetterFunc(func(obj interface{}, field *FieldContext, ctx *web.EventContext) (err error) {
+		v := ctx.R.FormValue(field.FormKey)
+		if v == "" {
+			return errors.New("Note is required")
+		}
+		obj.(*restAPIItem).Note = "hashed:" + v
+		return
+	})
+
+	update := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		b.ServeHTTP(w, httptest.NewRequest("PATCH", "/admin/api/rest-api-items/1", strings.NewReader(body)))
+		return w
+	}
+	if w := update(`{"Note":"secret","name":"B"}`); w.Code != 200 {
+		t.Fatalf("status = %d, body: %s", w.Code, w.Body.String())
+	}
+	if item := op.items["1"]; item.Note != "hashed:secret" || item.Name != "B" {
+		t.Errorf("item = %+v", item)
+	}
+	if w := update(`{"Note":""}`); w.Code != 422 || op.items["1"].Note != "hashed:secret" {
+		t.Errorf("the error of the setter is not returned: %d %s", w.Code, w.Body.String())
+	}
+	if w := update(`{"Note":{"a":1}}`); w.Code != 400 {
+		t.Errorf("the object is set by the setter: %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,87 @@
+package presets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// TenantScoped is implemented by the models that belong to a tenant, like the products of a brand.
+// The data operators scope their operations to the tenant of the request, see gorm2op.DataOperatorBuilder.
+type TenantScoped interface {
+	// TenantField returns the name of the field that stores the tenant, like "BrandID".
+	TenantField() string
+}
+
+// TenantResolverFunc returns the tenant of the request, an empty tenant means the request has no tenant,
+// and the error responds 403 Forbidden.
+type TenantResolverFunc func(r *http.Request) (tenant string, err error)
+
+// TenantResolver sets the func that resolves the tenant of every request of the admin,
+// the tenant is stored in the context of the request, see TenantFromContext.
+func (b *Builder) TenantResolver(v TenantResolverFunc) (r *Builder) {
+	b.tenantResolver = v
+	return b
+}
+
+func (b *Builder) resolveTenant(in http.Handler) http.Handler {
+	if b.tenantResolver == nil {
+		return in
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant, err := b.tenantResolver(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		in.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), tenant)))
+	})
+}
+
+type tenantKey struct{}
+
+// WithTenant returns the context with the tenant, like for the jobs that are not run by requests.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant of the context, empty if there is no tenant.
+func TenantFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// TenantOf returns the tenant of the TenantScoped record, empty if obj is not TenantScoped or its tenant is not set.
+func TenantOf(obj interface{}) string {
+	ts, ok := obj.(TenantScoped)
+	if !ok {
+		return ""
+	}
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	f := reflect.Indirect(v.FieldByName(ts.TenantField()))
+	if !f.IsValid() || f.IsZero() {
+		return ""
+	}
+	return fmt.Sprint(f.Interface())
+}
+
+// TenantPath puts the path into the directory of the tenant, like "/products/1.html" to "/acme/products/1.html",
+// and "//cdn.com/system/1.png" to "//cdn.com/acme/system/1.png", the path is not changed if the tenant is empty.
+func TenantPath(tenant string, path string) string {
+	if tenant == "" {
+		return path
+	}
+	if strings.HasPrefix(path, "//") {
+		host, p, _ := strings.Cut(strings.TrimPrefix(path, "//"), "/")
+		return "//" + host + "/" + tenant + "/" + p
+	}
+	return "/" + tenant + "/" + strings.TrimPrefix(path, "/")
+}
@@ -0,0 +1,81 @@
+package presets
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type tenantItem struct {
+	ID      uint
+	BrandID uint
+}
+
+func (tenantItem) TenantField() string {
+	return "BrandID"
+}
+
+func TestTenantResolver(t *testing.T) {
+	b := New().TenantResolver(func(r *http.Request) (tenant string, err error) {
+		if r.Header.Get("X-Brand") == "" {
+			return "", errors.New("unknown brand")
+		}
+		return r.Header.Get("X-Brand"), nil
+	})
+
+	var tenant string
+	h := b.wrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant = TenantFromContext(r.Context())
+	}))
+
+	r := httptest.NewRequest("GET", "/admin", nil)
+	r.Header.Set("X-Brand", "acme")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || tenant != "acme" {
+		t.Errorf("code = %d, tenant = %q", w.Code, tenant)
+	}
+
+	tenant = ""
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/admin", nil))
+	if w.Code != http.StatusForbidden || tenant != "" {
+		t.Errorf("code = %d, tenant = %q", w.Code, tenant)
+	}
+}
+
+func TestTenantOf(t *testing.T) {
+	cases := []struct {
+		obj  interface{}
+		want string
+	}{
+		{&tenantItem{ID: 1, BrandID: 2}, "2"},
+		{tenantItem{BrandID: 3}, "3"},
+		{&tenantItem{ID: 1}, ""},
+		{&restAPIItem{ID: "1"}, ""},
+	}
+	for _, c := range cases {
+		if got := TenantOf(c.obj); got != c.want {
+			t.Errorf("TenantOf(%+v) = %q, want %q", c.obj, got, c.want)
+		}
+	}
+}
+
+func TestTenantPath(t *testing.T) {
+	cases := []struct {
+		tenant string
+		path   string
+		want   string
+	}{
+		{"", "/products/1.html", "/products/1.html"},
+		{"acme", "/products/1.html", "/acme/products/1.html"},
+		{"acme", "products/1.html", "/acme/products/1.html"},
+		{"acme", "//cdn.com/system/1.png", "//cdn.com/acme/system/1.png"},
+	}
+	for _, c := range cases {
+		if got := TenantPath(c.tenant, c.path); got != c.want {
+			t.Errorf("TenantPath(%q, %q) = %q, want %q", c.tenant, c.path, got, c.want)
+		}
+	}
+}
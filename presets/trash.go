@@ -0,0 +1,298 @@
+package presets
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/qor5/admin/presets/actions"
+	. "github.com/qor5/ui/vuetify"
+	vx "github.com/qor5/ui/vuetifyx"
+	"github.com/qor5/web"
+	"github.com/qor5/x/perm"
+	h "github.com/theplant/htmlgo"
+)
+
+// TrashBuilder lists the soft-deleted records of the model in the trash of the listing,
+// where they can be restored or deleted permanently.
+type TrashBuilder struct {
+	lb               *ListingBuilder
+	Searcher         SearchFunc
+	Fetcher          FetchFunc
+	Restorer         RestoreFunc
+	PermanentDeleter DeleteFunc
+}
+
+// Trash enables the trash on the listing, by default the DataOperator is used if it implements Trasher.
+// Restoring requires the PermRestore permission, and deleting permanently requires the PermDeletePermanently permission,
+// both are checked on every record fetched by the Fetcher of the trash.
+// The AfterRestore hooks run with the restored records after the Restorer, in the same transaction.
+func (b *ListingBuilder) Trash() (r *TrashBuilder) {
+	if b.trash != nil {
+		return b.trash
+	}
+
+	b.trash = &TrashBuilder{lb: b}
+	if t, ok := b.mb.p.dataOperator.(Trasher); ok {
+		b.trash.SearchFunc(t.SearchTrash)
+		b.trash.FetchFunc(t.FetchTrash)
+		b.trash.RestoreFunc(t.Restore)
+		b.trash.DeletePermanentlyFunc(t.DeletePermanently)
+	}
+	b.mb.RegisterEventFunc(actions.Restore, b.trash.restore)
+	b.mb.RegisterEventFunc(actions.DeletePermanentlyConfirmation, b.trash.deletePermanentlyConfirmation)
+	b.mb.RegisterEventFunc(actions.DoDeletePermanently, b.trash.doDeletePermanently)
+	return b.trash
+}
+
+func (b *ListingBuilder) HasTrash() bool {
+	return b.trash != nil
+}
+
+func (b *TrashBuilder) SearchFunc(v SearchFunc) (r *TrashBuilder) {
+	b.Searcher = v
+	return b
+}
+
+// FetchFunc fetches the soft-deleted record to check the permissions on it.
+func (b *TrashBuilder) FetchFunc(v FetchFunc) (r *TrashBuilder) {
+	b.Fetcher = v
+	return b
+}
+
+func (b *TrashBuilder) RestoreFunc(v RestoreFunc) (r *TrashBuilder) {
+	b.Restorer = v
+	return b
+}
+
+func (b *TrashBuilder) DeletePermanentlyFunc(v DeleteFunc) (r *TrashBuilder) {
+	b.PermanentDeleter = v
+	return b
+}
+
+// inTrash returns true if the listing is showing the trash, the trash is not available in the listing dialog.
+func (b *ListingBuilder) inTrash(ctx *web.EventContext) bool {
+	return b.trash != nil &&
+		!IsInDialog(ctx.R.Context()) &&
+		ctx.R.URL.Query().Get(ParamTrash) == "true"
+}
+
+func (b *TrashBuilder) search(searchParams *SearchParams, ctx *web.EventContext) (r interface{}, totalCount int, err error) {
+	if b.Searcher == nil {
+		panic("a DataOperator implementing presets.Trasher or Trash().SearchFunc(...) required")
+	}
+	return b.Searcher(b.lb.mb.NewModelSlice(), searchParams, ctx)
+}
+
+// button switches between the listing and the trash.
+func (b *TrashBuilder) button(ctx *web.EventContext) h.HTMLComponent {
+	msgr := MustGetMessages(ctx.R)
+	if b.lb.inTrash(ctx) {
+		return VBtn(msgr.TrashBack).
+			Depressed(true).
+			Class("ml-2").
+			Attr("@click", web.Plaid().PushStateURL(b.lb.mb.Info().ListingHref()).Go())
+	}
+
+	return VBtn(msgr.Trash).
+		Depressed(true).
+		Class("ml-2").
+		Attr("@click", web.Plaid().
+			PushState(true).
+			Queries(url.Values{ParamTrash: []string{"true"}}).
+			Go())
+}
+
+// bulkButtons restores or deletes the selected records in the trash.
+func (b *TrashBuilder) bulkButtons(ctx *web.EventContext) (r h.HTMLComponents) {
+	msgr := MustGetMessages(ctx.R)
+	verifier := b.lb.mb.Info().Verifier()
+	if verifier.Do(PermRestore).WithReq(ctx.R).IsAllowed() == nil {
+		r = append(r, VBtn(msgr.Restore).
+			Color(ColorPrimary).
+			Depressed(true).
+			Dark(true).
+			Class("ml-2").
+			Attr("@click", web.Plaid().
+				EventFunc(actions.Restore).
+				MergeQuery(true).
+				Go()))
+	}
+	if verifier.Do(PermDeletePermanently).WithReq(ctx.R).IsAllowed() == nil {
+		r = append(r, VBtn(msgr.DeletePermanently).
+			Color("error").
+			Depressed(true).
+			Dark(true).
+			Class("ml-2").
+			Attr("@click", web.Plaid().
+				EventFunc(actions.DeletePermanentlyConfirmation).
+				MergeQuery(true).
+				Go()))
+	}
+	return
+}
+
+func (b *TrashBuilder) rowMenuItemFuncs() []vx.RowMenuItemFunc {
+	return []vx.RowMenuItemFunc{
+		func(obj interface{}, id string, ctx *web.EventContext) h.HTMLComponent {
+			if b.lb.mb.Info().Verifier().Do(PermRestore).ObjectOn(obj).WithReq(ctx.R).IsAllowed() != nil {
+				return nil
+			}
+			return VListItem(
+				VListItemIcon(VIcon("restore")),
+				VListItemTitle(h.Text(MustGetMessages(ctx.R).Restore)),
+			).Attr("@click", web.Plaid().
+				EventFunc(actions.Restore).
+				Query(ParamID, id).
+				Go())
+		},
+		func(obj interface{}, id string, ctx *web.EventContext) h.HTMLComponent {
+			if b.lb.mb.Info().Verifier().Do(PermDeletePermanently).ObjectOn(obj).WithReq(ctx.R).IsAllowed() != nil {
+				return nil
+			}
+			return VListItem(
+				VListItemIcon(VIcon("delete_forever")),
+				VListItemTitle(h.Text(MustGetMessages(ctx.R).DeletePermanently)),
+			).Attr("@click", web.Plaid().
+				EventFunc(actions.DeletePermanentlyConfirmation).
+				Query(ParamID, id).
+				Go())
+		},
+	}
+}
+
+// trashIds returns the id of the row menu item, or the selected ids of the bulk buttons.
+func (b *TrashBuilder) trashIds(ctx *web.EventContext) []string {
+	if id := ctx.R.FormValue(ParamID); id != "" {
+		return []string{id}
+	}
+	return getSelectedIds(ctx)
+}
+
+// each calls f with every record fetched by the Fetcher in a transaction, so that either all or none of the records are changed.
+// The permission is checked on all the records before any of them is changed, like the row menu items.
+func (b *TrashBuilder) each(ids []string, permission string, ctx *web.EventContext, f func(obj interface{}, id string, ctx *web.EventContext) error) error {
+	if b.Fetcher == nil {
+		panic("a DataOperator implementing presets.Trasher or Trash().FetchFunc(...) required")
+	}
+	return dataOperatorTransaction(b.lb.mb.p.dataOperator)(ctx, func(txCtx *web.EventContext) error {
+		objs := make([]interface{}, len(ids))
+		for i, id := range ids {
+			obj, err := b.Fetcher(b.lb.mb.NewModel(), id, txCtx)
+			if err != nil {
+				return err
+			}
+			if err = b.lb.mb.Info().Verifier().Do(permission).ObjectOn(obj).WithReq(ctx.R).IsAllowed(); err != nil {
+				return err
+			}
+			objs[i] = obj
+		}
+		for i, id := range ids {
+			if err := f(objs[i], id, txCtx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (b *TrashBuilder) restore(ctx *web.EventContext) (r web.EventResponse, err error) {
+	if b.lb.mb.Info().Verifier().Do(PermRestore).WithReq(ctx.R).IsAllowed() != nil {
+		ShowMessage(&r, perm.PermissionDenied.Error(), "warning")
+		return
+	}
+
+	msgr := MustGetMessages(ctx.R)
+	ids := b.trashIds(ctx)
+	if len(ids) == 0 {
+		ShowMessage(&r, msgr.TrashNoRecordsSelected, "warning")
+		return
+	}
+	if err1 := b.each(ids, PermRestore, ctx, b.restoreRecord); err1 != nil {
+		ShowMessage(&r, err1.Error(), "warning")
+		return
+	}
+
+	ShowMessage(&r, msgr.TrashRestored(len(ids)), "")
+	r.PushState = web.Location(url.Values{ParamSelectedIds: []string{}}).MergeQuery(true)
+	return
+}
+
+// restoreRecord restores the record by the Restorer, then runs the AfterRestore hooks with the restored record.
+func (b *TrashBuilder) restoreRecord(obj interface{}, id string, ctx *web.EventContext) (err error) {
+	if err = b.Restorer(obj, id, ctx); err != nil {
+		return
+	}
+	mb := b.lb.mb
+	if !mb.hasHooks(AfterRestore) {
+		return
+	}
+	restored, err := mb.editing.Fetcher(mb.NewModel(), id, ctx)
+	if err != nil {
+		return
+	}
+	return mb.RunHooks(AfterRestore, nil, restored, ctx)
+}
+
+func (b *TrashBuilder) deletePermanentlyConfirmation(ctx *web.EventContext) (r web.EventResponse, err error) {
+	msgr := MustGetMessages(ctx.R)
+	ids := b.trashIds(ctx)
+	if len(ids) == 0 {
+		ShowMessage(&r, msgr.TrashNoRecordsSelected, "warning")
+		return
+	}
+
+	r.UpdatePortals = append(r.UpdatePortals, &web.PortalUpdate{
+		Name: DeleteConfirmPortalName,
+		Body: VDialog(
+			VCard(
+				VCardTitle(h.Text(msgr.DeletePermanentlyConfirmationText(len(ids)))),
+				VCardActions(
+					VSpacer(),
+					VBtn(msgr.Cancel).
+						Depressed(true).
+						Class("ml-2").
+						On("click", "vars.deleteConfirmation = false"),
+
+					VBtn(msgr.DeletePermanently).
+						Color("error").
+						Depressed(true).
+						Dark(true).
+						Attr("@click", web.Plaid().
+							EventFunc(actions.DoDeletePermanently).
+							Query(ParamID, ctx.R.FormValue(ParamID)).
+							Query(ParamSelectedIds, strings.Join(ids, ",")).
+							MergeQuery(true).
+							Go()),
+				),
+			),
+		).MaxWidth("600px").
+			Attr("v-model", "vars.deleteConfirmation").
+			Attr(web.InitContextVars, `{deleteConfirmation: false}`),
+	})
+
+	r.VarsScript = "setTimeout(function(){ vars.deleteConfirmation = true }, 100)"
+	return
+}
+
+func (b *TrashBuilder) doDeletePermanently(ctx *web.EventContext) (r web.EventResponse, err error) {
+	if b.lb.mb.Info().Verifier().Do(PermDeletePermanently).WithReq(ctx.R).IsAllowed() != nil {
+		ShowMessage(&r, perm.PermissionDenied.Error(), "warning")
+		return
+	}
+
+	msgr := MustGetMessages(ctx.R)
+	ids := b.trashIds(ctx)
+	if len(ids) == 0 {
+		ShowMessage(&r, msgr.TrashNoRecordsSelected, "warning")
+		return
+	}
+	if err1 := b.each(ids, PermDeletePermanently, ctx, b.PermanentDeleter); err1 != nil {
+		ShowMessage(&r, err1.Error(), "warning")
+		return
+	}
+
+	ShowMessage(&r, msgr.TrashDeleted(len(ids)), "")
+	web.AppendVarsScripts(&r, "vars.deleteConfirmation = false")
+	r.PushState = web.Location(url.Values{ParamSelectedIds: []string{}}).MergeQuery(true)
+	return
+}
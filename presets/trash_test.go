@@ -0,0 +1,117 @@
+package presets
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/qor5/web"
+	"github.com/qor5/x/perm"
+)
+
+type trashItem struct {
+	ID   uint
+	Name string
+}
+
+type trashOperator struct {
+	restAPIItemsOperator
+	restored []string
+	deleted  []string
+}
+
+func (op *trashOperator) Fetch(obj interface{}, id string, ctx *web.EventContext) (r interface{}, err error) {
+	return &trashItem{Name: id}, nil
+}
+
+func (op *trashOperator) SearchTrash(obj interface{}, params *SearchParams, ctx *web.EventContext) (r interface{}, totalCount int, err error) {
+	return []*trashItem{{ID: 1, Name: "A"}}, 1, nil
+}
+
+func (op *trashOperator) FetchTrash(obj interface{}, id string, ctx *web.EventContext) (r interface{}, err error) {
+	i, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, ErrRecordNotFound
+	}
+	return &trashItem{ID: uint(i), Name: id}, nil
+}
+
+func (op *trashOperator) Restore(obj interface{}, id string, ctx *web.EventContext) (err error) {
+	op.restored = append(op.restored, id)
+	return
+}
+
+func (op *trashOperator) DeletePermanently(obj interface{}, id string, ctx *web.EventContext) (err error) {
+	op.deleted = append(op.deleted, id)
+	return
+}
+
+func TestTrash(t *testing.T) {
+	op := &trashOperator{}
+	b := New().DataOperator(op).
+		Permission(perm.New().Policies(
+			perm.PolicyFor(perm.Anybody).WhoAre(perm.Allowed).ToDo(perm.Anything).On(perm.Anything),
+			perm.PolicyFor(perm.Anybody).WhoAre(perm.Denied).ToDo(PermRestore, PermDeletePermanently).On("*:trash_items:9:*"),
+		))
+	mb := b.Model(&trashItem{})
+	lb := mb.Listing().KeysetPagination(true)
+	var hooked []string
+	mb.Hook(AfterRestore, 0, func(old interface{}, obj interface{}, ctx *web.EventContext) (err error) {
+		hooked = append(hooked, obj.(*trashItem).Name)
+		return
+	})
+	tb := lb.Trash()
+	if tb.Searcher == nil || tb.Restorer == nil || tb.PermanentDeleter == nil {
+		t.Fatalf("trash funcs are not set from the data operator")
+	}
+
+	newCtx := func(url string) *web.EventContext {
+		return &web.EventContext{R: httptest.NewRequest("POST", url, nil), W: httptest.NewRecorder()}
+	}
+
+	ctx := newCtx("/trash-items?presets_trash=true&page=2")
+	if !lb.inTrash(ctx) {
+		t.Errorf("not in trash")
+	}
+	if sp := lb.newSearchParams(ctx, 10); sp.Keyset != nil || sp.Page != 2 {
+		t.Errorf("search params = %+v", sp)
+	}
+
+	er, err := tb.restore(newCtx("/trash-items?__execute_event__=presets_Restore&presets_trash=true&selected_ids=1,2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(op.restored, []string{"1", "2"}) || er.PushState == nil {
+		t.Errorf("restored = %v", op.restored)
+	}
+	if !reflect.DeepEqual(hooked, []string{"1", "2"}) {
+		t.Errorf("the restore hooks run with %v", hooked)
+	}
+
+	if _, err = tb.doDeletePermanently(newCtx("/trash-items?__execute_event__=presets_DoDeletePermanently&id=3&selected_ids=1,2")); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(op.deleted, []string{"3"}) {
+		t.Errorf("deleted = %v", op.deleted)
+	}
+
+	// the records that the user can't restore or delete are refused with the others
+	op.restored, op.deleted = nil, nil
+	if _, err = tb.restore(newCtx("/trash-items?__execute_event__=presets_Restore&selected_ids=1,9")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = tb.doDeletePermanently(newCtx("/trash-items?__execute_event__=presets_DoDeletePermanently&id=9")); err != nil {
+		t.Fatal(err)
+	}
+	if len(op.restored) != 0 || len(op.deleted) != 0 {
+		t.Errorf("restored = %v, deleted = %v", op.restored, op.deleted)
+	}
+
+	if _, err = tb.restore(newCtx("/trash-items?__execute_event__=presets_Restore")); err != nil {
+		t.Fatal(err)
+	}
+	if len(op.restored) != 0 {
+		t.Errorf("restored without selection = %v", op.restored)
+	}
+}
@@ -0,0 +1,567 @@
+package presets
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/qor5/admin/presets/actions"
+	. "github.com/qor5/ui/vuetify"
+	vx "github.com/qor5/ui/vuetifyx"
+	"github.com/qor5/web"
+	"github.com/qor5/x/perm"
+	h "github.com/theplant/htmlgo"
+)
+
+// ErrTreeCycle is returned when the record is saved under itself or its descendants.
+var ErrTreeCycle = errors.New("the record can not be moved under itself or its descendants")
+
+// treeCycleHookOrder runs the cycle check before the other hooks like activity.
+const treeCycleHookOrder = -100
+
+// treeMaxDepth stops walking the ancestors of the broken trees.
+const treeMaxDepth = 1000
+
+// TreeBuilder shows the records of the listing as a tree by the parent field, the children are loaded
+// when their parent is expanded. Dragging a node to another parent or position updates the parent and the
+// position of the record by the Saver of the editing.
+type TreeBuilder struct {
+	lb             *ListingBuilder
+	parentField    string
+	parentColumn   string
+	positionField  string
+	positionColumn string
+	titleFunc      func(obj interface{}, ctx *web.EventContext) string
+}
+
+// Tree adds the tree layout to the listing, by default the parent is the ParentID field in the parent_id column,
+// which is a string, an integer or a pointer of them, the roots have nil or zero parents.
+// Moving the nodes requires the PermUpdate permission on the parent field.
+func (b *ListingBuilder) Tree() (r *TreeBuilder) {
+	if b.tree == nil {
+		b.mb.RegisterEventFunc(actions.LoadTreeChildren, b.loadTreeChildren)
+		b.mb.RegisterEventFunc(actions.MoveTreeNode, b.moveTreeNode)
+		b.mb.Hook(BeforeSave, treeCycleHookOrder, func(old interface{}, obj interface{}, ctx *web.EventContext) (err error) {
+			if b.tree == nil || old == nil || b.tree.parentID(old) == b.tree.parentID(obj) {
+				return
+			}
+			return b.tree.checkCycle(obj, ctx)
+		})
+	}
+	b.tree = &TreeBuilder{
+		lb:           b,
+		parentField:  "ParentID",
+		parentColumn: "parent_id",
+	}
+	return b.tree
+}
+
+// ParentField sets the parent of the records and its column.
+func (b *TreeBuilder) ParentField(field string, dbColumn string) (r *TreeBuilder) {
+	b.parentField = field
+	b.parentColumn = dbColumn
+	return b
+}
+
+// PositionField sets the integer field of the order of the siblings, the nodes can only be reordered with it,
+// and the siblings are renumbered from 1 when a node is moved.
+func (b *TreeBuilder) PositionField(field string, dbColumn string) (r *TreeBuilder) {
+	b.positionField = field
+	b.positionColumn = dbColumn
+	return b
+}
+
+// TitleFunc sets the title of the node, by default it is the PageTitle() of the record or its id.
+func (b *TreeBuilder) TitleFunc(v func(obj interface{}, ctx *web.EventContext) string) (r *TreeBuilder) {
+	b.titleFunc = v
+	return b
+}
+
+func treeChildrenPortalName(parentID string) string {
+	return "presets_tree_children_" + parentID
+}
+
+func (b *TreeBuilder) parentType() reflect.Type {
+	f, ok := reflect.Indirect(reflect.ValueOf(b.lb.mb.NewModel())).Type().FieldByName(b.parentField)
+	if !ok {
+		panic(fmt.Sprintf("the parent field %s is not found", b.parentField))
+	}
+	return f.Type
+}
+
+// parentID returns the parent of the record as a string, it is empty for the roots.
+func (b *TreeBuilder) parentID(obj interface{}) string {
+	v := reflect.Indirect(reflect.ValueOf(obj)).FieldByName(b.parentField)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.IsZero() {
+		return ""
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// typedParent converts the parent id to the type of the parent field, the pointer is dereferenced.
+func (b *TreeBuilder) typedParent(id string) (r reflect.Value, err error) {
+	t := b.parentType()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	r = reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		r.SetString(id)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		if n, err = strconv.ParseInt(id, 10, 64); err != nil {
+			return
+		}
+		r.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		if n, err = strconv.ParseUint(id, 10, 64); err != nil {
+			return
+		}
+		r.SetUint(n)
+	default:
+		err = fmt.Errorf("the parent field %s of %s is not supported", b.parentField, t)
+	}
+	return
+}
+
+func (b *TreeBuilder) setParent(obj interface{}, id string) (err error) {
+	fv := reflect.Indirect(reflect.ValueOf(obj)).FieldByName(b.parentField)
+	if id == "" {
+		fv.Set(reflect.Zero(fv.Type()))
+		return
+	}
+	v, err := b.typedParent(id)
+	if err != nil {
+		return
+	}
+	if fv.Kind() == reflect.Ptr {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		v = p
+	}
+	fv.Set(v)
+	return
+}
+
+func (b *TreeBuilder) setPosition(obj interface{}, position int) {
+	fv := reflect.Indirect(reflect.ValueOf(obj)).FieldByName(b.positionField)
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fv.SetInt(int64(position))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		fv.SetUint(uint64(position))
+	case reflect.Float32, reflect.Float64:
+		fv.SetFloat(float64(position))
+	default:
+		panic(fmt.Sprintf("the position field %s is not a number", b.positionField))
+	}
+}
+
+func (b *TreeBuilder) position(obj interface{}) string {
+	return fmt.Sprint(reflect.Indirect(reflect.ValueOf(obj)).FieldByName(b.positionField).Interface())
+}
+
+// childrenCondition searches the children of the parent, or the roots if the parent is empty.
+func (b *TreeBuilder) childrenCondition(parentID string) (r *SQLCondition, err error) {
+	if parentID != "" {
+		v, err := b.typedParent(parentID)
+		if err != nil {
+			return nil, err
+		}
+		return &SQLCondition{Query: fmt.Sprintf("%s = ?", b.parentColumn), Args: []interface{}{v.Interface()}}, nil
+	}
+
+	t := b.parentType()
+	if t.Kind() == reflect.Ptr {
+		return &SQLCondition{Query: fmt.Sprintf("%s IS NULL", b.parentColumn)}, nil
+	}
+	return &SQLCondition{
+		Query: fmt.Sprintf("(%s IS NULL OR %s = ?)", b.parentColumn, b.parentColumn),
+		Args:  []interface{}{reflect.Zero(t).Interface()},
+	}, nil
+}
+
+func (b *TreeBuilder) orderBy() string {
+	if b.positionColumn == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s, %s", b.positionColumn, b.lb.mb.primaryField)
+}
+
+// children searches the children of the parent with the keyword and the filters of the listing.
+func (b *TreeBuilder) children(parentID string, ctx *web.EventContext) (objs []interface{}, hasChildren map[string]bool) {
+	cond, err := b.childrenCondition(parentID)
+	if err != nil {
+		panic(err)
+	}
+	objs = b.lb.layoutSearch(ctx, b.orderBy(), cond)
+
+	hasChildren = make(map[string]bool)
+	if len(objs) == 0 {
+		return
+	}
+	var ids []interface{}
+	for _, obj := range objs {
+		v, err := b.typedParent(vx.ObjectID(obj))
+		if err != nil {
+			panic(err)
+		}
+		ids = append(ids, v.Interface())
+	}
+	for _, child := range b.lb.layoutSearch(ctx, "", &SQLCondition{
+		Query: fmt.Sprintf("%s IN ?", b.parentColumn),
+		Args:  []interface{}{ids},
+	}) {
+		hasChildren[b.parentID(child)] = true
+	}
+	return
+}
+
+func (b *TreeBuilder) movable(obj interface{}, ctx *web.EventContext) bool {
+	info := b.lb.mb.Info()
+	verifier := info.Verifier().Do(PermUpdate)
+	if obj != nil {
+		verifier = verifier.ObjectOn(obj)
+	}
+	if verifier.WithReq(ctx.R).IsAllowed() != nil ||
+		!info.FieldAllowed(PermUpdate, obj, b.parentField, ctx.R) {
+		return false
+	}
+	return b.positionField == "" || info.FieldAllowed(PermUpdate, obj, b.positionField, ctx.R)
+}
+
+func (b *TreeBuilder) component(ctx *web.EventContext) h.HTMLComponent {
+	return h.Div(
+		web.Portal(b.childrenComponent("", ctx)).Name(treeChildrenPortalName("")),
+	).Class("pa-2")
+}
+
+// childrenComponent renders the children of the parent, which are dropped to or dragged out of the other parents.
+func (b *TreeBuilder) childrenComponent(parentID string, ctx *web.EventContext) h.HTMLComponent {
+	lb := b.lb
+	objs, hasChildren := b.children(parentID, ctx)
+
+	var nodes []h.HTMLComponent
+	for _, obj := range objs {
+		nodes = append(nodes, b.node(obj, hasChildren[vx.ObjectID(obj)], ctx))
+	}
+
+	move := web.Plaid().
+		EventFunc(actions.MoveTreeNode).
+		Query(ParamID, web.Var("$event.item.dataset.id")).
+		Query(ParamTreeParentID, web.Var("$event.to.dataset.parent")).
+		Query(ParamTreeIndex, web.Var("$event.newIndex")).
+		Go()
+	list := h.Tag("vx-draggable").
+		Attr("group", "presets_tree_"+lb.mb.uriName).
+		Attr("animation", "300").
+		Attr(":disabled", fmt.Sprint(!b.movable(nil, ctx))).
+		Attr("data-parent", parentID).
+		Attr("@add", move).
+		Children(nodes...).
+		Style("min-height: 24px;")
+	if b.positionField != "" {
+		list.Attr("@update", move)
+	}
+	return list
+}
+
+func (b *TreeBuilder) node(obj interface{}, hasChildren bool, ctx *web.EventContext) h.HTMLComponent {
+	id := vx.ObjectID(obj)
+	title := getPageTitle(obj, id)
+	if b.titleFunc != nil {
+		title = b.titleFunc(obj, ctx)
+	}
+	icon := "insert_drive_file"
+	if hasChildren {
+		icon = "folder"
+	}
+
+	// the leaves are expandable too, so that the nodes can be dropped under them
+	load := web.Plaid().
+		EventFunc(actions.LoadTreeChildren).
+		Query(ParamTreeParentID, id).
+		Go()
+	return h.Div(
+		web.Scope(
+			h.Div(
+				h.Div(
+					VBtn("").Icon(true).XSmall(true).
+						Children(VIcon("{{locals.expanded ? 'expand_more' : 'chevron_right'}}").Small(true)).
+						Attr("@click", fmt.Sprintf("locals.expanded = !locals.expanded; if (!locals.loaded) { locals.loaded = true; %s }", load)),
+					VIcon(icon).Small(true).Class("mx-1"),
+					h.Span(title).Class("text-body-2").
+						Attr("@click", b.lb.recordOnclick(id)).
+						Style("cursor: pointer;"),
+				).Class("d-flex align-center py-1"),
+				h.Div(
+					web.Portal().Name(treeChildrenPortalName(id)),
+				).Attr("v-show", "locals.expanded").Class("pl-6"),
+			),
+		).Init(`{ expanded: false, loaded: false }`).VSlot("{ locals }"),
+	).Attr("data-id", id)
+}
+
+// loadTreeChildren loads the children of the expanded node.
+func (b *ListingBuilder) loadTreeChildren(ctx *web.EventContext) (r web.EventResponse, err error) {
+	if b.tree == nil {
+		return r, errors.New("the tree is not enabled")
+	}
+	parentID := ctx.R.FormValue(ParamTreeParentID)
+	if parentID != "" {
+		if _, err = b.tree.typedParent(parentID); err != nil {
+			return
+		}
+	}
+	r.UpdatePortals = append(r.UpdatePortals, &web.PortalUpdate{
+		Name: treeChildrenPortalName(parentID),
+		Body: b.tree.childrenComponent(parentID, ctx),
+	})
+	return
+}
+
+// moveTreeNode sets the parent of the moved node to its new parent, renumbers the siblings at the new parent
+// if the tree has the position field, and reloads the children of the old and the new parents.
+func (b *ListingBuilder) moveTreeNode(ctx *web.EventContext) (r web.EventResponse, err error) {
+	if b.tree == nil {
+		return r, errors.New("the tree is not enabled")
+	}
+	tb := b.tree
+	eb := b.mb.editing
+	msgr := MustGetMessages(ctx.R)
+	id := ctx.R.FormValue(ParamID)
+	parentID := ctx.R.FormValue(ParamTreeParentID)
+	if parentID != "" {
+		if _, err = tb.typedParent(parentID); err != nil {
+			return
+		}
+	}
+
+	obj, err1 := eb.Fetcher(b.mb.NewModel(), id, ctx)
+	if err1 != nil {
+		ShowMessage(&r, err1.Error(), "warning")
+		return
+	}
+	if !tb.movable(obj, ctx) {
+		ShowMessage(&r, perm.PermissionDenied.Error(), "warning")
+		return
+	}
+	oldParentID := tb.parentID(obj)
+
+	err1 = dataOperatorTransaction(b.mb.p.dataOperator)(ctx, func(ctx *web.EventContext) (err error) {
+		if err = tb.setParent(obj, parentID); err != nil {
+			return
+		}
+		if vErr := eb.validate(obj, ctx); vErr.HaveErrors() {
+			return &vErr
+		}
+		saveCtx := ctx
+		if eb.versionField != "" {
+			if saveCtx, err = eb.lockVersion(obj, id, ctx); err != nil {
+				return
+			}
+		}
+		if tb.positionField == "" {
+			return eb.Save(obj, id, saveCtx)
+		}
+
+		siblings, err := tb.siblings(parentID, ctx)
+		if err != nil {
+			return
+		}
+		var others []interface{}
+		for _, s := range siblings {
+			if vx.ObjectID(s) != id {
+				others = append(others, s)
+			}
+		}
+		index, _ := strconv.Atoi(ctx.R.FormValue(ParamTreeIndex))
+		if index < 0 || index > len(others) {
+			index = len(others)
+		}
+		others = append(others[:index], append([]interface{}{obj}, others[index:]...)...)
+
+		for i, s := range others {
+			sid := vx.ObjectID(s)
+			if sid == id {
+				tb.setPosition(obj, i+1)
+				if err = eb.Save(obj, id, saveCtx); err != nil {
+					return
+				}
+				continue
+			}
+			old := tb.position(s)
+			tb.setPosition(s, i+1)
+			if tb.position(s) == old {
+				continue
+			}
+			if err = eb.Save(s, sid, ctx); err != nil {
+				return
+			}
+		}
+		return
+	})
+	if err1 != nil {
+		if errors.Is(err1, ErrTreeCycle) {
+			ShowMessage(&r, msgr.TreeCycleNotice, "warning")
+		} else {
+			ShowMessage(&r, err1.Error(), "warning")
+		}
+	}
+
+	// the children are reloaded even if the move fails, so that the dragged node is put back
+	for _, p := range []string{oldParentID, parentID} {
+		r.UpdatePortals = append(r.UpdatePortals, &web.PortalUpdate{
+			Name: treeChildrenPortalName(p),
+			Body: tb.childrenComponent(p, ctx),
+		})
+		if oldParentID == parentID {
+			break
+		}
+	}
+	if err1 == nil {
+		ShowMessage(&r, msgr.SuccessfullyUpdated, "")
+	}
+	return
+}
+
+// siblings searches all the children of the parent without the keyword and the filters of the listing,
+// so that the positions of the hidden siblings are kept.
+func (b *TreeBuilder) siblings(parentID string, ctx *web.EventContext) (objs []interface{}, err error) {
+	cond, err := b.childrenCondition(parentID)
+	if err != nil {
+		return
+	}
+	r, _, err := b.lb.Searcher(b.lb.mb.NewModelSlice(), &SearchParams{
+		PerPage:       layoutMaxRecords,
+		Page:          1,
+		OrderBy:       b.orderBy(),
+		PageURL:       ctx.R.URL,
+		SQLConditions: append(append([]*SQLCondition{}, b.lb.conditions...), cond),
+	}, ctx)
+	if err != nil {
+		return
+	}
+	return objectsOf(r), nil
+}
+
+// checkCycle returns ErrTreeCycle if the record is one of the ancestors of its parent.
+func (b *TreeBuilder) checkCycle(obj interface{}, ctx *web.EventContext) (err error) {
+	id := vx.ObjectID(obj)
+	p := b.parentID(obj)
+	for depth := 0; p != "" && depth < treeMaxDepth; depth++ {
+		if p == id {
+			return ErrTreeCycle
+		}
+		parent, err := b.lb.mb.editing.Fetcher(b.lb.mb.NewModel(), p, ctx)
+		if errors.Is(err, ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		p = b.parentID(parent)
+	}
+	return
+}
+
+type treeSelectItem struct {
+	Text  string `json:"text"`
+	Value string `json:"value"`
+	Depth int    `json:"depth"`
+}
+
+// treeSelectItems flattens all the records depth-first, the record and its descendants are excluded
+// since they can not be its parent.
+func (b *TreeBuilder) treeSelectItems(obj interface{}, ctx *web.EventContext) (items []treeSelectItem) {
+	r, _, err := b.lb.Searcher(b.lb.mb.NewModelSlice(), &SearchParams{
+		PerPage:       layoutMaxRecords,
+		Page:          1,
+		OrderBy:       b.orderBy(),
+		PageURL:       ctx.R.URL,
+		SQLConditions: b.lb.conditions,
+	}, ctx)
+	if err != nil {
+		panic(err)
+	}
+	objs := objectsOf(r)
+
+	ids := make(map[string]bool)
+	for _, o := range objs {
+		ids[vx.ObjectID(o)] = true
+	}
+	children := make(map[string][]interface{})
+	for _, o := range objs {
+		p := b.parentID(o)
+		// the records whose parents are not found are shown as the roots
+		if !ids[p] {
+			p = ""
+		}
+		children[p] = append(children[p], o)
+	}
+
+	exclude := ""
+	if obj != nil {
+		exclude = vx.ObjectID(obj)
+	}
+	var walk func(parentID string, depth int)
+	walk = func(parentID string, depth int) {
+		for _, o := range children[parentID] {
+			id := vx.ObjectID(o)
+			if id == exclude && id != "" {
+				continue
+			}
+			title := getPageTitle(o, id)
+			if b.titleFunc != nil {
+				title = b.titleFunc(o, ctx)
+			}
+			items = append(items, treeSelectItem{Text: title, Value: id, Depth: depth})
+			if depth < treeMaxDepth {
+				walk(id, depth+1)
+			}
+		}
+	}
+	walk("", 0)
+	return
+}
+
+// ParentSelectComponentFunc returns the component to pick the parent in the editing form, the candidates
+// are indented by their depth, the record and its descendants are not selectable.
+//
+//	tb := lb.Tree()
+//	eb.Field("ParentID").ComponentFunc(tb.ParentSelectComponentFunc()).SetterFunc(tb.ParentSetterFunc())
+func (b *TreeBuilder) ParentSelectComponentFunc() FieldComponentFunc {
+	return func(obj interface{}, field *FieldContext, ctx *web.EventContext) h.HTMLComponent {
+		return VAutocomplete(
+			h.Template(
+				h.Div(h.Text("{{item.text}}")).Attr(":style", "{paddingLeft: (item.depth * 16) + 'px'}"),
+			).Attr("v-slot:item", "{ item }"),
+		).
+			Items(b.treeSelectItems(obj, ctx)).
+			ItemText("text").
+			ItemValue("value").
+			FieldName(field.FormKey).
+			Label(field.Label).
+			Value(b.parentID(obj)).
+			Clearable(true).
+			ErrorMessages(field.Errors...).
+			Disabled(field.Disabled)
+	}
+}
+
+// ParentSetterFunc returns the setter of the parent picked by ParentSelectComponentFunc, the record is a root
+// if the parent is cleared.
+func (b *TreeBuilder) ParentSetterFunc() FieldSetterFunc {
+	return func(obj interface{}, field *FieldContext, ctx *web.EventContext) (err error) {
+		return b.setParent(obj, ctx.R.FormValue(field.FormKey))
+	}
+}
@@ -0,0 +1,151 @@
+package presets
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/qor5/web"
+)
+
+type treeItem struct {
+	ID       uint
+	Name     string
+	ParentID *uint
+	Position int
+}
+
+func (i *treeItem) PrimarySlug() string {
+	return fmt.Sprint(i.ID)
+}
+
+// treeItemsOperator searches the items by the parent conditions of the tree.
+type treeItemsOperator struct {
+	items map[uint]*treeItem
+}
+
+func (op *treeItemsOperator) Search(obj interface{}, params *SearchParams, ctx *web.EventContext) (r interface{}, totalCount int, err error) {
+	var items []*treeItem
+	for _, item := range op.items {
+		matched := true
+		for _, c := range params.SQLConditions {
+			switch {
+			case strings.HasSuffix(c.Query, "IS NULL"):
+				matched = matched && item.ParentID == nil
+			case strings.HasSuffix(c.Query, "= ?"):
+				matched = matched && item.ParentID != nil && *item.ParentID == c.Args[0].(uint)
+			case strings.HasSuffix(c.Query, "IN ?"):
+				in := false
+				for _, id := range c.Args[0].([]interface{}) {
+					in = in || item.ParentID != nil && *item.ParentID == id.(uint)
+				}
+				matched = matched && in
+			}
+		}
+		if matched {
+			v := *item
+			items = append(items, &v)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Position != items[j].Position {
+			return items[i].Position < items[j].Position
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items, len(items), nil
+}
+
+func (op *treeItemsOperator) Fetch(obj interface{}, id string, ctx *web.EventContext) (r interface{}, err error) {
+	for _, item := range op.items {
+		if fmt.Sprint(item.ID) == id {
+			v := *item
+			return &v, nil
+		}
+	}
+	return nil, ErrRecordNotFound
+}
+
+func (op *treeItemsOperator) Save(obj interface{}, id string, ctx *web.EventContext) (err error) {
+	item := obj.(*treeItem)
+	op.items[item.ID] = item
+	return
+}
+
+func (op *treeItemsOperator) Delete(obj interface{}, id string, ctx *web.EventContext) (err error) {
+	return
+}
+
+func TestTree(t *testing.T) {
+	one, two := uint(1), uint(2)
+	op := &treeItemsOperator{items: map[uint]*treeItem{
+		1: {ID: 1, Name: "A", Position: 1},
+		2: {ID: 2, Name: "B", ParentID: &one, Position: 1},
+		3: {ID: 3, Name: "C", ParentID: &two, Position: 1},
+		4: {ID: 4, Name: "D", ParentID: &one, Position: 2},
+	}}
+	b := New().DataOperator(op)
+	mb := b.Model(&treeItem{})
+	lb := mb.Listing()
+	tb := lb.Tree().PositionField("Position", "position")
+	mb.Editing("Name", "ParentID").Field("ParentID").
+		ComponentFunc(tb.ParentSelectComponentFunc()).
+		SetterFunc(tb.ParentSetterFunc())
+
+	newCtx := func(url string) *web.EventContext {
+		return &web.EventContext{R: httptest.NewRequest("POST", url, nil), W: httptest.NewRecorder()}
+	}
+
+	comp, ok := lb.layoutComponents(newCtx("/tree-items?presets_listing_layout=tree"))
+	if !ok {
+		t.Fatalf("the tree is not rendered")
+	}
+	content, _ := comp.MarshalHTML(context.TODO())
+	if !strings.Contains(string(content), "data-id='1'") || strings.Contains(string(content), "data-id='2'") {
+		t.Errorf("the roots are not rendered lazily: %s", content)
+	}
+
+	r, err := lb.loadTreeChildren(newCtx("/tree-items?__execute_event__=presets_LoadTreeChildren&presets_tree_parent_id=1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ = r.UpdatePortals[0].Body.MarshalHTML(context.TODO())
+	if !strings.Contains(string(content), "data-id='2'") || !strings.Contains(string(content), "data-id='4'") {
+		t.Errorf("the children of 1 are not loaded: %s", content)
+	}
+
+	// 4 is moved to the first child of 1
+	if _, err = lb.moveTreeNode(newCtx("/tree-items?__execute_event__=presets_MoveTreeNode&id=4&presets_tree_parent_id=1&presets_tree_index=0")); err != nil {
+		t.Fatal(err)
+	}
+	if op.items[4].Position != 1 || op.items[2].Position != 2 {
+		t.Errorf("positions = %d, %d", op.items[4].Position, op.items[2].Position)
+	}
+
+	// 1 can not be moved under its grandchild 3
+	if _, err = lb.moveTreeNode(newCtx("/tree-items?__execute_event__=presets_MoveTreeNode&id=1&presets_tree_parent_id=3&presets_tree_index=0")); err != nil {
+		t.Fatal(err)
+	}
+	if op.items[1].ParentID != nil {
+		t.Errorf("1 is moved under %d", *op.items[1].ParentID)
+	}
+
+	// 2 is moved to the root
+	if _, err = lb.moveTreeNode(newCtx("/tree-items?__execute_event__=presets_MoveTreeNode&id=2&presets_tree_index=1")); err != nil {
+		t.Fatal(err)
+	}
+	if op.items[2].ParentID != nil || op.items[1].Position != 1 || op.items[2].Position != 2 {
+		t.Errorf("2 is not moved to the root: %+v", op.items[2])
+	}
+
+	var values []string
+	for _, item := range tb.treeSelectItems(op.items[2], newCtx("/tree-items")) {
+		values = append(values, item.Value)
+	}
+	if strings.Join(values, ",") != "1,4" {
+		t.Errorf("the parents of 2 = %v", values)
+	}
+}
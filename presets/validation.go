@@ -0,0 +1,233 @@
+package presets
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/qor5/web"
+	"github.com/qor5/x/i18n"
+	"github.com/sunfmin/reflectutils"
+)
+
+// Validate sets the rules that the value of the field is validated by before the ValidateFunc, like "required,max=255,email".
+// The rules are separated by commas, and the supported rules are
+//
+//   - required: not the zero value, or not empty for slices and maps
+//   - min=n, max=n: the value of numbers, the length of strings, or the count of the items of slices and maps
+//   - len=n: the length of strings
+//   - email, url: the format of strings
+//   - oneof=a b c: one of the values that are separated by spaces
+//
+// The rules other than required are skipped for the empty values, except that min, max and oneof
+// are also checked for the zero numbers, so min=1 refuses 0.
+// The rules are also read from the `validate` struct tags, see EditingBuilder.StructTagValidation.
+func (b *FieldBuilder) Validate(rules string) (r *FieldBuilder) {
+	parseValidationRules(rules)
+	b.validation = rules
+	return b
+}
+
+// StructTagValidation validates the fields by the rules of their `validate` struct tags, like `validate:"required,max=255"`,
+// including the fields of the nested FieldsBuilder, the rules set by FieldBuilder.Validate take precedence over the tags.
+func (b *EditingBuilder) StructTagValidation(v bool) (r *EditingBuilder) {
+	b.structTagValidation = v
+	return b
+}
+
+type validationRule struct {
+	name  string
+	param string
+}
+
+func parseValidationRules(rules string) (r []validationRule) {
+	for _, s := range strings.Split(rules, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		name, param, _ := strings.Cut(s, "=")
+		switch name {
+		case "required", "email", "url", "oneof":
+		case "min", "max", "len":
+			if _, err := strconv.ParseFloat(param, 64); err != nil {
+				panic(fmt.Sprintf("validation rule %s: %s is not a number", s, param))
+			}
+		default:
+			panic(fmt.Sprintf("unknown validation rule %s", s))
+		}
+		r = append(r, validationRule{name: name, param: param})
+	}
+	return
+}
+
+// validate validates obj by the rules of the fields, then by the ValidateFunc if there is no errors.
+func (b *EditingBuilder) validate(obj interface{}, ctx *web.EventContext) (vErr web.ValidationErrors) {
+	b.FieldsBuilder.validateFields(b.mb.Info(), obj, "", b.structTagValidation, &vErr, ctx)
+	if vErr.HaveErrors() || b.Validator == nil {
+		return
+	}
+	return b.Validator(obj, ctx)
+}
+
+// validateFields adds the errors of the fields with the form keys, like "Permissions[0].Effect",
+// so that they are shown next to the inputs.
+func (b *FieldsBuilder) validateFields(info *ModelInfo, obj interface{}, parentFormKey string, tags bool, vErr *web.ValidationErrors, ctx *web.EventContext) {
+	t := indirectType(reflect.TypeOf(obj))
+	for _, f := range b.fields {
+		formKey := f.name
+		if parentFormKey != "" {
+			formKey = fmt.Sprintf("%s.%s", parentFormKey, f.name)
+		}
+		val, err := reflectutils.Get(obj, f.name)
+		if err != nil {
+			continue
+		}
+
+		rules := f.validation
+		if rules == "" && tags {
+			if sf, ok := t.FieldByName(f.name); ok {
+				rules = sf.Tag.Get("validate")
+			}
+		}
+		if rules != "" {
+			label := b.getLabel(f.NameLabel)
+			if info != nil {
+				label = i18n.PT(ctx.R, ModelsI18nModuleKey, info.Label(), label)
+			}
+			if msg := validateValue(val, parseValidationRules(rules), label, MustGetMessages(ctx.R)); msg != "" {
+				vErr.FieldError(formKey, msg)
+			}
+		}
+
+		if f.nestedFieldsBuilder == nil {
+			continue
+		}
+		rv := reflect.ValueOf(val)
+		switch rv.Kind() {
+		case reflect.Slice:
+			for i := 0; i < rv.Len(); i++ {
+				f.nestedFieldsBuilder.validateFields(info, rv.Index(i).Interface(), fmt.Sprintf("%s[%d]", formKey, i), tags, vErr, ctx)
+			}
+		case reflect.Ptr:
+			if !rv.IsNil() {
+				f.nestedFieldsBuilder.validateFields(info, val, formKey, tags, vErr, ctx)
+			}
+		case reflect.Struct:
+			f.nestedFieldsBuilder.validateFields(info, val, formKey, tags, vErr, ctx)
+		}
+	}
+}
+
+// validateValue returns the message of the first rule that the value breaks.
+func validateValue(val interface{}, rules []validationRule, label string, msgr *Messages) string {
+	rv := reflect.ValueOf(val)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			rv = reflect.Value{}
+			break
+		}
+		rv = rv.Elem()
+	}
+
+	empty := !rv.IsValid() || rv.IsZero()
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map {
+		empty = rv.Len() == 0
+	}
+	for _, rule := range rules {
+		if rule.name == "required" && empty {
+			return msgr.ValidationMessage(msgr.ValidationRequiredTemplate, label, "")
+		}
+	}
+	// the other rules are only for the values that are not empty, or the zero numbers
+	if empty && !isNumberKind(rv) {
+		return ""
+	}
+
+	for _, rule := range rules {
+		if empty && rule.name != "min" && rule.name != "max" && rule.name != "oneof" {
+			continue
+		}
+		n, _ := strconv.ParseFloat(rule.param, 64)
+		var template string
+		switch rule.name {
+		case "min", "max":
+			min := rule.name == "min"
+			switch rv.Kind() {
+			case reflect.String:
+				if l := float64(utf8.RuneCountInString(rv.String())); (min && l < n) || (!min && l > n) {
+					template = pick(min, msgr.ValidationMinLengthTemplate, msgr.ValidationMaxLengthTemplate)
+				}
+			case reflect.Slice, reflect.Map:
+				if l := float64(rv.Len()); (min && l < n) || (!min && l > n) {
+					template = pick(min, msgr.ValidationMinItemsTemplate, msgr.ValidationMaxItemsTemplate)
+				}
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				if v := float64(rv.Int()); (min && v < n) || (!min && v > n) {
+					template = pick(min, msgr.ValidationMinTemplate, msgr.ValidationMaxTemplate)
+				}
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				if v := float64(rv.Uint()); (min && v < n) || (!min && v > n) {
+					template = pick(min, msgr.ValidationMinTemplate, msgr.ValidationMaxTemplate)
+				}
+			case reflect.Float32, reflect.Float64:
+				if v := rv.Float(); (min && v < n) || (!min && v > n) {
+					template = pick(min, msgr.ValidationMinTemplate, msgr.ValidationMaxTemplate)
+				}
+			}
+		case "len":
+			if rv.Kind() == reflect.String && float64(utf8.RuneCountInString(rv.String())) != n {
+				template = msgr.ValidationLenTemplate
+			}
+		case "email":
+			s := fmt.Sprint(rv.Interface())
+			if a, err := mail.ParseAddress(s); err != nil || a.Address != s {
+				template = msgr.ValidationEmailTemplate
+			}
+		case "url":
+			if u, err := url.ParseRequestURI(fmt.Sprint(rv.Interface())); err != nil || u.Scheme == "" || u.Host == "" {
+				template = msgr.ValidationURLTemplate
+			}
+		case "oneof":
+			s := fmt.Sprint(rv.Interface())
+			found := false
+			for _, o := range strings.Fields(rule.param) {
+				if o == s {
+					found = true
+					break
+				}
+			}
+			if !found {
+				template = msgr.ValidationOneOfTemplate
+			}
+		}
+		if template != "" {
+			return msgr.ValidationMessage(template, label, rule.param)
+		}
+	}
+	return ""
+}
+
+func isNumberKind(rv reflect.Value) bool {
+	if !rv.IsValid() {
+		return false
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func pick(cond bool, a string, b string) string {
+	if cond {
+		return a
+	}
+	return b
+}
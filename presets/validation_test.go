@@ -0,0 +1,63 @@
+package presets
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/qor5/web"
+)
+
+type validationItem struct {
+	Effect string `validate:"required,oneof=allow deny"`
+	Count  int    `validate:"min=1"`
+}
+
+type validationModel struct {
+	ID    uint
+	Name  string `validate:"required,max=5"`
+	Email string `validate:"email"`
+	Note  string
+	Items []*validationItem
+}
+
+func TestValidation(t *testing.T) {
+	b := New()
+	mb := b.Model(&validationModel{})
+	eb := mb.Editing("Name", "Email", "Note", "Items").StructTagValidation(true)
+	eb.Field("Note").Validate("len=2")
+	eb.Field("Items").Nested(b.NewFieldsBuilder(WRITE).Model(&validationItem{}).Only("Effect", "Count"))
+	eb.ValidateFunc(func(obj interface{}, ctx *web.EventContext) (err web.ValidationErrors) {
+		err.GlobalError("validate func")
+		return
+	})
+
+	ctx := &web.EventContext{R: httptest.NewRequest("POST", "/validation-models", nil), W: httptest.NewRecorder()}
+	vErr := eb.validate(&validationModel{
+		Name:  "Felix the cat",
+		Email: "felix",
+		Note:  "x",
+		Items: []*validationItem{{Effect: "allow", Count: 1}, {Effect: "maybe"}},
+	}, ctx)
+
+	for key, excepted := range map[string][]string{
+		"Name":            {"Name must be at most 5 characters"},
+		"Email":           {"Email must be a valid email address"},
+		"Note":            {"Note must be 2 characters"},
+		"Items[0].Effect": nil,
+		"Items[1].Effect": {"Effect must be one of allow deny"},
+		"Items[1].Count":  {"Count must be at least 1"},
+	} {
+		if errs := vErr.GetFieldErrors(key); !reflect.DeepEqual(errs, excepted) {
+			t.Errorf("%s errors = %v, excepted %v", key, errs, excepted)
+		}
+	}
+	if vErr.GetGlobalError() != "" {
+		t.Errorf("the validate func is called with the field errors")
+	}
+
+	vErr = eb.validate(&validationModel{Name: "Felix", Note: "ok", Items: []*validationItem{{Effect: "deny", Count: 2}}}, ctx)
+	if vErr.GetGlobalError() != "validate func" || len(vErr.GetFieldErrors("Name")) > 0 {
+		t.Errorf("errors = %v", vErr.Error())
+	}
+}
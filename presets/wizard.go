@@ -0,0 +1,241 @@
+package presets
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/qor5/admin/presets/actions"
+	. "github.com/qor5/ui/vuetify"
+	"github.com/qor5/web"
+	"github.com/qor5/x/perm"
+	h "github.com/theplant/htmlgo"
+)
+
+type wizardStep struct {
+	title  string
+	fields *FieldsBuilder
+}
+
+// Wizard turns the creating form into the steps of its FieldsSections, the fields before the first section
+// are in the first step, and the other fields are in the step of the section before them.
+// The fields of all the steps stay in the form while only the current one is shown, so the values are kept
+// when moving back and forth, and the record is saved by the Saver only at the last step.
+//
+//	mb.Editing().Creating(
+//		&presets.FieldsSection{Title: "Basic", Rows: [][]string{{"Name", "Code"}}},
+//		&presets.FieldsSection{Title: "Variants", Rows: [][]string{{"Variants"}}},
+//	).Wizard().StepValidateFunc("Basic", validateBasic)
+func (b *EditingBuilder) Wizard() (r *EditingBuilder) {
+	if !b.wizard {
+		b.mb.RegisterEventFunc(actions.WizardStep, b.wizardStepTo)
+	}
+	b.wizard = true
+	return b
+}
+
+// StepValidateFunc validates the record when moving forward from the step of the section title,
+// and again with the other steps before the record is saved. The rules of the fields of the step are checked before it.
+func (b *EditingBuilder) StepValidateFunc(title string, v ValidateFunc) (r *EditingBuilder) {
+	if b.wizardValidators == nil {
+		b.wizardValidators = make(map[string]ValidateFunc)
+	}
+	b.wizardValidators[title] = v
+	return b
+}
+
+func (b *EditingBuilder) wizardSteps() (steps []*wizardStep) {
+	type group struct {
+		title   string
+		section bool
+		items   []interface{}
+	}
+	var groups []*group
+	for _, iv := range b.fieldsLayout {
+		s, isSection := iv.(*FieldsSection)
+		if len(groups) == 0 || isSection && groups[len(groups)-1].section {
+			groups = append(groups, &group{})
+		}
+		g := groups[len(groups)-1]
+		if isSection {
+			g.title = s.Title
+			g.section = true
+		}
+		g.items = append(g.items, iv)
+	}
+
+	// the fields that are not in the layout are in the last step
+	inLayout := make(map[string]bool)
+	for _, n := range b.getFieldNamesFromLayout() {
+		inLayout[n] = true
+	}
+	for _, f := range b.fields {
+		if inLayout[f.name] {
+			continue
+		}
+		if len(groups) == 0 {
+			groups = append(groups, &group{})
+		}
+		g := groups[len(groups)-1]
+		g.items = append(g.items, f.name)
+	}
+
+	for _, g := range groups {
+		steps = append(steps, &wizardStep{title: g.title, fields: b.FieldsBuilder.Only(g.items...)})
+	}
+	return
+}
+
+type wizardStepKey struct{}
+
+func withWizardStep(r *http.Request, step int) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), wizardStepKey{}, step))
+}
+
+// currentWizardStep returns the step of the form, or the first step with the errors of the fields.
+func (b *EditingBuilder) currentWizardStep(steps []*wizardStep, ctx *web.EventContext) (current int) {
+	if vErr, ok := ctx.Flash.(*web.ValidationErrors); ok {
+		for i, s := range steps {
+			for _, f := range s.fields.fields {
+				if len(vErr.GetFieldErrors(f.name)) > 0 {
+					return i
+				}
+			}
+		}
+	}
+
+	current, ok := ctx.R.Context().Value(wizardStepKey{}).(int)
+	if !ok {
+		current, _ = strconv.Atoi(ctx.R.FormValue(ParamWizardStep))
+	}
+	if current < 0 || current >= len(steps) {
+		current = 0
+	}
+	return
+}
+
+// wizardComponents renders the steps of the creating form and the buttons to move between them,
+// submit is the button of the last step.
+func (b *EditingBuilder) wizardComponents(obj interface{}, submit h.HTMLComponent, ctx *web.EventContext) (form h.HTMLComponent, buttons h.HTMLComponent) {
+	msgr := MustGetMessages(ctx.R)
+	steps := b.wizardSteps()
+	current := b.currentWizardStep(steps, ctx)
+
+	var headers []h.HTMLComponent
+	modifiedIndexes := ContextModifiedIndexesBuilder(ctx)
+	comps := []h.HTMLComponent{
+		modifiedIndexes.ToFormHidden(),
+		h.Input("").Type("hidden").Value(strconv.Itoa(current)).Attr(web.VFieldName(ParamWizardStep)...),
+	}
+	for i, s := range steps {
+		if i > 0 {
+			headers = append(headers, VDivider())
+		}
+		headers = append(headers, VStepperStep(h.Text(s.title)).
+			Step(i+1).
+			Complete(i < current))
+
+		comp := h.Div(s.fields.toComponentWithFormValueKey(b.mb.Info(), obj, "", modifiedIndexes, ctx))
+		if i != current {
+			comp.Style("display: none;")
+		}
+		comps = append(comps, comp)
+	}
+
+	stepTo := func(target int) string {
+		return web.Plaid().
+			EventFunc(actions.WizardStep).
+			Queries(ctx.Queries()).
+			Query(ParamWizardTarget, strconv.Itoa(target)).
+			URL(b.mb.Info().ListingHref()).
+			Go()
+	}
+	var back, next h.HTMLComponent
+	if current > 0 {
+		back = VBtn(msgr.WizardBack).Text(true).Attr("@click", stepTo(current-1))
+	}
+	next = submit
+	if current < len(steps)-1 {
+		next = VBtn(msgr.WizardNext).
+			Color("primary").
+			Attr("@click", stepTo(current+1)).
+			Attr(":disabled", "isFetching").
+			Attr(":loading", "isFetching")
+	}
+
+	form = h.Components(
+		VStepper(VStepperHeader(headers...)).Attr(":value", current+1).Elevation(0).Class("mb-4"),
+		h.Components(comps...),
+	)
+	buttons = h.Components(back, VSpacer(), next)
+	return
+}
+
+// validateWizardStep checks the rules of the fields of the step, then its StepValidateFunc.
+func (b *EditingBuilder) validateWizardStep(s *wizardStep, obj interface{}, ctx *web.EventContext) (vErr web.ValidationErrors) {
+	s.fields.validateFields(b.mb.Info(), obj, "", b.structTagValidation, &vErr, ctx)
+	if vErr.HaveErrors() {
+		return
+	}
+	if v := b.wizardValidators[s.title]; v != nil {
+		return v(obj, ctx)
+	}
+	return
+}
+
+// validateWizardSteps runs the StepValidateFunc of all the steps before the record is saved.
+func (b *EditingBuilder) validateWizardSteps(obj interface{}, ctx *web.EventContext) (vErr web.ValidationErrors) {
+	for _, s := range b.wizardSteps() {
+		if v := b.wizardValidators[s.title]; v != nil {
+			if vErr = v(obj, ctx); vErr.HaveErrors() {
+				return
+			}
+		}
+	}
+	return
+}
+
+// wizardStepTo moves the creating form to the target step, moving forward only goes to the next step
+// after the current one is valid.
+func (b *EditingBuilder) wizardStepTo(ctx *web.EventContext) (r web.EventResponse, err error) {
+	if b.mb.Info().Verifier().Do(PermCreate).WithReq(ctx.R).IsAllowed() != nil {
+		ShowMessage(&r, perm.PermissionDenied.Error(), "warning")
+		return
+	}
+
+	steps := b.wizardSteps()
+	if len(steps) == 0 {
+		return
+	}
+	current, _ := strconv.Atoi(ctx.R.FormValue(ParamWizardStep))
+	if current < 0 || current >= len(steps) {
+		current = 0
+	}
+	target, _ := strconv.Atoi(ctx.R.FormValue(ParamWizardTarget))
+	if target < 0 {
+		target = 0
+	}
+
+	// the deleted items of the lists are kept, so that they stay deleted by the hidden field of the form
+	obj, vErr := b.FetchAndUnmarshal("", false, ctx)
+	if target > current {
+		target = current + 1
+		if !vErr.HaveErrors() {
+			vErr = b.validateWizardStep(steps[current], obj, ctx)
+		}
+		if vErr.HaveErrors() {
+			target = current
+		}
+	}
+	if target >= len(steps) {
+		target = len(steps) - 1
+	}
+
+	ctx.R = withWizardStep(ctx.R, target)
+	if vErr.HaveErrors() {
+		b.UpdateOverlayContent(ctx, &r, obj, "", &vErr)
+		return
+	}
+	b.UpdateOverlayContent(ctx, &r, obj, "", nil)
+	return
+}
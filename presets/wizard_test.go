@@ -0,0 +1,72 @@
+package presets
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qor5/web"
+)
+
+func TestWizard(t *testing.T) {
+	op := &restAPIItemsOperator{items: map[string]*restAPIItem{}}
+	b := New().DataOperator(op)
+	eb := b.Model(&restAPIItem{}).Editing("Name", "Price", "Note").Creating(
+		&FieldsSection{Title: "Basic", Rows: [][]string{{"Name"}}},
+		&FieldsSection{Title: "Details", Rows: [][]string{{"Price", "Note"}}},
+	).Wizard().StepValidateFunc("Basic", func(obj interface{}, ctx *web.EventContext) (err web.ValidationErrors) {
+		if obj.(*restAPIItem).Name == "" {
+			err.FieldError("Name", "Name is required")
+		}
+		return
+	})
+
+	if steps := eb.wizardSteps(); len(steps) != 2 || steps[1].title != "Details" || steps[1].fields.GetField("Note") == nil {
+		t.Fatalf("steps = %v", steps)
+	}
+
+	newCtx := func(event string, values map[string]string) *web.EventContext {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		for k, v := range values {
+			mw.WriteField(k, v)
+		}
+		mw.Close()
+		r := httptest.NewRequest("POST", "/rest-api-items?__execute_event__="+event, &body)
+		r.Header.Set("Content-Type", mw.FormDataContentType())
+		return &web.EventContext{R: r, W: httptest.NewRecorder()}
+	}
+	stepTo := func(values map[string]string) string {
+		r, err := eb.wizardStepTo(newCtx("presets_WizardStep", values))
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := r.UpdatePortals[0].Body.MarshalHTML(context.TODO())
+		return string(content)
+	}
+
+	content := stepTo(map[string]string{ParamWizardStep: "0", ParamWizardTarget: "1"})
+	if !strings.Contains(content, "Name is required") || !strings.Contains(content, "value='0'") {
+		t.Errorf("the invalid step is passed: %s", content)
+	}
+	content = stepTo(map[string]string{ParamWizardStep: "0", ParamWizardTarget: "1", "Name": "A"})
+	if !strings.Contains(content, "value='1'") || !strings.Contains(content, `:value='"A"'`) {
+		t.Errorf("the next step is not shown: %s", content)
+	}
+	if len(op.items) != 0 {
+		t.Errorf("the record is saved before the last step")
+	}
+
+	if err := eb.doUpdate(newCtx("presets_Update", map[string]string{ParamWizardStep: "1", "Price": "3"}), &web.EventResponse{}, false); err == nil || len(op.items) != 0 {
+		t.Errorf("the record is saved without the validation of the first step: %v", err)
+	}
+	if err := eb.doUpdate(newCtx("presets_Update", map[string]string{ParamWizardStep: "1", "Name": "A", "Price": "3"}), &web.EventResponse{}, false); err != nil {
+		t.Fatal(err)
+	}
+	if item := op.items["3"]; item == nil || item.Name != "A" || item.Price != 3 {
+		t.Errorf("saved = %+v", item)
+	}
+}
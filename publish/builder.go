@@ -60,15 +60,16 @@ func (b *Builder) Context() context.Context {
 
 // 幂等
 func (b *Builder) Publish(record interface{}) (err error) {
+	storage := b.storageOf(record)
 	err = utils.Transact(b.db, func(tx *gorm.DB) (err error) {
 		// publish content
 		if r, ok := record.(PublishInterface); ok {
 			var objs []*PublishAction
-			objs, err = r.GetPublishActions(b.db, b.context, b.storage)
+			objs, err = r.GetPublishActions(b.db, b.context, storage)
 			if err != nil {
 				return
 			}
-			if err = UploadOrDelete(objs, b.storage); err != nil {
+			if err = UploadOrDelete(objs, storage); err != nil {
 				return
 			}
 		}
@@ -124,7 +125,7 @@ func (b *Builder) Publish(record interface{}) (err error) {
 
 		// publish callback
 		if r, ok := record.(AfterPublishInterface); ok {
-			if err = r.AfterPublish(b.db, b.storage, b.context); err != nil {
+			if err = r.AfterPublish(b.db, storage, b.context); err != nil {
 				return
 			}
 		}
@@ -134,15 +135,16 @@ func (b *Builder) Publish(record interface{}) (err error) {
 }
 
 func (b *Builder) UnPublish(record interface{}) (err error) {
+	storage := b.storageOf(record)
 	err = utils.Transact(b.db, func(tx *gorm.DB) (err error) {
 		// unpublish content
 		if r, ok := record.(UnPublishInterface); ok {
 			var objs []*PublishAction
-			objs, err = r.GetUnPublishActions(b.db, b.context, b.storage)
+			objs, err = r.GetUnPublishActions(b.db, b.context, storage)
 			if err != nil {
 				return
 			}
-			if err = UploadOrDelete(objs, b.storage); err != nil {
+			if err = UploadOrDelete(objs, storage); err != nil {
 				return
 			}
 		}
@@ -168,7 +170,7 @@ func (b *Builder) UnPublish(record interface{}) (err error) {
 
 		// unpublish callback
 		if r, ok := record.(AfterUnPublishInterface); ok {
-			if err = r.AfterUnPublish(b.db, b.storage, b.context); err != nil {
+			if err = r.AfterUnPublish(b.db, storage, b.context); err != nil {
 				return
 			}
 		}
@@ -0,0 +1,47 @@
+package publish
+
+import (
+	"io"
+	"os"
+
+	"github.com/qor/oss"
+	"github.com/qor5/admin/presets"
+)
+
+// storageOf returns the storage of the record, the files of the presets.TenantScoped records are put into
+// the directories of their tenants, see presets.TenantPath.
+func (b *Builder) storageOf(record interface{}) oss.StorageInterface {
+	if tenant := presets.TenantOf(record); tenant != "" {
+		return &tenantStorage{StorageInterface: b.storage, tenant: tenant}
+	}
+	return b.storage
+}
+
+type tenantStorage struct {
+	oss.StorageInterface
+	tenant string
+}
+
+func (s *tenantStorage) Get(path string) (*os.File, error) {
+	return s.StorageInterface.Get(presets.TenantPath(s.tenant, path))
+}
+
+func (s *tenantStorage) GetStream(path string) (io.ReadCloser, error) {
+	return s.StorageInterface.GetStream(presets.TenantPath(s.tenant, path))
+}
+
+func (s *tenantStorage) Put(path string, reader io.Reader) (*oss.Object, error) {
+	return s.StorageInterface.Put(presets.TenantPath(s.tenant, path), reader)
+}
+
+func (s *tenantStorage) Delete(path string) error {
+	return s.StorageInterface.Delete(presets.TenantPath(s.tenant, path))
+}
+
+func (s *tenantStorage) List(path string) ([]*oss.Object, error) {
+	return s.StorageInterface.List(presets.TenantPath(s.tenant, path))
+}
+
+func (s *tenantStorage) GetURL(path string) (string, error) {
+	return s.StorageInterface.GetURL(presets.TenantPath(s.tenant, path))
+}
@@ -1,6 +1,7 @@
 package views
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -8,6 +9,7 @@ import (
 	"github.com/qor5/admin/activity"
 	"github.com/qor5/admin/presets"
 	"github.com/qor5/admin/presets/actions"
+	"github.com/qor5/admin/presets/gorm2op"
 	"github.com/qor5/admin/publish"
 	. "github.com/qor5/ui/vuetify"
 	"github.com/qor5/web"
@@ -20,6 +22,14 @@ import (
 
 const I18nPublishKey i18n.ModuleKey = "I18nPublishKey"
 
+// dbFromContext returns the transaction of the presets change, so that the changes are rolled back with it.
+func dbFromContext(db *gorm.DB, ctx *web.EventContext) *gorm.DB {
+	if tx, ok := gorm2op.TxFromContext(ctx.R.Context()); ok {
+		return tx
+	}
+	return db
+}
+
 func Configure(b *presets.Builder, db *gorm.DB, ab *activity.ActivityBuilder, publisher *publish.Builder, models ...*presets.ModelBuilder) {
 	for _, m := range models {
 		obj := m.NewModel()
@@ -61,6 +71,34 @@ func Configure(b *presets.Builder, db *gorm.DB, ab *activity.ActivityBuilder, pu
 				return searcher(model, params, ctx)
 			})
 
+			// the other deleted versions of the record are restored with the restored version
+			m.Hook(presets.AfterRestore, 0, func(old interface{}, obj interface{}, ctx *web.EventContext) (err error) {
+				err = eachDeletedVersion(dbFromContext(db, ctx), mb, obj.(presets.SlugEncoder).PrimarySlug(), func(versionID string) error {
+					return mb.Listing().Trash().Restorer(mb.NewModel(), versionID, ctx)
+				})
+				if errors.Is(err, presets.ErrRecordNotFound) {
+					return nil
+				}
+				return
+			})
+
+			// the trash lists the latest deleted versions, and deletes all the deleted versions of the record
+			if m.Listing().HasTrash() {
+				trash := m.Listing().Trash()
+				trashSearcher := trash.Searcher
+				trash.SearchFunc(func(model interface{}, params *presets.SearchParams, ctx *web.EventContext) (r interface{}, totalCount int, err error) {
+					params.SQLConditions = append(params.SQLConditions, latestDeletedVersionCondition(db, mb))
+					return trashSearcher(model, params, ctx)
+				})
+
+				deleter := trash.PermanentDeleter
+				trash.DeletePermanentlyFunc(func(obj interface{}, id string, ctx *web.EventContext) (err error) {
+					return eachDeletedVersion(dbFromContext(db, ctx), mb, id, func(versionID string) error {
+						return deleter(mb.NewModel(), versionID, ctx)
+					})
+				})
+			}
+
 			// listing-delete deletes all versions
 			{
 				// rewrite Delete row menu item to show correct id in prompt message
@@ -101,11 +139,11 @@ func Configure(b *presets.Builder, db *gorm.DB, ab *activity.ActivityBuilder, pu
 						VListItemTitle(h.Text(msgr.Delete)),
 					).Attr("@click", onclick.Go())
 				})
-				// rewrite Deleter to ignore version condition
+				// rewrite Deleter to ignore version condition, the delete runs in the transaction of the hooks
 				m.Editing().DeleteFunc(func(obj interface{}, id string, ctx *web.EventContext) (err error) {
 					allVersions := ctx.R.URL.Query().Get("all_versions") == "true"
 
-					wh := db.Model(obj)
+					wh := dbFromContext(db, ctx).Model(obj)
 
 					if id != "" {
 						if slugger, ok := obj.(presets.SlugDecoder); ok {
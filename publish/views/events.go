@@ -58,6 +58,9 @@ func publishAction(db *gorm.DB, mb *presets.ModelBuilder, publisher *publish.Bui
 		if err != nil {
 			return
 		}
+		if err = mb.RunHooks(presets.AfterPublish, nil, obj, ctx); err != nil {
+			return
+		}
 		if ab != nil {
 			if _, exist := ab.GetModelBuilder(obj); exist {
 				ab.AddCustomizedRecord(actionName, false, ctx.R.Context(), obj)
@@ -88,6 +91,9 @@ func unpublishAction(db *gorm.DB, mb *presets.ModelBuilder, publisher *publish.B
 		if err != nil {
 			return
 		}
+		if err = mb.RunHooks(presets.AfterUnpublish, nil, obj, ctx); err != nil {
+			return
+		}
 		if ab != nil {
 			if _, exist := ab.GetModelBuilder(obj); exist {
 				ab.AddCustomizedRecord(actionName, false, ctx.R.Context(), obj)
@@ -116,7 +122,7 @@ func renameVersionAction(db *gorm.DB, mb *presets.ModelBuilder, publisher *publi
 			return
 		}
 
-		if err = mb.Editing().Saver(obj, paramID, ctx); err != nil {
+		if err = mb.Editing().Save(obj, paramID, ctx); err != nil {
 			return
 		}
 
@@ -275,7 +275,8 @@ func saveNewVersionAction(db *gorm.DB, mb *presets.ModelBuilder, publisher *publ
 			}
 		}
 
-		if err = me.Saver(toObj, paramID, ctx); err != nil {
+		// the new version is created, so that the hooks like activity record it as a new record
+		if err = me.Save(toObj, "", ctx); err != nil {
 			me.UpdateOverlayContent(ctx, &r, toObj, "", err)
 			return
 		}
@@ -336,7 +337,8 @@ func duplicateVersionAction(db *gorm.DB, mb *presets.ModelBuilder, publisher *pu
 			}
 		}
 
-		if err = me.Saver(toObj, paramID, ctx); err != nil {
+		// the new version is created, so that the hooks like activity record it as a new record
+		if err = me.Save(toObj, "", ctx); err != nil {
 			presets.ShowMessage(&r, err.Error(), "error")
 			return
 		}
@@ -421,6 +423,47 @@ func searcher(db *gorm.DB, mb *presets.ModelBuilder) presets.SearchFunc {
 	}
 }
 
+// latestDeletedVersionCondition filters the latest deleted version of each record for the trash.
+func latestDeletedVersionCondition(db *gorm.DB, mb *presets.ModelBuilder) *presets.SQLCondition {
+	stmt := &gorm.Statement{DB: db}
+	stmt.Parse(mb.NewModel())
+	tn := stmt.Schema.Table
+
+	var pks []string
+	for _, f := range stmt.Schema.PrimaryFields {
+		if f.Name != "Version" {
+			pks = append(pks, f.DBName)
+		}
+	}
+	pkc := strings.Join(pks, ",")
+	return &presets.SQLCondition{
+		Query: fmt.Sprintf("(%v,version) IN (SELECT %v, MAX(version) FROM %v WHERE deleted_at IS NOT NULL GROUP BY %v)", pkc, pkc, tn, pkc),
+	}
+}
+
+// eachDeletedVersion calls f with the slug of every deleted version of the record,
+// so that the trash restores or deletes the versions one by one. The versions are changed by the Restorer and
+// the PermanentDeleter directly without the hooks, so the activity is recorded only for the version in the trash.
+func eachDeletedVersion(db *gorm.DB, mb *presets.ModelBuilder, id string, f func(versionID string) error) (err error) {
+	objs := mb.NewModelSlice()
+	if err = utils.PrimarySluggerWhere(db.Unscoped(), mb.NewModel(), id, "version").
+		Where("deleted_at IS NOT NULL").
+		Find(objs).Error; err != nil {
+		return
+	}
+
+	vs := reflect.ValueOf(objs).Elem()
+	if vs.Len() == 0 {
+		return presets.ErrRecordNotFound
+	}
+	for i := 0; i < vs.Len(); i++ {
+		if err = f(vs.Index(i).Interface().(presets.SlugEncoder).PrimarySlug()); err != nil {
+			return
+		}
+	}
+	return
+}
+
 func versionActionsFunc(m *presets.ModelBuilder) presets.ObjectComponentFunc {
 	return func(obj interface{}, ctx *web.EventContext) h.HTMLComponent {
 		gmsgr := presets.MustGetMessages(ctx.R)
@@ -1,6 +1,7 @@
 package role
 
 import (
+	"fmt"
 	"net/http"
 	"time"
 
@@ -92,9 +93,13 @@ func (b *Builder) Configure(pb *presets.Builder) *presets.ModelBuilder {
 		"Name",
 		"Permissions",
 	)
+	ed.Field("Name").Validate("required")
 
 	permFb := pb.NewFieldsBuilder(presets.WRITE).Model(&perm.DefaultDBPolicy{}).Only("Effect", "Actions", "Resources")
 	ed.Field("Permissions").Nested(permFb)
+	permFb.Field("Effect").Validate(fmt.Sprintf("required,oneof=%s %s", perm.Allowed, perm.Denied))
+	permFb.Field("Actions").Validate("required")
+	permFb.Field("Resources").Validate("required")
 
 	permFb.Field("Effect").ComponentFunc(func(obj interface{}, field *presets.FieldContext, ctx *web.EventContext) h.HTMLComponent {
 		return vuetify.VSelect().
@@ -135,10 +140,6 @@ func (b *Builder) Configure(pb *presets.Builder) *presets.ModelBuilder {
 
 	ed.ValidateFunc(func(obj interface{}, ctx *web.EventContext) (err web.ValidationErrors) {
 		u := obj.(*Role)
-		if u.Name == "" {
-			err.FieldError("Name", "Name is required")
-			return
-		}
 		for _, p := range u.Permissions {
 			p.Subject = u.Name
 		}
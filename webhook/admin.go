@@ -0,0 +1,192 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/qor5/admin/presets"
+	"github.com/qor5/admin/presets/actions"
+	"github.com/qor5/ui/vuetify"
+	"github.com/qor5/web"
+	h "github.com/theplant/htmlgo"
+	"golang.org/x/text/language"
+)
+
+// Configure adds the endpoints to the admin, and the log of the deliveries with the "Redeliver" item of the row menu.
+func (b *Builder) Configure(pb *presets.Builder) {
+	pb.I18n().
+		RegisterForModule(language.English, I18nWebhookKey, Messages_en_US).
+		RegisterForModule(language.SimplifiedChinese, I18nWebhookKey, Messages_zh_CN).
+		RegisterForModule(language.Japanese, I18nWebhookKey, Messages_ja_JP)
+
+	b.configureEndpoints(pb)
+	b.configureDeliveries(pb)
+}
+
+func (b *Builder) configureEndpoints(pb *presets.Builder) {
+	mb := pb.Model(&WebhookEndpoint{}).
+		Label("Webhooks").
+		URIName("webhooks").
+		MenuIcon("webhook")
+
+	lb := mb.Listing("ID", "Name", "URL", "Models", "Events", "Active")
+	lb.RowMenu().RowMenuItem("Send Test").
+		Icon("send").
+		PermAction(presets.PermUpdate).
+		OnClick(func(ctx *web.EventContext, id string) (r web.EventResponse, err error) {
+			epID, err := strconv.ParseUint(id, 10, 64)
+			if err != nil {
+				return
+			}
+			d, err := b.Ping(ctx.R.Context(), uint(epID))
+			if err != nil {
+				return
+			}
+			// the result is known if the delivery is sent in the request
+			if b.wb == nil || b.testMode {
+				if err = b.db.First(d, d.ID).Error; err != nil {
+					return
+				}
+				if d.Status != DeliveryStatusSucceeded {
+					presets.ShowMessage(&r, d.Error, "error")
+					return
+				}
+			}
+			presets.ShowMessage(&r, getMessages(ctx.R).SuccessfullySentTest, "")
+			return
+		})
+
+	ed := mb.Editing("Name", "URL", "Secret", "Models", "Events", "Active")
+	ed.Field("Models").
+		ComponentFunc(multiSelect(func() []string { return b.models }, func(msgr *Messages) string { return msgr.AllModels })).
+		SetterFunc(setJoined)
+	ed.Field("Events").
+		ComponentFunc(multiSelect(func() []string { return Events }, func(msgr *Messages) string { return msgr.AllEvents })).
+		SetterFunc(setJoined)
+	ed.Field("Secret").
+		ComponentFunc(secretField).
+		SetterFunc(setSecret)
+	ed.ValidateFunc(func(obj interface{}, ctx *web.EventContext) (err web.ValidationErrors) {
+		ep := obj.(*WebhookEndpoint)
+		if u, pErr := url.Parse(ep.URL); pErr != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			err.FieldError("URL", getMessages(ctx.R).InvalidURL)
+		}
+		return
+	})
+
+	// the secret is generated for the new endpoints that leave it empty, like the ones created without the form
+	mb.Hook(presets.BeforeSave, 0, func(old interface{}, obj interface{}, ctx *web.EventContext) (err error) {
+		ep := obj.(*WebhookEndpoint)
+		if ep.Secret != "" {
+			return
+		}
+		bs := make([]byte, 32)
+		if _, err = rand.Read(bs); err != nil {
+			return
+		}
+		ep.Secret = hex.EncodeToString(bs)
+		return
+	})
+}
+
+func (b *Builder) configureDeliveries(pb *presets.Builder) {
+	mb := pb.Model(&WebhookDelivery{}).
+		Label("Webhook Deliveries").
+		URIName("webhook-deliveries").
+		MenuIcon("outbox")
+
+	lb := mb.Listing("ID", "CreatedAt", "EndpointID", "Event", "ModelName", "RecordID", "Status", "Attempts", "ResponseCode", "Error").
+		OrderBy("id DESC").
+		NewButtonFunc(func(ctx *web.EventContext) h.HTMLComponent {
+			return nil
+		})
+	lb.RowMenu("Redeliver").RowMenuItem("Redeliver").
+		Icon("replay").
+		PermAction(presets.PermUpdate).
+		OnClick(func(ctx *web.EventContext, id string) (r web.EventResponse, err error) {
+			dID, err := strconv.ParseUint(id, 10, 64)
+			if err != nil {
+				return
+			}
+			if _, err = b.Redeliver(ctx.R.Context(), uint(dID)); err != nil {
+				return
+			}
+			presets.ShowMessage(&r, getMessages(ctx.R).SuccessfullyRedelivered, "")
+			web.AppendVarsScripts(&r, web.Plaid().EventFunc(actions.ReloadList).Go())
+			return
+		})
+
+	mb.Detailing("ID", "CreatedAt", "EndpointID", "Event", "ModelName", "RecordID", "Status", "Attempts",
+		"NextRetryAt", "DeliveredAt", "ResponseCode", "ResponseBody", "Error", "Payload")
+}
+
+func multiSelect(items func() []string, hint func(msgr *Messages) string) presets.FieldComponentFunc {
+	return func(obj interface{}, field *presets.FieldContext, ctx *web.EventContext) h.HTMLComponent {
+		var value []string
+		if s := field.StringValue(obj); s != "" {
+			value = strings.Split(s, ",")
+		}
+		return vuetify.VAutocomplete().
+			Value(value).
+			Label(field.Label).
+			Hint(hint(getMessages(ctx.R))).
+			PersistentHint(true).
+			FieldName(field.FormKey).
+			Multiple(true).Chips(true).DeletableChips(true).
+			Items(items())
+	}
+}
+
+// secretField shows the generated secret of the new endpoint to be copied, and masks the secret
+// once the endpoint is created, so that it is not sent to the users who can view the endpoints.
+func secretField(obj interface{}, field *presets.FieldContext, ctx *web.EventContext) h.HTMLComponent {
+	msgr := getMessages(ctx.R)
+	ep := obj.(*WebhookEndpoint)
+	tf := vuetify.VTextField().
+		Label(field.Label).
+		FieldName(field.FormKey).
+		PersistentHint(true).
+		ErrorMessages(field.Errors...)
+	if ep.ID == 0 {
+		secret := ep.Secret
+		if secret == "" {
+			bs := make([]byte, 32)
+			if _, err := rand.Read(bs); err == nil {
+				secret = hex.EncodeToString(bs)
+			}
+		}
+		return tf.Value(secret).Hint(msgr.SecretCopyHint)
+	}
+	return tf.Type("password").
+		Placeholder(maskSecret(ep.Secret)).
+		Hint(msgr.SecretKeepHint)
+}
+
+func maskSecret(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("•", len(secret))
+	}
+	return strings.Repeat("•", 8) + secret[len(secret)-4:]
+}
+
+// setSecret keeps the secret of the endpoint if the input is left empty.
+func setSecret(obj interface{}, field *presets.FieldContext, ctx *web.EventContext) (err error) {
+	if v := ctx.R.FormValue(field.FormKey); v != "" {
+		obj.(*WebhookEndpoint).Secret = v
+	}
+	return
+}
+
+func setJoined(obj interface{}, field *presets.FieldContext, ctx *web.EventContext) (err error) {
+	v := strings.Join(ctx.R.Form[field.FormKey], ",")
+	switch ep := obj.(*WebhookEndpoint); field.Name {
+	case "Models":
+		ep.Models = v
+	case "Events":
+		ep.Events = v
+	}
+	return
+}
@@ -0,0 +1,96 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature"
+
+	maxResponseBody = 1024
+)
+
+// Sign returns the signature of the body in the X-Webhook-Signature header, which is "sha256=" and the hex
+// of the HMAC-SHA256 of the body with the secret of the endpoint.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of the request body, for the receivers written in Go.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// attempt sends the delivery once and logs the result, the failed delivery is marked as retrying with
+// the time of the next attempt until it reaches the max attempts. d is nil if the delivery is not found.
+func (b *Builder) attempt(c context.Context, id uint) (d *WebhookDelivery, err error) {
+	d = &WebhookDelivery{}
+	if err = b.db.First(d, id).Error; err != nil {
+		return nil, err
+	}
+
+	d.Attempts++
+	ep := &WebhookEndpoint{}
+	if err = b.db.First(ep, d.EndpointID).Error; err == nil {
+		d.ResponseCode, d.ResponseBody, err = b.send(c, ep, d)
+	}
+
+	now := time.Now()
+	d.NextRetryAt = nil
+	if err == nil {
+		d.Status = DeliveryStatusSucceeded
+		d.Error = ""
+		d.DeliveredAt = &now
+	} else {
+		d.Error = err.Error()
+		d.Status = DeliveryStatusFailed
+		if d.Attempts < b.maxAttempts {
+			next := now.Add(b.backoff(d.Attempts))
+			d.Status = DeliveryStatusRetrying
+			d.NextRetryAt = &next
+		}
+	}
+	if sErr := b.db.Save(d).Error; sErr != nil {
+		return d, sErr
+	}
+	return
+}
+
+func (b *Builder) send(c context.Context, ep *WebhookEndpoint, d *WebhookDelivery) (code int, body string, err error) {
+	req, err := http.NewRequestWithContext(c, http.MethodPost, ep.URL, strings.NewReader(d.Payload))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderDelivery, fmt.Sprint(d.ID))
+	if ep.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(ep.Secret, []byte(d.Payload)))
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	bs, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	code, body = resp.StatusCode, string(bs)
+	if code < 200 || code >= 300 {
+		err = errors.New(resp.Status)
+	}
+	return
+}
@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"net/http"
+
+	"github.com/qor5/x/i18n"
+)
+
+const I18nWebhookKey i18n.ModuleKey = "I18nWebhookKey"
+
+type Messages struct {
+	InvalidURL              string
+	AllModels               string
+	AllEvents               string
+	SuccessfullyRedelivered string
+	SuccessfullySentTest    string
+	SecretCopyHint          string
+	SecretKeepHint          string
+}
+
+var Messages_en_US = &Messages{
+	InvalidURL:              "URL must be an absolute http or https URL",
+	AllModels:               "All models if empty",
+	AllEvents:               "All events if empty",
+	SuccessfullyRedelivered: "Successfully Redelivered",
+	SuccessfullySentTest:    "Successfully Sent the Test Event",
+	SecretCopyHint:          "Copy the secret to verify the signatures, it is hidden once the webhook is created",
+	SecretKeepHint:          "Leave empty to keep the current secret",
+}
+
+var Messages_zh_CN = &Messages{
+	InvalidURL:              "URL 必须是完整的 http 或 https 地址",
+	AllModels:               "为空时为所有模型",
+	AllEvents:               "为空时为所有事件",
+	SuccessfullyRedelivered: "成功重新发送",
+	SuccessfullySentTest:    "成功发送测试事件",
+	SecretCopyHint:          "请复制密钥以验证签名，创建后将不再显示",
+	SecretKeepHint:          "为空时保留当前密钥",
+}
+
+var Messages_ja_JP = &Messages{
+	InvalidURL:              "URL は http または https の完全な URL である必要があります",
+	AllModels:               "空の場合はすべてのモデル",
+	AllEvents:               "空の場合はすべてのイベント",
+	SuccessfullyRedelivered: "再送信に成功しました",
+	SuccessfullySentTest:    "テストイベントの送信に成功しました",
+	SecretCopyHint:          "署名の検証のためにシークレットをコピーしてください。作成後は表示されません",
+	SecretKeepHint:          "空の場合は現在のシークレットを維持します",
+}
+
+func getMessages(r *http.Request) *Messages {
+	return i18n.MustGetModuleMessages(r, I18nWebhookKey, Messages_en_US).(*Messages)
+}
@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusRetrying  = "retrying"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusFailed    = "failed"
+)
+
+// WebhookEndpoint receives the events of the models, Models and Events are comma separated,
+// and the empty value subscribes to all of them.
+type WebhookEndpoint struct {
+	gorm.Model
+
+	Name   string
+	URL    string
+	Secret string
+	Models string
+	Events string
+	Active bool
+}
+
+func (ep *WebhookEndpoint) Subscribes(model string, event string) bool {
+	return ep.Active && contains(ep.Models, model) && contains(ep.Events, event)
+}
+
+func contains(list string, v string) bool {
+	if strings.TrimSpace(list) == "" {
+		return true
+	}
+	for _, s := range strings.Split(list, ",") {
+		if strings.TrimSpace(s) == v {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery logs the request of the event to the endpoint, it is retried with the same payload until
+// it succeeds or the attempts reach the max attempts of the Builder.
+type WebhookDelivery struct {
+	gorm.Model
+
+	EndpointID   uint `gorm:"index"`
+	Event        string
+	ModelName    string `gorm:"index"`
+	RecordID     string `gorm:"index"`
+	Payload      string
+	Status       string `gorm:"index"`
+	Attempts     int
+	ResponseCode int
+	ResponseBody string
+	Error        string
+	NextRetryAt  *time.Time
+	DeliveredAt  *time.Time
+}
+
+// DeliveryArgs is the argument of the delivery job, the job runs at RunAt for the retries.
+type DeliveryArgs struct {
+	DeliveryID uint
+	RunAt      *time.Time
+}
+
+func (args *DeliveryArgs) GetScheduleTime() *time.Time {
+	return args.RunAt
+}
+
+func (args *DeliveryArgs) SetScheduleTime(t *time.Time) {
+	args.RunAt = t
+}
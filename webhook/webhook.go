@@ -0,0 +1,306 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/qor5/admin/activity"
+	"github.com/qor5/admin/presets"
+	"github.com/qor5/admin/presets/gorm2op"
+	"github.com/qor5/admin/worker"
+	vx "github.com/qor5/ui/vuetifyx"
+	"github.com/qor5/web"
+	"gorm.io/gorm"
+)
+
+const (
+	EventCreated     = "created"
+	EventUpdated     = "updated"
+	EventDeleted     = "deleted"
+	EventPublished   = "published"
+	EventUnpublished = "unpublished"
+	EventPing        = "ping"
+
+	// HookOrder is the order of the hooks of the webhooks, they run after the activity records the changes.
+	HookOrder = activity.HookOrder + 100
+
+	DeliveryJobName = "Webhook Delivery"
+)
+
+var Events = []string{EventCreated, EventUpdated, EventDeleted, EventPublished, EventUnpublished}
+
+// BackoffFunc returns the delay before the next attempt of the failed delivery.
+type BackoffFunc func(attempt int) time.Duration
+
+// DefaultBackoff doubles the delay from 30 seconds for every attempt.
+func DefaultBackoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	return time.Duration(1<<uint(attempt-1)) * 30 * time.Second
+}
+
+// Payload is the JSON body of the deliveries, Diffs are the changes of the updates
+// if the model is registered to the activity.
+type Payload struct {
+	Event      string          `json:"event"`
+	Model      string          `json:"model"`
+	RecordID   string          `json:"record_id"`
+	Record     interface{}     `json:"record,omitempty"`
+	Diffs      []activity.Diff `json:"diffs,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+type Builder struct {
+	db          *gorm.DB
+	wb          *worker.Builder
+	ab          *activity.ActivityBuilder
+	client      *http.Client
+	maxAttempts int
+	backoff     BackoffFunc
+	testMode    bool
+	models      []string
+}
+
+func New(db *gorm.DB) *Builder {
+	if err := db.AutoMigrate(&WebhookEndpoint{}, &WebhookDelivery{}); err != nil {
+		panic(err)
+	}
+
+	return &Builder{
+		db:          db,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		maxAttempts: 5,
+		backoff:     DefaultBackoff,
+	}
+}
+
+// Worker sends the deliveries by the jobs of the worker, and the failed ones are retried by the scheduled jobs
+// with the backoff. Without the worker, the deliveries are sent once in the requests that change the records,
+// after the changes are committed.
+func (b *Builder) Worker(wb *worker.Builder) *Builder {
+	b.wb = wb
+	wb.NewJob(DeliveryJobName).
+		Resource(&DeliveryArgs{}).
+		Handler(b.deliveryJob)
+	return b
+}
+
+// Activity adds the diffs of the activity to the payloads of the updates.
+func (b *Builder) Activity(ab *activity.ActivityBuilder) *Builder {
+	b.ab = ab
+	return b
+}
+
+func (b *Builder) HTTPClient(v *http.Client) *Builder {
+	b.client = v
+	return b
+}
+
+func (b *Builder) MaxAttempts(v int) *Builder {
+	b.maxAttempts = v
+	return b
+}
+
+func (b *Builder) Backoff(v BackoffFunc) *Builder {
+	b.backoff = v
+	return b
+}
+
+// TestMode sends the deliveries in the requests without the worker and retries them without the backoff,
+// like for the tests that post to the httptest servers.
+func (b *Builder) TestMode(v bool) *Builder {
+	b.testMode = v
+	return b
+}
+
+// RegisterModels sends the created, updated, deleted, published and unpublished events of the models
+// to the endpoints that subscribe to them.
+func (b *Builder) RegisterModels(mbs ...*presets.ModelBuilder) *Builder {
+	for _, mb := range mbs {
+		mb := mb
+		name := modelName(mb)
+		b.models = append(b.models, name)
+
+		mb.Hook(presets.AfterSave, HookOrder, func(old interface{}, obj interface{}, ctx *web.EventContext) (err error) {
+			if old == nil {
+				return b.fire(ctx.R.Context(), mb, EventCreated, nil, obj)
+			}
+			return b.fire(ctx.R.Context(), mb, EventUpdated, old, obj)
+		})
+		mb.Hook(presets.AfterDelete, HookOrder, func(old interface{}, obj interface{}, ctx *web.EventContext) (err error) {
+			if old == nil {
+				return
+			}
+			return b.fire(ctx.R.Context(), mb, EventDeleted, old, nil)
+		})
+		mb.Hook(presets.AfterPublish, HookOrder, func(old interface{}, obj interface{}, ctx *web.EventContext) (err error) {
+			return b.fire(ctx.R.Context(), mb, EventPublished, nil, obj)
+		})
+		mb.Hook(presets.AfterUnpublish, HookOrder, func(old interface{}, obj interface{}, ctx *web.EventContext) (err error) {
+			return b.fire(ctx.R.Context(), mb, EventUnpublished, nil, obj)
+		})
+	}
+	return b
+}
+
+func modelName(mb *presets.ModelBuilder) string {
+	return reflect.Indirect(reflect.ValueOf(mb.NewModel())).Type().Name()
+}
+
+func (b *Builder) fire(c context.Context, mb *presets.ModelBuilder, event string, old interface{}, obj interface{}) (err error) {
+	name := modelName(mb)
+	db := b.dbFrom(c)
+	var endpoints []*WebhookEndpoint
+	if err = db.Where("active = ?", true).Find(&endpoints).Error; err != nil {
+		return
+	}
+	var subscribed []*WebhookEndpoint
+	for _, ep := range endpoints {
+		if ep.Subscribes(name, event) {
+			subscribed = append(subscribed, ep)
+		}
+	}
+	if len(subscribed) == 0 {
+		return
+	}
+
+	record := obj
+	if record == nil {
+		record = old
+	}
+	payload := Payload{
+		Event:      event,
+		Model:      name,
+		RecordID:   vx.ObjectID(record),
+		Record:     record,
+		OccurredAt: time.Now(),
+	}
+	if b.ab != nil && old != nil && obj != nil {
+		if amb, ok := b.ab.GetModelBuilder(mb); ok {
+			if payload.Diffs, err = amb.Diff(old, obj); err != nil {
+				return
+			}
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	// the deliveries are written in the transaction of the change, and sent after it is committed,
+	// so that the rolled back changes are not notified
+	for _, ep := range subscribed {
+		d := &WebhookDelivery{
+			EndpointID: ep.ID,
+			Event:      event,
+			ModelName:  name,
+			RecordID:   payload.RecordID,
+			Payload:    string(body),
+			Status:     DeliveryStatusPending,
+		}
+		if err = db.Create(d).Error; err != nil {
+			return
+		}
+		gorm2op.AfterCommit(c, func() {
+			if eErr := b.enqueue(c, d.ID, nil); eErr != nil {
+				log.Println(eErr)
+			}
+		})
+	}
+	return
+}
+
+func (b *Builder) dbFrom(c context.Context) *gorm.DB {
+	if tx, ok := gorm2op.TxFromContext(c); ok {
+		return tx
+	}
+	return b.db
+}
+
+// Redeliver sends the payload of the delivery to its endpoint again as a new delivery.
+func (b *Builder) Redeliver(c context.Context, id uint) (d *WebhookDelivery, err error) {
+	old := &WebhookDelivery{}
+	if err = b.db.First(old, id).Error; err != nil {
+		return
+	}
+	d = &WebhookDelivery{
+		EndpointID: old.EndpointID,
+		Event:      old.Event,
+		ModelName:  old.ModelName,
+		RecordID:   old.RecordID,
+		Payload:    old.Payload,
+		Status:     DeliveryStatusPending,
+	}
+	if err = b.db.Create(d).Error; err != nil {
+		return
+	}
+	err = b.enqueue(c, d.ID, nil)
+	return
+}
+
+// Ping sends the ping event to the endpoint, to check its URL and secret.
+func (b *Builder) Ping(c context.Context, endpointID uint) (d *WebhookDelivery, err error) {
+	body, err := json.Marshal(Payload{Event: EventPing, OccurredAt: time.Now()})
+	if err != nil {
+		return
+	}
+	d = &WebhookDelivery{
+		EndpointID: endpointID,
+		Event:      EventPing,
+		Payload:    string(body),
+		Status:     DeliveryStatusPending,
+	}
+	if err = b.db.Create(d).Error; err != nil {
+		return
+	}
+	err = b.enqueue(c, d.ID, nil)
+	return
+}
+
+func (b *Builder) enqueue(c context.Context, id uint, runAt *time.Time) (err error) {
+	if b.testMode || b.wb == nil {
+		return b.deliverNow(c, id)
+	}
+	_, err = b.wb.AddJob(c, DeliveryJobName, &DeliveryArgs{DeliveryID: id, RunAt: runAt})
+	return
+}
+
+// deliverNow sends the delivery in the request, it is retried immediately in the test mode, and the failure
+// of the endpoint is logged in the delivery and doesn't fail the change of the record.
+func (b *Builder) deliverNow(c context.Context, id uint) (err error) {
+	for {
+		var d *WebhookDelivery
+		if d, err = b.attempt(c, id); d == nil {
+			return err
+		}
+		if !b.testMode || d.Status != DeliveryStatusRetrying {
+			return nil
+		}
+	}
+}
+
+func (b *Builder) deliveryJob(c context.Context, job worker.QorJobInterface) (err error) {
+	ji, err := job.GetJobInfo()
+	if err != nil {
+		return
+	}
+	args := ji.Argument.(*DeliveryArgs)
+
+	d, err := b.attempt(c, args.DeliveryID)
+	if d == nil {
+		return
+	}
+	job.AddLogf("Attempt %d to endpoint %d: %s", d.Attempts, d.EndpointID, d.Status)
+	if d.Status == DeliveryStatusRetrying {
+		job.AddLogf("Retry at %s", d.NextRetryAt.Format(time.RFC3339))
+		if rErr := b.enqueue(c, d.ID, d.NextRetryAt); rErr != nil {
+			return rErr
+		}
+	}
+	return
+}
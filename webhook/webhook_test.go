@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/qor5/admin/activity"
+	"github.com/qor5/admin/presets"
+	"github.com/qor5/admin/presets/gorm2op"
+	"github.com/qor5/web"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var db *gorm.DB
+
+type WebhookProduct struct {
+	ID    uint
+	Name  string
+	Price int
+}
+
+func init() {
+	var err error
+	db, err = gorm.Open(postgres.Open(os.Getenv("DBURL")), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	if err = db.AutoMigrate(&WebhookProduct{}); err != nil {
+		panic(err)
+	}
+}
+
+type received struct {
+	event     string
+	signature string
+	payload   Payload
+	body      []byte
+}
+
+func TestDeliveries(t *testing.T) {
+	db.Exec("DELETE FROM webhook_endpoints")
+	db.Exec("DELETE FROM webhook_deliveries")
+	db.Exec("DELETE FROM webhook_products")
+
+	var (
+		mu       sync.Mutex
+		requests []received
+		failures = 1
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		rec := received{event: r.Header.Get(HeaderEvent), signature: r.Header.Get(HeaderSignature), body: body}
+		json.Unmarshal(body, &rec.payload)
+		requests = append(requests, rec)
+	}))
+	defer srv.Close()
+
+	pb := presets.New().DataOperator(gorm2op.DataOperator(db))
+	mb := pb.Model(&WebhookProduct{})
+	ab := activity.New(pb, db)
+	ab.RegisterModel(mb)
+	b := New(db).Activity(ab).TestMode(true).RegisterModels(mb)
+
+	db.Create(&WebhookEndpoint{URL: srv.URL, Secret: "s3cret", Models: "WebhookProduct", Events: "created,updated", Active: true})
+	db.Create(&WebhookEndpoint{URL: srv.URL, Secret: "other", Events: EventDeleted, Active: true})
+
+	ctx := &web.EventContext{R: httptest.NewRequest("POST", "/webhook-products", nil)}
+	eb := mb.Editing()
+	p := &WebhookProduct{Name: "Shoes", Price: 10}
+	if err := eb.Save(p, "", ctx); err != nil {
+		t.Fatal(err)
+	}
+	p.Price = 20
+	if err := eb.Save(p, "1", ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("requests = %d, want 2", len(requests))
+	}
+	if r := requests[0]; r.event != EventCreated || r.payload.Model != "WebhookProduct" || r.payload.RecordID != "1" {
+		t.Errorf("the created request is %+v", r)
+	}
+	if r := requests[1]; r.event != EventUpdated || len(r.payload.Diffs) != 1 || r.payload.Diffs[0].Now != "20" {
+		t.Errorf("the updated request is %+v", r)
+	}
+	for _, r := range requests {
+		if !Verify("s3cret", r.body, r.signature) {
+			t.Errorf("the signature %s is not verified", r.signature)
+		}
+	}
+
+	var first WebhookDelivery
+	db.Order("id").First(&first)
+	if first.Status != DeliveryStatusSucceeded || first.Attempts != 2 {
+		t.Errorf("the first delivery is %s with %d attempts, want succeeded with 2 attempts", first.Status, first.Attempts)
+	}
+
+	if _, err := b.Redeliver(ctx.R.Context(), first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 3 || string(requests[2].body) != string(requests[0].body) {
+		t.Errorf("the redelivered request is not the same as the first one")
+	}
+
+	failures = 10
+	if err := eb.Delete(&WebhookProduct{}, "1", ctx); err != nil {
+		t.Fatal(err)
+	}
+	var last WebhookDelivery
+	db.Order("id DESC").First(&last)
+	if last.Event != EventDeleted || last.Status != DeliveryStatusFailed || last.Attempts != 5 || last.ResponseCode != http.StatusInternalServerError {
+		t.Errorf("the deleted delivery is %+v", last)
+	}
+
+	// the rolled back changes are not notified
+	mb.Hook(presets.AfterSave, HookOrder+1, func(old interface{}, obj interface{}, ctx *web.EventContext) (err error) {
+		return errors.New("rolled back")
+	})
+	sent := len(requests)
+	if err := eb.Save(&WebhookProduct{Name: "Boots"}, "", ctx); err == nil {
+		t.Fatal("the save is not rolled back")
+	}
+	var count int64
+	db.Model(&WebhookDelivery{}).Where("payload LIKE ?", "%Boots%").Count(&count)
+	if count != 0 || len(requests) != sent {
+		t.Errorf("the rolled back change is notified, deliveries = %d, requests = %d", count, len(requests)-sent)
+	}
+}
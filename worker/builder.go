@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"net/url"
 	"path"
@@ -430,22 +431,49 @@ func (b *Builder) createJob(ctx *web.EventContext, qorJob *QorJob) (j *QorJob, e
 		}
 	}
 
-	err = b.db.Transaction(func(tx *gorm.DB) error {
+	return b.addJob(ctx.R.Context(), ctx.R, jb, args, context)
+}
+
+// AddJob creates the job with the args and adds it to the queue, for the jobs that are not created
+// from the admin, like the ones that are fired by the changes of the records.
+func (b *Builder) AddJob(ctx context.Context, jobName string, args interface{}) (j *QorJob, err error) {
+	jb := b.getJobBuilder(jobName)
+	if jb == nil {
+		return nil, fmt.Errorf("no job %s", jobName)
+	}
+	return b.addJob(ctx, nil, jb, args, map[string]interface{}{})
+}
+
+// addJob creates the job and its instance in a transaction, and adds the instance to the queue after the commit,
+// so that the worker can read it. The job is deleted if it can't be queued, instead of being left new forever.
+func (b *Builder) addJob(ctx context.Context, r *http.Request, jb *JobBuilder, args interface{}, jobContext interface{}) (j *QorJob, err error) {
+	var inst *QorJobInstance
+	err = b.db.Transaction(func(tx *gorm.DB) (err error) {
 		j = &QorJob{
-			Job:    qorJob.Job,
+			Job:    jb.name,
 			Status: JobStatusNew,
 		}
-		err = b.db.Create(j).Error
-		if err != nil {
-			return err
-		}
-		var inst *QorJobInstance
-		inst, err = jb.newJobInstance(ctx.R, j.ID, qorJob.Job, args, context)
-		if err != nil {
-			return err
+		if err = tx.Create(j).Error; err != nil {
+			return
 		}
-		return b.q.Add(ctx.R.Context(), inst)
+		inst, err = jb.newJobInstance(tx, r, j.ID, jb.name, args, jobContext)
+		return
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = b.q.Add(ctx, inst); err != nil {
+		if dErr := b.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Unscoped().Where("qor_job_id = ?", j.ID).Delete(&QorJobInstance{}).Error; err != nil {
+				return err
+			}
+			return tx.Unscoped().Delete(j).Error
+		}); dErr != nil {
+			log.Println(dErr)
+		}
+		return nil, err
+	}
 	return
 }
 
@@ -552,7 +580,7 @@ func (b *Builder) eventRerunJob(ctx *web.EventContext) (er web.EventResponse, er
 		return er, errors.New("job is not done")
 	}
 
-	inst, err := jb.newJobInstance(ctx.R, qorJobID, qorJobName, old.Args, old.Context)
+	inst, err := jb.newJobInstance(b.db, ctx.R, qorJobID, qorJobName, old.Args, old.Context)
 	if err != nil {
 		return er, err
 	}
@@ -625,7 +653,7 @@ func (b *Builder) eventUpdateJob(ctx *web.EventContext) (er web.EventResponse, e
 		return er, nil
 	}
 
-	newInst, err := jb.newJobInstance(ctx.R, qorJobID, qorJobName, newArgs, contexts)
+	newInst, err := jb.newJobInstance(b.db, ctx.R, qorJobID, qorJobName, newArgs, contexts)
 	if err != nil {
 		return er, err
 	}
@@ -165,7 +165,9 @@ func (jb *JobBuilder) getJobInstance(qorJobID uint) (*QorJobInstance, error) {
 	return inst, nil
 }
 
+// newJobInstance creates the instance of the job by db, which can be the transaction that creates the job.
 func (jb *JobBuilder) newJobInstance(
+	db *gorm.DB,
 	r *http.Request,
 	qorJobID uint,
 	qorJobName string,
@@ -201,15 +203,20 @@ func (jb *JobBuilder) newJobInstance(
 		Job:      qorJobName,
 		Status:   JobStatusNew,
 	}
-	if jb.b.getCurrentUserIDFunc != nil {
+	if jb.b.getCurrentUserIDFunc != nil && r != nil {
 		inst.Operator = jb.b.getCurrentUserIDFunc(r)
 	}
-	err := jb.b.db.Create(&inst).Error
+	err := db.Create(&inst).Error
 	if err != nil {
 		return nil, err
 	}
 
-	return jb.getJobInstance(qorJobID)
+	created, err := getModelQorJobInstance(db, qorJobID)
+	if err != nil {
+		return nil, err
+	}
+	created.jb = jb
+	return created, nil
 }
 
 type QueJobInterface interface {